@@ -2,12 +2,16 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/rtzll/tldw/internal"
+	"github.com/rtzll/tldw/internal/cache"
+	"github.com/rtzll/tldw/internal/metrics"
 	openaiadapter "github.com/rtzll/tldw/internal/openai"
 	"github.com/rtzll/tldw/internal/process"
 	"github.com/rtzll/tldw/internal/store"
 	"github.com/rtzll/tldw/internal/tldw"
+	"github.com/rtzll/tldw/internal/watch"
 	ytdlpadapter "github.com/rtzll/tldw/internal/ytdlp"
 )
 
@@ -21,18 +25,28 @@ func (l cliLogSink) Printf(format string, args ...any) {
 	}
 }
 
+// metricsObserver counts the engine's cache lookups in the default registry.
+type metricsObserver struct{}
+
+func (metricsObserver) CacheLookup(kind string, hit bool) {
+	metrics.CacheLookup(kind, hit)
+}
+
 func newEngine(config *internal.Config) (*tldw.Engine, error) {
-	return buildEngine(config, cliLogSink{config: config})
+	return buildEngine(config, cliLogSink{config: config}, store.NewFile(config.TranscriptsDir), nil)
 }
 
 type silentLogSink struct{}
 
 func (silentLogSink) Printf(string, ...any) {}
 
-func buildEngine(config *internal.Config, log tldw.LogSink) (*tldw.Engine, error) {
-	runner := &process.CommandRunner{}
+// buildEngine composes the engine. wrapAI, when set, wraps the OpenAI adapter,
+// as the MCP server does to summarize through clients when there is no key.
+func buildEngine(config *internal.Config, log tldw.LogSink, files *store.File, wrapAI func(tldw.AIAdapter) tldw.AIAdapter) (*tldw.Engine, error) {
+	runner := process.NewRetryRunner(&process.CommandRunner{}, retryPolicy(config))
 	audio := openaiadapter.NewAudio(runner, config.TempDir, config.Verbose)
 	youtube := ytdlpadapter.NewYouTube(config.TranscriptsDir, config.CacheDir, config.Verbose, config.Quiet)
+	youtube.SetRunner(runner)
 	ai, err := openaiadapter.NewAIWithKey(config.OpenAIAPIKey, audio, openaiadapter.Config{
 		Model: config.TLDRModel, WhisperLimit: internal.WhisperLimit, Timeout: config.SummaryTimeout,
 		Verbose: config.Verbose, Quiet: config.Quiet,
@@ -41,17 +55,58 @@ func buildEngine(config *internal.Config, log tldw.LogSink) (*tldw.Engine, error
 		return nil, fmt.Errorf("configuring OpenAI adapter: %w", err)
 	}
 	youtube.SetLogSink(log)
+	youtube.SetCachePolicy(cacheDir(config), cachePolicy(config))
+	youtube.SetOptions(ytdlpOptions(config))
 	ai.SetLogSink(log)
+	var summarizer tldw.AIAdapter = ai
+	if wrapAI != nil {
+		summarizer = wrapAI(ai)
+	}
 	return tldw.NewEngine(
 		tldw.Config{
 			WhisperTimeout: config.WhisperTimeout,
 		},
 		tldw.Dependencies{
-			Video:   youtube,
-			Store:   store.NewFile(config.TranscriptsDir),
-			AI:      ai,
-			Prompts: internal.NewPromptManager(config.ConfigDir, config.Prompt),
-			Log:     log,
+			Video:    youtube,
+			Store:    files,
+			AI:       summarizer,
+			Prompts:  internal.NewPromptManager(config.ConfigDir, config.Prompt),
+			Log:      log,
+			Observer: metricsObserver{},
 		},
 	)
 }
+
+func cacheDir(config *internal.Config) cache.Dir {
+	return cache.Dir{Path: config.CacheDir, TempDir: config.TempDir}
+}
+
+func watchList(config *internal.Config) watch.List {
+	return watch.List{Path: filepath.Join(config.DataDir, "watchlist.json")}
+}
+
+func ytdlpOptions(config *internal.Config) ytdlpadapter.Options {
+	return ytdlpadapter.Options{
+		CookiesFile:        config.YtDLP.Cookies,
+		CookiesFromBrowser: config.YtDLP.CookiesFromBrowser,
+		Proxy:              config.YtDLP.Proxy,
+		ExtractorArgs:      config.YtDLP.ExtractorArgs,
+		SleepInterval:      config.YtDLP.SleepInterval,
+		MaxSleepInterval:   config.YtDLP.MaxSleepInterval,
+		ExtraArgs:          config.YtDLP.ExtraArgs,
+	}
+}
+
+func retryPolicy(config *internal.Config) process.RetryPolicy {
+	return process.RetryPolicy{
+		MaxAttempts:    config.CommandRetry.Attempts,
+		BaseDelay:      config.CommandRetry.Delay,
+		RateLimitDelay: config.CommandRetry.RateLimitDelay,
+		MaxDelay:       config.CommandRetry.MaxDelay,
+		MaxPerHost:     config.CommandRetry.MaxPerHost,
+	}
+}
+
+func cachePolicy(config *internal.Config) cache.Policy {
+	return cache.Policy{MaxSize: config.CacheMaxSize, MaxAge: config.CacheMaxAge}
+}
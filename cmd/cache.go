@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rtzll/tldw/internal/cache"
+)
+
+type cacheSettings func() (cache.Dir, cache.Policy)
+
+func newCacheCommand(settings cacheSettings, now func() time.Time) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and clean downloaded audio and temporary files",
+		Long: `Inspect and clean the cache directory.
+
+Downloaded audio is reused for repeated Whisper runs. After each download the
+least recently used files are evicted to stay within cache_max_size_mb and
+cache_max_age. Transcripts and metadata live in the library, not the cache.`,
+		Example: `  # Show cache usage
+  tldw cache du
+
+  # Remove all cached audio and leftovers
+  tldw cache clean
+
+  # Remove cached audio not used for a week
+  tldw cache clean --kind audio --older-than 7d`,
+	}
+	command.AddCommand(newCacheUsageCommand(settings), newCacheCleanCommand(settings, now))
+	return command
+}
+
+func newCacheUsageCommand(settings cacheSettings) *cobra.Command {
+	return &cobra.Command{
+		Use:   "du",
+		Short: "Show disk usage of the cache",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			dir, policy := settings()
+			usages, err := dir.Usage()
+			if err != nil {
+				return err
+			}
+			return writeCacheUsage(cmd.OutOrStdout(), dir, policy, usages)
+		},
+	}
+}
+
+func newCacheCleanCommand(settings cacheSettings, now func() time.Time) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "clean",
+		Short: "Remove cached audio, subtitles, and transcription chunks",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var options cache.CleanOptions
+			kindNames, err := cmd.Flags().GetStringSlice("kind")
+			if err != nil {
+				return err
+			}
+			for _, name := range kindNames {
+				kind, err := cache.ParseKind(name)
+				if err != nil {
+					return err
+				}
+				options.Kinds = append(options.Kinds, kind)
+			}
+			olderThan, err := cmd.Flags().GetString("older-than")
+			if err != nil {
+				return err
+			}
+			if olderThan != "" {
+				age, err := parseAge(olderThan)
+				if err != nil {
+					return err
+				}
+				options.Before = now().Add(-age)
+			}
+			if options.DryRun, err = cmd.Flags().GetBool("dry-run"); err != nil {
+				return err
+			}
+
+			dir, _ := settings()
+			removed, err := dir.Clean(options)
+			verb := "Removed"
+			if options.DryRun {
+				verb = "Would remove"
+			}
+			var total int64
+			for _, file := range removed {
+				total += file.Size
+			}
+			if _, writeErr := fmt.Fprintf(cmd.OutOrStdout(), "%s %d %s (%s)\n", verb, len(removed),
+				plural(len(removed), "file", "files"), formatBytes(total)); writeErr != nil {
+				return writeErr
+			}
+			return err
+		},
+	}
+	command.Flags().StringSlice("kind", nil, "Only remove audio, subtitles, chunks, or partial files")
+	command.Flags().String("older-than", "", "Only remove files not used for this long, e.g. 7d")
+	command.Flags().Bool("dry-run", false, "Only report what would be removed")
+	return command
+}
+
+func writeCacheUsage(writer io.Writer, dir cache.Dir, policy cache.Policy, usages []cache.Usage) error {
+	if _, err := fmt.Fprintf(writer, "Cache directory: %s\n\n", dir.Path); err != nil {
+		return err
+	}
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	var files int
+	var total int64
+	for _, usage := range usages {
+		files += usage.Files
+		total += usage.Size
+		if _, err := fmt.Fprintf(table, "%s\t%d %s\t%s\n", usage.Kind, usage.Files,
+			plural(usage.Files, "file", "files"), formatBytes(usage.Size)); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(table, "total\t%d %s\t%s\n", files, plural(files, "file", "files"), formatBytes(total)); err != nil {
+		return err
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	maxSize, maxAge := "unlimited", "unlimited"
+	if policy.MaxSize > 0 {
+		maxSize = formatBytes(policy.MaxSize)
+	}
+	if policy.MaxAge > 0 {
+		maxAge = formatStatsDuration(policy.MaxAge.Seconds())
+	}
+	_, err := fmt.Fprintf(writer, "\nLimits: %s, %s\n", maxSize, maxAge)
+	return err
+}
+
+var cacheCmd = newCacheCommand(func() (cache.Dir, cache.Policy) {
+	return cacheDir(config), cachePolicy(config)
+}, time.Now)
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rtzll/tldw/internal/feed"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+type feedApplicationFactory func() (feed.Source, error)
+
+func newFeedCommand(build feedApplicationFactory, now func() time.Time) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "feed",
+		Short: "Write cached summaries as an Atom feed",
+		Long: `Write the most recently generated summaries as an Atom feed.
+
+Each entry links to the video and carries its title, channel, publish date, and
+the summary rendered as HTML. Use tldw serve --feed to serve the same feed over
+HTTP.`,
+		Example: `  # Print a feed of the latest summaries
+  tldw feed
+
+  # Write one channel's summaries to a file
+  tldw feed --channel "CMU Database Group" --out cmu.xml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var query tldw.SummaryQuery
+			var err error
+			if query.Channel, err = cmd.Flags().GetString("channel"); err != nil {
+				return err
+			}
+			if query.Tag, err = cmd.Flags().GetString("tag"); err != nil {
+				return err
+			}
+			if query.Limit, err = cmd.Flags().GetInt("limit"); err != nil {
+				return err
+			}
+			out, err := cmd.Flags().GetString("out")
+			if err != nil {
+				return err
+			}
+			app, err := build()
+			if err != nil {
+				return fmt.Errorf("building application: %w", err)
+			}
+			summaries, err := app.RecentSummaries(query)
+			if err != nil {
+				return err
+			}
+			write := func(writer io.Writer) error {
+				return feed.Write(writer, query, summaries, now())
+			}
+			if out == "" {
+				return write(cmd.OutOrStdout())
+			}
+			return writeFileAtomically(out, write)
+		},
+	}
+	command.Flags().String("channel", "", "Only include videos whose channel contains this text")
+	command.Flags().String("tag", "", "Only include videos with this tag")
+	command.Flags().Int("limit", feed.DefaultLimit, "Maximum number of summaries (0 for all)")
+	command.Flags().String("out", "", "Write the feed to this file instead of stdout")
+	return command
+}
+
+var feedCmd = newFeedCommand(func() (feed.Source, error) {
+	return newEngine(config)
+}, time.Now)
+
+func init() {
+	rootCmd.AddCommand(feedCmd)
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rtzll/tldw/internal/feed"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+type feedApplicationStub struct {
+	query     tldw.SummaryQuery
+	summaries []tldw.StoredSummary
+}
+
+func (stub *feedApplicationStub) RecentSummaries(query tldw.SummaryQuery) ([]tldw.StoredSummary, error) {
+	stub.query = query
+	return stub.summaries, nil
+}
+
+func TestFeedCommandWritesFilteredAtomFeed(t *testing.T) {
+	stub := &feedApplicationStub{summaries: []tldw.StoredSummary{{
+		VideoID: "dQw4w9WgXcQ", Metadata: &tldw.VideoMetadata{Title: "B-trees"}, Markdown: "**Pages split**",
+		SummarizedAt: time.Date(2026, time.July, 3, 0, 0, 0, 0, time.UTC),
+	}}}
+	command := newFeedCommand(func() (feed.Source, error) { return stub, nil }, time.Now)
+	var output bytes.Buffer
+	command.SetOut(&output)
+	command.SetArgs([]string{"--channel", "CMU", "--tag", "postgres", "--limit", "5"})
+
+	if err := command.Execute(); err != nil {
+		t.Fatalf("feed error = %v", err)
+	}
+	if want := (tldw.SummaryQuery{Channel: "CMU", Tag: "postgres", Limit: 5}); stub.query != want {
+		t.Fatalf("feed query = %+v, want %+v", stub.query, want)
+	}
+	for _, fragment := range []string{`<feed xmlns="http://www.w3.org/2005/Atom">`, "<title>B-trees</title>", "&lt;strong&gt;Pages split&lt;/strong&gt;"} {
+		if !strings.Contains(output.String(), fragment) {
+			t.Fatalf("feed output %q does not contain %q", output.String(), fragment)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -18,6 +19,61 @@ func addOpenAIFlags(cmd *cobra.Command) {
 	cmd.Flags().StringP("prompt", "p", "", "Custom prompt (string or file path)")
 }
 
+// addYtDLPFlags adds the flags that override the ytdlp_* settings, e.g. to
+// fetch an age-restricted video with browser cookies.
+func addYtDLPFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	flags.String("cookies", "", "Netscape cookies file for yt-dlp")
+	flags.String("cookies-from-browser", "", "Browser to load yt-dlp cookies from, e.g. firefox or chrome:Profile 1")
+	flags.String("proxy", "", "Proxy URL for yt-dlp, e.g. socks5://127.0.0.1:1080")
+	flags.String("extractor-args", "", "yt-dlp extractor arguments replacing the default YouTube policy")
+	flags.Duration("sleep-interval", 0, "Minimum pause before each yt-dlp request")
+	flags.Duration("max-sleep-interval", 0, "Maximum pause before each yt-dlp request")
+	flags.StringArray("ytdlp-arg", nil, "Extra argument for every yt-dlp command (repeatable)")
+}
+
+// applyYtDLPFlags overrides the ytdlp_* settings with the flags that were set.
+// Extra arguments from flags follow those from the config file.
+func applyYtDLPFlags(cmd *cobra.Command, config *internal.Config) error {
+	flags := cmd.Flags()
+	for name, target := range map[string]*string{
+		"cookies":              &config.YtDLP.Cookies,
+		"cookies-from-browser": &config.YtDLP.CookiesFromBrowser,
+		"proxy":                &config.YtDLP.Proxy,
+		"extractor-args":       &config.YtDLP.ExtractorArgs,
+	} {
+		if !flags.Changed(name) {
+			continue
+		}
+		value, err := flags.GetString(name)
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", name, err)
+		}
+		*target = value
+	}
+	for name, target := range map[string]*time.Duration{
+		"sleep-interval":     &config.YtDLP.SleepInterval,
+		"max-sleep-interval": &config.YtDLP.MaxSleepInterval,
+	} {
+		if !flags.Changed(name) {
+			continue
+		}
+		value, err := flags.GetDuration(name)
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", name, err)
+		}
+		*target = value
+	}
+	if flags.Changed("ytdlp-arg") {
+		args, err := flags.GetStringArray("ytdlp-arg")
+		if err != nil {
+			return fmt.Errorf("failed to get ytdlp-arg flag: %w", err)
+		}
+		config.YtDLP.ExtraArgs = append(config.YtDLP.ExtraArgs, args...)
+	}
+	return nil
+}
+
 func handlePromptFlag(cmd *cobra.Command, config *internal.Config) error {
 	promptFlag := cmd.Flags().Lookup("prompt")
 	if promptFlag == nil || !promptFlag.Changed {
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -38,3 +40,29 @@ func TestApplyOutputFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyYtDLPFlagsOverridesConfig(t *testing.T) {
+	cmd := &cobra.Command{}
+	addYtDLPFlags(cmd)
+	if err := cmd.ParseFlags([]string{"--proxy", "socks5://127.0.0.1:1080", "--sleep-interval", "3s", "--ytdlp-arg", "--geo-bypass"}); err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	config := &internal.Config{YtDLP: internal.YtDLPConfig{
+		CookiesFromBrowser: "firefox",
+		Proxy:              "http://127.0.0.1:8080",
+		ExtraArgs:          []string{"--no-check-certificates"},
+	}}
+
+	if err := applyYtDLPFlags(cmd, config); err != nil {
+		t.Fatalf("applyYtDLPFlags() error = %v", err)
+	}
+	want := internal.YtDLPConfig{
+		CookiesFromBrowser: "firefox",
+		Proxy:              "socks5://127.0.0.1:1080",
+		SleepInterval:      3 * time.Second,
+		ExtraArgs:          []string{"--no-check-certificates", "--geo-bypass"},
+	}
+	if !reflect.DeepEqual(config.YtDLP, want) {
+		t.Fatalf("YtDLP = %+v, want %+v", config.YtDLP, want)
+	}
+}
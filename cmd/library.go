@@ -0,0 +1,396 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+type libraryApplication interface {
+	Library(tldw.LibraryQuery) ([]tldw.LibraryEntry, error)
+	LibraryEntry(tldw.YouTubeRef) (*tldw.LibraryEntry, error)
+	RemoveFromLibrary(tldw.YouTubeRef) error
+	PruneLibrary(cutoff time.Time, dryRun bool) ([]tldw.LibraryEntry, error)
+	ExportLibrary(tldw.LibraryQuery, io.Writer) ([]tldw.LibraryEntry, error)
+	ImportLibrary(io.Reader, tldw.ImportPolicy) ([]tldw.LibraryImport, error)
+}
+
+type libraryApplicationFactory func() (libraryApplication, error)
+
+func newLibraryCommand(build libraryApplicationFactory, now func() time.Time) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "library",
+		Short: "Inspect and manage cached videos",
+		Long: `Inspect and manage the local library of cached transcripts and metadata.
+
+Each video's transcript, plain-text, metadata, and summary files are listed and
+removed together.`,
+		Example: `  # List cached videos, newest first
+  tldw library list
+
+  # Show everything cached for one video
+  tldw library show tAP1eZYEuKA
+
+  # Remove videos not written for 90 days
+  tldw library prune --older-than 90d
+
+  # Move the library to another machine
+  tldw library export --out lib.tar.gz
+  tldw library import lib.tar.gz`,
+	}
+	command.AddCommand(
+		newLibraryListCommand(build),
+		newLibraryShowCommand(build),
+		newLibraryRemoveCommand(build),
+		newLibraryPruneCommand(build, now),
+		newLibraryExportCommand(build),
+		newLibraryImportCommand(build),
+	)
+	return command
+}
+
+func newLibraryListCommand(build libraryApplicationFactory) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "list",
+		Short: "List cached videos",
+		Example: `  # List the ten longest videos from one channel
+  tldw library list --channel "CMU Database Group" --sort duration --limit 10
+
+  # Find videos by title, ID, or tag
+  tldw library list --search postgres
+
+  # Return machine-readable output
+  tldw library list --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			query, err := libraryQueryFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			app, err := build()
+			if err != nil {
+				return fmt.Errorf("building application: %w", err)
+			}
+			entries, err := app.Library(query)
+			if err != nil {
+				return err
+			}
+			jsonOutput, err := cmd.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return writeLibraryJSON(cmd.OutOrStdout(), entries)
+			}
+			return writeLibraryList(cmd.OutOrStdout(), entries)
+		},
+	}
+	command.Flags().String("channel", "", "Only list videos whose channel contains this text")
+	command.Flags().String("search", "", "Only list videos whose title, ID, or tags contain this text")
+	command.Flags().String("source", "", "Only list transcripts from captions or whisper")
+	command.Flags().String("sort", string(tldw.LibrarySortSeen), "Sort by seen, title, channel, duration, or size")
+	command.Flags().Bool("reverse", false, "Reverse the sort order")
+	command.Flags().Int("limit", 0, "Maximum number of videos to list (0 lists all)")
+	command.Flags().Bool("json", false, "Output the library as JSON")
+	return command
+}
+
+func newLibraryShowCommand(build libraryApplicationFactory) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "show [URL]",
+		Short: "Show cached metadata, transcript source, and files for a video",
+		Example: `  # Show a cached video
+  tldw library show tAP1eZYEuKA
+
+  # Return machine-readable output
+  tldw library show tAP1eZYEuKA --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ref, err := tldw.ParseVideoRef(args[0])
+			if err != nil {
+				return err
+			}
+			app, err := build()
+			if err != nil {
+				return fmt.Errorf("building application: %w", err)
+			}
+			entry, err := app.LibraryEntry(ref)
+			if err != nil {
+				return err
+			}
+			jsonOutput, err := cmd.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return writeLibraryJSON(cmd.OutOrStdout(), entry)
+			}
+			return writeLibraryEntry(cmd.OutOrStdout(), ref, *entry)
+		},
+	}
+	command.Flags().Bool("json", false, "Output the entry as JSON")
+	return command
+}
+
+func newLibraryRemoveCommand(build libraryApplicationFactory) *cobra.Command {
+	return &cobra.Command{
+		Use:     "rm [URL...]",
+		Aliases: []string{"remove"},
+		Short:   "Remove cached videos from the library",
+		Example: `  # Remove one cached video
+  tldw library rm tAP1eZYEuKA`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			refs := make([]tldw.YouTubeRef, 0, len(args))
+			for _, arg := range args {
+				ref, err := tldw.ParseVideoRef(arg)
+				if err != nil {
+					return err
+				}
+				refs = append(refs, ref)
+			}
+			app, err := build()
+			if err != nil {
+				return fmt.Errorf("building application: %w", err)
+			}
+			for _, ref := range refs {
+				if err := app.RemoveFromLibrary(ref); err != nil {
+					return err
+				}
+				if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Removed %s\n", ref.ID()); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func newLibraryPruneCommand(build libraryApplicationFactory, now func() time.Time) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove videos whose cache has not been written recently",
+		Example: `  # Remove videos not written for 90 days
+  tldw library prune --older-than 90d
+
+  # Preview what would be removed
+  tldw library prune --older-than 4w --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			olderThan, err := cmd.Flags().GetString("older-than")
+			if err != nil {
+				return err
+			}
+			age, err := parseAge(olderThan)
+			if err != nil {
+				return err
+			}
+			dryRun, err := cmd.Flags().GetBool("dry-run")
+			if err != nil {
+				return err
+			}
+			app, err := build()
+			if err != nil {
+				return fmt.Errorf("building application: %w", err)
+			}
+			pruned, err := app.PruneLibrary(now().Add(-age), dryRun)
+			if writeErr := writeLibraryPrune(cmd.OutOrStdout(), pruned, dryRun); writeErr != nil {
+				return writeErr
+			}
+			return err
+		},
+	}
+	command.Flags().String("older-than", "", "Age such as 90d, 4w, or 36h (required)")
+	command.Flags().Bool("dry-run", false, "Only report which videos would be removed")
+	_ = command.MarkFlagRequired("older-than")
+	return command
+}
+
+func libraryQueryFromFlags(cmd *cobra.Command) (tldw.LibraryQuery, error) {
+	var query tldw.LibraryQuery
+	var err error
+	if query.Channel, err = cmd.Flags().GetString("channel"); err != nil {
+		return query, err
+	}
+	if query.Search, err = cmd.Flags().GetString("search"); err != nil {
+		return query, err
+	}
+	source, err := cmd.Flags().GetString("source")
+	if err != nil {
+		return query, err
+	}
+	query.Source = tldw.TranscriptSource(strings.ToLower(strings.TrimSpace(source)))
+	sortBy, err := cmd.Flags().GetString("sort")
+	if err != nil {
+		return query, err
+	}
+	query.SortBy = tldw.LibrarySort(strings.ToLower(strings.TrimSpace(sortBy)))
+	if query.Reverse, err = cmd.Flags().GetBool("reverse"); err != nil {
+		return query, err
+	}
+	if query.Limit, err = cmd.Flags().GetInt("limit"); err != nil {
+		return query, err
+	}
+	return query, nil
+}
+
+// parseAge accepts Go durations plus whole days (d) and weeks (w).
+func parseAge(value string) (time.Duration, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return 0, fmt.Errorf("age is required, e.g. 90d")
+	}
+	units := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	var age time.Duration
+	if unit, ok := units[value[len(value)-1]]; ok {
+		count, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid age %q: use a whole number of days or weeks, e.g. 90d", value)
+		}
+		age = time.Duration(count) * unit
+	} else {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid age %q: use e.g. 90d, 4w, or 36h", value)
+		}
+		age = parsed
+	}
+	if age <= 0 {
+		return 0, fmt.Errorf("age must be positive: %q", value)
+	}
+	return age, nil
+}
+
+func writeLibraryJSON(writer io.Writer, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding library: %w", err)
+	}
+	_, err = fmt.Fprintln(writer, string(data))
+	return err
+}
+
+func writeLibraryList(writer io.Writer, entries []tldw.LibraryEntry) error {
+	if len(entries) == 0 {
+		_, err := fmt.Fprintln(writer, "No cached videos")
+		return err
+	}
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(table, "ID\tFIRST SEEN\tDURATION\tSOURCE\tSIZE\tCHANNEL\tTITLE"); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		duration := "-"
+		if entry.Duration() > 0 {
+			duration = formatStatsDuration(entry.Duration())
+		}
+		if _, err := fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", entry.VideoID,
+			entry.FirstSeenAt.Local().Format("2006-01-02"), duration, libraryTranscriptLabel(entry),
+			formatBytes(entry.Size()), valueOrDash(entry.Channel()), valueOrDash(entry.Title())); err != nil {
+			return err
+		}
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(writer, "\n%d %s\n", len(entries), plural(len(entries), "video", "videos"))
+	return err
+}
+
+func writeLibraryEntry(writer io.Writer, ref tldw.YouTubeRef, entry tldw.LibraryEntry) error {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "Video ID: %s\n", entry.VideoID)
+	fmt.Fprintf(&buf, "URL: %s\n", ref.URL())
+	if metadata := entry.Metadata; metadata != nil {
+		fmt.Fprintf(&buf, "Title: %s\n", metadata.Title)
+		fmt.Fprintf(&buf, "Channel: %s\n", valueOrDash(metadata.Channel))
+		if metadata.PublishedAt != "" {
+			fmt.Fprintf(&buf, "Published: %s\n", metadata.PublishedAt)
+		}
+		fmt.Fprintf(&buf, "Duration: %s\n", formatStatsDuration(metadata.Duration))
+		fmt.Fprintf(&buf, "Has Captions: %t\n", metadata.HasCaptions)
+	} else {
+		buf.WriteString("Metadata: not cached\n")
+	}
+	transcript := libraryTranscriptLabel(entry)
+	if entry.HasTimestamps {
+		transcript += " (timestamped)"
+	}
+	fmt.Fprintf(&buf, "Transcript: %s\n", transcript)
+	fmt.Fprintf(&buf, "First seen: %s\n", entry.FirstSeenAt.Local().Format(time.DateTime))
+	fmt.Fprintf(&buf, "Cached at: %s\n", entry.CachedAt.Local().Format(time.DateTime))
+	buf.WriteString("Files:\n")
+	for _, file := range entry.Files {
+		fmt.Fprintf(&buf, "  %s  %s\n", file.Name, formatBytes(file.Size))
+	}
+	fmt.Fprintf(&buf, "Total size: %s\n", formatBytes(entry.Size()))
+	_, err := io.WriteString(writer, buf.String())
+	return err
+}
+
+func writeLibraryPrune(writer io.Writer, entries []tldw.LibraryEntry, dryRun bool) error {
+	verb := "Removed"
+	if dryRun {
+		verb = "Would remove"
+	}
+	var total int64
+	for _, entry := range entries {
+		total += entry.Size()
+		if _, err := fmt.Fprintf(writer, "%s %s  %s  %s\n", verb, entry.VideoID,
+			entry.CachedAt.Local().Format("2006-01-02"), valueOrDash(entry.Title())); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(writer, "%s %d %s (%s)\n", verb, len(entries),
+		plural(len(entries), "video", "videos"), formatBytes(total))
+	return err
+}
+
+func libraryTranscriptLabel(entry tldw.LibraryEntry) string {
+	switch {
+	case !entry.HasTranscript:
+		return "none"
+	case entry.TranscriptSource == "":
+		return "unknown"
+	default:
+		return string(entry.TranscriptSource)
+	}
+}
+
+func valueOrDash(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "-"
+	}
+	return value
+}
+
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size) / unit
+	for _, suffix := range []string{"KiB", "MiB", "GiB"} {
+		if value < unit {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%.1f TiB", value)
+}
+
+var libraryCmd = newLibraryCommand(func() (libraryApplication, error) {
+	return newEngine(config)
+}, time.Now)
+
+func init() {
+	rootCmd.AddCommand(libraryCmd)
+}
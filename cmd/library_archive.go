@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+func newLibraryExportCommand(build libraryApplicationFactory) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "export",
+		Short: "Bundle cached videos into a portable archive",
+		Long: `Bundle transcripts, metadata, and summaries into a gzip-compressed tarball
+with a manifest. Metadata keeps its first-seen time, so stats survive the move.`,
+		Example: `  # Export the whole library
+  tldw library export --out lib.tar.gz
+
+  # Export one channel's videos
+  tldw library export --out cmu.tar.gz --channel "CMU Database Group"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			out, err := cmd.Flags().GetString("out")
+			if err != nil {
+				return err
+			}
+			var query tldw.LibraryQuery
+			if query.Channel, err = cmd.Flags().GetString("channel"); err != nil {
+				return err
+			}
+			if query.Search, err = cmd.Flags().GetString("search"); err != nil {
+				return err
+			}
+			source, err := cmd.Flags().GetString("source")
+			if err != nil {
+				return err
+			}
+			query.Source = tldw.TranscriptSource(strings.ToLower(strings.TrimSpace(source)))
+			app, err := build()
+			if err != nil {
+				return fmt.Errorf("building application: %w", err)
+			}
+
+			var entries []tldw.LibraryEntry
+			err = writeFileAtomically(out, func(writer io.Writer) error {
+				var exportErr error
+				entries, exportErr = app.ExportLibrary(query, writer)
+				return exportErr
+			})
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Exported %d %s to %s\n", len(entries),
+				plural(len(entries), "video", "videos"), out)
+			return err
+		},
+	}
+	command.Flags().String("out", "", "Archive path, e.g. lib.tar.gz (required)")
+	command.Flags().String("channel", "", "Only export videos whose channel contains this text")
+	command.Flags().String("search", "", "Only export videos whose title, ID, or tags contain this text")
+	command.Flags().String("source", "", "Only export transcripts from captions or whisper")
+	_ = command.MarkFlagRequired("out")
+	return command
+}
+
+func newLibraryImportCommand(build libraryApplicationFactory) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "import [ARCHIVE]",
+		Short: "Merge an exported archive into the library",
+		Long: `Merge an archive written by tldw library export into the local library.
+
+When a video is already cached, --on-conflict decides which copy wins: newest
+keeps the most recently cached copy, local keeps the local copy, and overwrite
+always takes the archive's copy. The archive is validated before anything is
+written.`,
+		Example: `  # Import, keeping whichever copy is newer
+  tldw library import lib.tar.gz
+
+  # Never touch videos that are already cached
+  tldw library import lib.tar.gz --on-conflict local`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value, err := cmd.Flags().GetString("on-conflict")
+			if err != nil {
+				return err
+			}
+			policy, err := tldw.ParseImportPolicy(value)
+			if err != nil {
+				return err
+			}
+			archive, err := os.Open(filepath.Clean(args[0]))
+			if err != nil {
+				return fmt.Errorf("opening archive: %w", err)
+			}
+			defer func() { _ = archive.Close() }()
+			app, err := build()
+			if err != nil {
+				return fmt.Errorf("building application: %w", err)
+			}
+			imports, err := app.ImportLibrary(archive, policy)
+			if writeErr := writeLibraryImport(cmd.OutOrStdout(), imports); writeErr != nil {
+				return writeErr
+			}
+			return err
+		},
+	}
+	command.Flags().String("on-conflict", string(tldw.ImportKeepNewest), "Keep the newest, local, or overwrite with the archive's copy")
+	return command
+}
+
+func writeLibraryImport(writer io.Writer, imports []tldw.LibraryImport) error {
+	counts := make(map[tldw.ImportOutcome]int)
+	for _, imported := range imports {
+		counts[imported.Outcome]++
+		if _, err := fmt.Fprintf(writer, "%-8s %s\n", imported.Outcome, imported.VideoID); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(writer, "Added %d, replaced %d, skipped %d\n",
+		counts[tldw.ImportAdded], counts[tldw.ImportReplaced], counts[tldw.ImportSkipped])
+	return err
+}
+
+// writeFileAtomically only replaces path once write has succeeded.
+func writeFileAtomically(path string, write func(io.Writer) error) (err error) {
+	temp, err := os.CreateTemp(filepath.Dir(path), ".tldw-*")
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = temp.Close()
+			_ = os.Remove(temp.Name())
+		}
+	}()
+	if err := write(temp); err != nil {
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+type libraryApplicationStub struct {
+	entries []tldw.LibraryEntry
+	query   tldw.LibraryQuery
+	removed []string
+	cutoff  time.Time
+	dryRun  bool
+	archive []byte
+	policy  tldw.ImportPolicy
+	imports []tldw.LibraryImport
+}
+
+func (stub *libraryApplicationStub) Library(query tldw.LibraryQuery) ([]tldw.LibraryEntry, error) {
+	stub.query = query
+	return stub.entries, nil
+}
+
+func (stub *libraryApplicationStub) LibraryEntry(ref tldw.YouTubeRef) (*tldw.LibraryEntry, error) {
+	for _, entry := range stub.entries {
+		if entry.VideoID == ref.ID() {
+			return &entry, nil
+		}
+	}
+	return nil, tldw.ErrStoreNotFound
+}
+
+func (stub *libraryApplicationStub) RemoveFromLibrary(ref tldw.YouTubeRef) error {
+	stub.removed = append(stub.removed, ref.ID())
+	return nil
+}
+
+func (stub *libraryApplicationStub) PruneLibrary(cutoff time.Time, dryRun bool) ([]tldw.LibraryEntry, error) {
+	stub.cutoff = cutoff
+	stub.dryRun = dryRun
+	return stub.entries, nil
+}
+
+func (stub *libraryApplicationStub) ExportLibrary(query tldw.LibraryQuery, archive io.Writer) ([]tldw.LibraryEntry, error) {
+	stub.query = query
+	_, err := archive.Write([]byte("archive"))
+	return stub.entries, err
+}
+
+func (stub *libraryApplicationStub) ImportLibrary(archive io.Reader, policy tldw.ImportPolicy) ([]tldw.LibraryImport, error) {
+	data, err := io.ReadAll(archive)
+	stub.archive = data
+	stub.policy = policy
+	return stub.imports, err
+}
+
+func TestLibraryListPassesFiltersAndRendersTable(t *testing.T) {
+	stub := &libraryApplicationStub{entries: []tldw.LibraryEntry{{
+		VideoID:          "dQw4w9WgXcQ",
+		Metadata:         &tldw.VideoMetadata{Title: "Example", Channel: "Channel", Duration: 5400},
+		HasTranscript:    true,
+		TranscriptSource: tldw.TranscriptSourceCaptions,
+		FirstSeenAt:      time.Date(2026, time.July, 2, 12, 0, 0, 0, time.Local),
+		Files:            []tldw.LibraryFile{{Name: "dQw4w9WgXcQ.txt", Size: 2048}},
+	}}}
+	command := newLibraryCommand(func() (libraryApplication, error) { return stub, nil }, time.Now)
+	var output bytes.Buffer
+	command.SetOut(&output)
+	command.SetArgs([]string{"list", "--channel", "chan", "--sort", "Duration", "--source", "captions", "--limit", "5"})
+
+	if err := command.Execute(); err != nil {
+		t.Fatalf("library list error = %v", err)
+	}
+	want := tldw.LibraryQuery{Channel: "chan", Source: tldw.TranscriptSourceCaptions, SortBy: tldw.LibrarySortDuration, Limit: 5}
+	if stub.query != want {
+		t.Fatalf("Library() query = %+v, want %+v", stub.query, want)
+	}
+	for _, fragment := range []string{"ID", "dQw4w9WgXcQ", "2026-07-02", "1h 30m", "captions", "2.0 KiB", "Example", "1 video\n"} {
+		if !strings.Contains(output.String(), fragment) {
+			t.Fatalf("library list output %q does not contain %q", output.String(), fragment)
+		}
+	}
+}
+
+func TestLibraryPruneParsesAgeRelativeToNow(t *testing.T) {
+	stub := &libraryApplicationStub{}
+	now := time.Date(2026, time.July, 19, 11, 0, 0, 0, time.UTC)
+	command := newLibraryCommand(func() (libraryApplication, error) { return stub, nil }, func() time.Time { return now })
+	var output bytes.Buffer
+	command.SetOut(&output)
+	command.SetArgs([]string{"prune", "--older-than", "90d", "--dry-run"})
+
+	if err := command.Execute(); err != nil {
+		t.Fatalf("library prune error = %v", err)
+	}
+	if want := now.AddDate(0, 0, -90); !stub.cutoff.Equal(want) || !stub.dryRun {
+		t.Fatalf("PruneLibrary() cutoff = %v dryRun = %v, want %v dry run", stub.cutoff, stub.dryRun, want)
+	}
+	if !strings.Contains(output.String(), "Would remove 0 videos") {
+		t.Fatalf("library prune output = %q", output.String())
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "90d", want: 90 * 24 * time.Hour},
+		{value: "2w", want: 14 * 24 * time.Hour},
+		{value: "36h", want: 36 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got, err := parseAge(tt.value); err != nil || got != tt.want {
+			t.Errorf("parseAge(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+	for _, value := range []string{"", "d", "-5d", "1.5d", "soon"} {
+		if _, err := parseAge(value); err == nil {
+			t.Errorf("parseAge(%q) accepted an invalid age", value)
+		}
+	}
+}
+
+func TestLibraryExportAndImportArchives(t *testing.T) {
+	stub := &libraryApplicationStub{
+		entries: []tldw.LibraryEntry{{VideoID: "dQw4w9WgXcQ"}},
+		imports: []tldw.LibraryImport{{VideoID: "dQw4w9WgXcQ", Outcome: tldw.ImportAdded}, {VideoID: "tAP1eZYEuKA", Outcome: tldw.ImportSkipped}},
+	}
+	archive := filepath.Join(t.TempDir(), "lib.tar.gz")
+	command := newLibraryCommand(func() (libraryApplication, error) { return stub, nil }, time.Now)
+	var output bytes.Buffer
+	command.SetOut(&output)
+	command.SetArgs([]string{"export", "--out", archive, "--channel", "chan"})
+	if err := command.Execute(); err != nil {
+		t.Fatalf("library export error = %v", err)
+	}
+	if stub.query.Channel != "chan" || !strings.Contains(output.String(), "Exported 1 video to") {
+		t.Fatalf("library export query = %+v output = %q", stub.query, output.String())
+	}
+
+	command = newLibraryCommand(func() (libraryApplication, error) { return stub, nil }, time.Now)
+	output.Reset()
+	command.SetOut(&output)
+	command.SetArgs([]string{"import", archive, "--on-conflict", "local"})
+	if err := command.Execute(); err != nil {
+		t.Fatalf("library import error = %v", err)
+	}
+	if string(stub.archive) != "archive" || stub.policy != tldw.ImportKeepLocal {
+		t.Fatalf("ImportLibrary() archive = %q policy = %q", stub.archive, stub.policy)
+	}
+	if !strings.Contains(output.String(), "Added 1, replaced 0, skipped 1") {
+		t.Fatalf("library import output = %q", output.String())
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(archive), ".tldw-*")); len(leftovers) != 0 {
+		t.Fatalf("library export left temporary files: %v", leftovers)
+	}
+	if _, err := os.Stat(archive); err != nil {
+		t.Fatalf("library export did not write the archive: %v", err)
+	}
+}
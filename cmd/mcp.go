@@ -1,17 +1,16 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"os"
-	"path/filepath"
-	"runtime"
-	"strings"
+	"os/exec"
 
-	"github.com/adrg/xdg"
 	"github.com/spf13/cobra"
 
+	"github.com/rtzll/tldw/internal"
 	mcpserver "github.com/rtzll/tldw/internal/mcp"
+	"github.com/rtzll/tldw/internal/store"
+	"github.com/rtzll/tldw/internal/tldw"
 )
 
 // mcpCmd represents the mcp command
@@ -20,24 +19,51 @@ var mcpCmd = &cobra.Command{
 	Short: "Run minimal MCP server for TL;DW",
 	Long: `Run a Model Context Protocol (MCP) server that exposes TL;DW functionality as tools.
 
-The MCP server provides three video tools:
+The MCP server provides four video tools:
 - get_youtube_metadata: Extract video metadata as formatted text
 - get_youtube_transcript: Fetch built-in captions
 - transcribe_youtube_whisper: Transcribe audio with Whisper
+- summarize_youtube_video: Summarize a video with OpenAI
+
+and two playlist tools:
+- get_youtube_playlist: List a playlist's videos
+- summarize_youtube_playlist: Summarize a whole playlist with OpenAI
+
+and two library tools that only read cached videos:
+- search_transcripts: Search cached transcripts for a phrase
+- get_watch_stats: Count watched videos and their runtime
+
+Cached videos are also available as resources: tldw://library,
+tldw://video/{id}/transcript, and tldw://video/{id}/metadata.
+
+The tldw_summary prompt renders the configured summary prompt with a video's
+transcript and metadata; each template in the prompts config directory is
+offered as tldw_<name>.
 
 This allows AI assistants to use TL;DW capabilities through the MCP protocol.
 
 Transport options:
 - stdio (default): Standard MCP transport via stdin/stdout
-- http: HTTP transport on specified host and port (use --host and --port to configure)`,
+- http: HTTP transport on specified host and port (use --host and --port to configure)
+
+The HTTP transport requires bearer tokens (mcp_tokens or mcp_tokens_file in the
+config) to listen on a non-loopback host. Requests must name an allowed Host and,
+from browsers, an allowed Origin. Set mcp_tls_cert and mcp_tls_key to serve HTTPS.
+
+Next to the MCP endpoint, the HTTP transport serves /healthz, /readyz (yt-dlp,
+ffmpeg, and a writable transcript store), and Prometheus metrics at /metrics,
+which needs a token when tokens are configured.`,
 	Example: `  # Run MCP server with stdio transport (e.g. for Claude Desktop)
   tldw mcp
 
   # Run MCP server with HTTP transport on localhost port 8765
   tldw mcp --transport=http --host=127.0.0.1 --port=8765
 
-  # Set up Claude Desktop integration
-  tldw mcp setup-claude`,
+  # Set up Claude Desktop integration (see "tldw mcp setup --help" for other clients)
+  tldw mcp setup --client claude
+
+  # Watch the MCP log (enable it with mcp_log_enabled or TLDW_MCP_LOG=true)
+  tldw mcp logs --follow`,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		// MCP uses stdio protocol, so disable all UI output including spinners
 		config.Verbose = false
@@ -52,160 +78,76 @@ Transport options:
 			host = "127.0.0.1"
 		}
 
-		app, err := buildEngine(config, silentLogSink{})
+		files := store.NewFile(config.TranscriptsDir)
+		var wrapAI func(tldw.AIAdapter) tldw.AIAdapter
+		if config.OpenAIAPIKey == "" {
+			// Without a key, summaries can still come from clients that
+			// support sampling.
+			wrapAI = func(ai tldw.AIAdapter) tldw.AIAdapter { return mcpserver.NewSamplingAI(ai) }
+		}
+		app, err := buildEngine(config, silentLogSink{}, files, wrapAI)
 		if err != nil {
 			return fmt.Errorf("building application: %w", err)
 		}
 
-		mcpserver.InitLogging(config.MCPLogEnabled)
+		httpOptions := mcpserver.HTTPOptions{
+			AllowedHosts:   config.MCPHTTP.AllowedHosts,
+			AllowedOrigins: config.MCPHTTP.AllowedOrigins,
+			TLSCertFile:    config.MCPHTTP.TLSCert,
+			TLSKeyFile:     config.MCPHTTP.TLSKey,
+			Readiness:      mcpReadinessChecks(files),
+		}
+		if transport == "http" {
+			httpOptions.Tokens, err = config.MCPHTTP.LoadTokens()
+			if err != nil {
+				return err
+			}
+		}
+
+		// Token-authenticated HTTP always logs, so paid tool calls can be
+		// traced back to a token.
+		mcpserver.InitLogging(mcpserver.LogOptions{
+			Enabled:  config.MCPLogEnabled || len(httpOptions.Tokens) > 0,
+			Path:     mcpLogPath(),
+			MaxSize:  config.MCPLogMaxSize,
+			MaxFiles: config.MCPLogMaxFiles,
+		})
 		mcpServer := mcpserver.NewMCPServer(app)
+		if err := mcpServer.SetHTTPOptions(httpOptions); err != nil {
+			return err
+		}
+		if err := mcpServer.ServeLibrary(files); err != nil {
+			return fmt.Errorf("serving library resources: %w", err)
+		}
+		files.SetTranscriptSavedHook(mcpServer.TranscriptSaved)
+		if err := mcpServer.ServePrompts(internal.NewPromptManager(config.ConfigDir, config.Prompt)); err != nil {
+			return fmt.Errorf("serving prompts: %w", err)
+		}
 
 		// Start the server (this will block until context is cancelled)
 		return mcpServer.Start(cmd.Context(), transport, host, port)
 	},
 }
 
-// setupClaudeCmd represents the setup-claude subcommand
-var setupClaudeCmd = &cobra.Command{
-	Use:   "setup-claude",
-	Short: "Configure Claude Desktop to use TL;DW MCP server",
-	Long: `Automatically configure Claude Desktop to use TL;DW as an MCP server.
-
-This command will:
-- Detect Claude Desktop installation and config location
-- Add TL;DW MCP server configuration to claude_desktop_config.json
-- Preserve existing MCP server configurations
-- Set appropriate XDG environment variables for the current platform`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		return setupClaudeDesktop()
-	},
-}
-
-type claudeDesktopConfig struct {
-	MCPServers map[string]mcpServerConfig `json:"mcpServers"`
-}
-
-type mcpServerConfig struct {
-	Command string            `json:"command"`
-	Args    []string          `json:"args"`
-	Env     map[string]string `json:"env"`
-}
-
-// setupClaudeDesktop implements the setup-claude subcommand
-func setupClaudeDesktop() error {
-	// Get the path to the current binary
-	execPath, err := os.Executable()
-	if err != nil {
-		return fmt.Errorf("getting executable path: %w", err)
-	}
-
-	// Only resolve symlinks if not running from Homebrew
-	// This preserves the Homebrew symlink path which will always point to latest version
-	if !strings.Contains(execPath, "/Cellar/") && !strings.Contains(execPath, "/homebrew/") {
-		execPath, err = filepath.EvalSymlinks(execPath)
-		if err != nil {
-			return fmt.Errorf("resolving executable path: %w", err)
-		}
-	}
-
-	// Get Claude Desktop config path
-	configPath, err := getClaudeDesktopConfigPath()
-	if err != nil {
-		return fmt.Errorf("getting Claude Desktop config path: %w", err)
-	}
-
-	// Check if config file exists - abort if it doesn't
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return fmt.Errorf("config for Claude Desktop not found at %s", configPath)
-	}
-
-	// Read existing config
-	var config claudeDesktopConfig
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return fmt.Errorf("reading existing config: %w", err)
-	}
-
-	if err := json.Unmarshal(data, &config); err != nil {
-		return fmt.Errorf("parsing existing config: %w", err)
-	}
-
-	// Initialize mcpServers map if it doesn't exist
-	if config.MCPServers == nil {
-		config.MCPServers = make(map[string]mcpServerConfig)
+// mcpReadinessChecks covers what the tools need beyond the network: yt-dlp
+// for YouTube, ffmpeg for Whisper audio, and a writable transcript store.
+func mcpReadinessChecks(files *store.File) []mcpserver.ReadinessCheck {
+	commandCheck := func(name string) mcpserver.ReadinessCheck {
+		return mcpserver.ReadinessCheck{Name: name, Check: func(context.Context) error {
+			_, err := exec.LookPath(name)
+			return err
+		}}
 	}
-
-	// Get XDG base paths so internal config can add tldw
-	paths := map[string]string{
-		"HOME":            xdg.Home,
-		"XDG_DATA_HOME":   xdg.DataHome,
-		"XDG_CONFIG_HOME": xdg.ConfigHome,
-		"XDG_CACHE_HOME":  xdg.CacheHome,
+	return []mcpserver.ReadinessCheck{
+		commandCheck("yt-dlp"),
+		commandCheck("ffmpeg"),
+		{Name: "store", Check: func(context.Context) error { return files.CheckWritable() }},
 	}
-
-	// Add/update TL;DW MCP server configuration
-	config.MCPServers["tldw"] = mcpServerConfig{
-		Command: execPath,
-		Args:    []string{"mcp"},
-		Env:     paths,
-	}
-
-	// Write updated config back to file
-	data, err = json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshaling config: %w", err)
-	}
-
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
-		return fmt.Errorf("writing config file: %w", err)
-	}
-
-	fmt.Printf("Successfully configured Claude Desktop MCP server\n")
-	fmt.Printf("Restart Claude Desktop to use the TL;DW MCP server\n")
-
-	return nil
-}
-
-// getClaudeDesktopConfigPath returns the platform-specific config path for Claude Desktop
-func getClaudeDesktopConfigPath() (string, error) {
-	var configPath string
-
-	switch runtime.GOOS {
-	case "darwin":
-		// macOS: ~/Library/Application Support/Claude/claude_desktop_config.json
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return "", err
-		}
-		configPath = filepath.Join(homeDir, "Library", "Application Support", "Claude", "claude_desktop_config.json")
-
-	case "windows":
-		// Windows: %APPDATA%/Claude/claude_desktop_config.json
-		appData := os.Getenv("APPDATA")
-		if appData == "" {
-			return "", fmt.Errorf("APPDATA environment variable not set")
-		}
-		configPath = filepath.Join(appData, "Claude", "claude_desktop_config.json")
-
-	case "linux":
-		// Linux: ~/.config/Claude/claude_desktop_config.json
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return "", err
-		}
-		configPath = filepath.Join(homeDir, ".config", "Claude", "claude_desktop_config.json")
-
-	default:
-		return "", fmt.Errorf("unsupported platform: %s", runtime.GOOS)
-	}
-
-	return configPath, nil
 }
 
 func init() {
 	mcpCmd.Flags().String("transport", "stdio", "Transport protocol (stdio or http)")
 	mcpCmd.Flags().String("host", "127.0.0.1", "Host for HTTP transport (only used with --transport=http)")
 	mcpCmd.Flags().Int("port", 8765, "Port for HTTP transport (only used with --transport=http)")
-	mcpCmd.AddCommand(setupClaudeCmd)
 	rootCmd.AddCommand(mcpCmd)
 }
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	mcpserver "github.com/rtzll/tldw/internal/mcp"
+)
+
+// mcpLogPollInterval is how often --follow checks the log for new records.
+var mcpLogPollInterval = 250 * time.Millisecond
+
+var mcpLogsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Show the MCP server log",
+	Long: `Show the MCP server log as readable lines.
+
+The log is written when mcp_log_enabled is set in the config, TLDW_MCP_LOG=true
+is exported, or the HTTP transport has tokens. Each tool call is one record with
+its tool, request and session IDs, video ID, duration, outcome, and error class.
+Lines that are not JSON, such as those from older versions, are shown as they are.`,
+	Example: `  # Show the last 20 records
+  tldw mcp logs
+
+  # Keep printing new records as the server writes them
+  tldw mcp logs --follow`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		lines, _ := cmd.Flags().GetInt("lines")
+		follow, _ := cmd.Flags().GetBool("follow")
+		return runMCPLogs(cmd.Context(), cmd.OutOrStdout(), mcpLogPath(), lines, follow)
+	},
+}
+
+func mcpLogPath() string {
+	return filepath.Join(config.CacheDir, mcpserver.LogFileName)
+}
+
+// runMCPLogs prints the last lines of the log at path and, when following,
+// every record written after them, across rotations, until ctx is done.
+func runMCPLogs(ctx context.Context, out io.Writer, path string, lines int, follow bool) error {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) && follow {
+		if file, err = waitForMCPLog(ctx, path); file == nil {
+			return err
+		}
+	} else if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no MCP log at %s - enable it with mcp_log_enabled = true in the config or TLDW_MCP_LOG=true", path)
+	} else if err != nil {
+		return fmt.Errorf("opening MCP log: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return fmt.Errorf("reading MCP log: %w", err)
+	}
+	complete := data[:bytes.LastIndexByte(data, '\n')+1]
+	for _, line := range lastLines(complete, lines) {
+		fmt.Fprintln(out, formatMCPLogLine(line))
+	}
+	if !follow {
+		return nil
+	}
+
+	pending := data[len(complete):]
+	ticker := time.NewTicker(mcpLogPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		chunk, err := io.ReadAll(file)
+		if err != nil {
+			return fmt.Errorf("reading MCP log: %w", err)
+		}
+		pending = printMCPLogLines(out, append(pending, chunk...))
+
+		rotated, err := mcpLogRotated(file, path)
+		if err != nil {
+			return err
+		}
+		if rotated {
+			// Records written just before the rotation are still in the old file.
+			if chunk, err = io.ReadAll(file); err == nil {
+				pending = printMCPLogLines(out, append(pending, chunk...))
+			}
+			_ = file.Close()
+			if file, err = waitForMCPLog(ctx, path); file == nil {
+				return err
+			}
+		}
+	}
+}
+
+// waitForMCPLog opens the log at path once the server has created it. It
+// returns no file and no error when ctx is done first.
+func waitForMCPLog(ctx context.Context, path string) (*os.File, error) {
+	for {
+		file, err := os.Open(path)
+		if err == nil {
+			return file, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("opening MCP log: %w", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-time.After(mcpLogPollInterval):
+		}
+	}
+}
+
+// mcpLogRotated reports whether path no longer names the open file, or the
+// file was truncated below what has been read.
+func mcpLogRotated(file *os.File, path string) (bool, error) {
+	current, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("checking MCP log: %w", err)
+	}
+	opened, err := file.Stat()
+	if err != nil {
+		return false, fmt.Errorf("checking MCP log: %w", err)
+	}
+	offset, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return false, fmt.Errorf("checking MCP log: %w", err)
+	}
+	return !os.SameFile(current, opened) || current.Size() < offset, nil
+}
+
+// printMCPLogLines prints the complete lines in data and returns the partial
+// line after them.
+func printMCPLogLines(out io.Writer, data []byte) []byte {
+	for {
+		line, rest, found := bytes.Cut(data, []byte("\n"))
+		if !found {
+			return data
+		}
+		fmt.Fprintln(out, formatMCPLogLine(line))
+		data = rest
+	}
+}
+
+// lastLines returns the last n lines of data, which ends with a newline, or
+// all of them when n is negative.
+func lastLines(data []byte, n int) [][]byte {
+	if len(data) == 0 {
+		return nil
+	}
+	lines := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+	if n >= 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// formatMCPLogLine renders a JSON record as its local time, level, and
+// message followed by the remaining fields in the order they were written.
+func formatMCPLogLine(line []byte) string {
+	fields, err := decodeOrderedFields(line)
+	if err != nil {
+		return string(line)
+	}
+
+	var timestamp, level, message string
+	var rest []string
+	for _, field := range fields {
+		value := logFieldValue(field.value)
+		switch field.key {
+		case "time":
+			timestamp = value
+			if parsed, err := time.Parse(time.RFC3339Nano, value); err == nil {
+				timestamp = parsed.Local().Format("2006-01-02 15:04:05.000")
+			}
+		case "level":
+			level = value
+		case "msg":
+			message = value
+		default:
+			if strings.ContainsAny(value, " \t\"=") || value == "" {
+				value = fmt.Sprintf("%q", value)
+			}
+			rest = append(rest, field.key+"="+value)
+		}
+	}
+
+	parts := []string{timestamp, fmt.Sprintf("%-5s", level), message}
+	return strings.TrimSpace(strings.Join(append(parts, rest...), " "))
+}
+
+type logField struct {
+	key   string
+	value json.RawMessage
+}
+
+// decodeOrderedFields decodes a JSON object without losing its key order.
+func decodeOrderedFields(line []byte) ([]logField, error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("not a JSON object")
+	}
+	var fields []logField
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := token.(string)
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		fields = append(fields, logField{key: key, value: value})
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func logFieldValue(value json.RawMessage) string {
+	var text string
+	if json.Unmarshal(value, &text) == nil {
+		return text
+	}
+	return string(value)
+}
+
+func init() {
+	mcpLogsCmd.Flags().IntP("lines", "n", 20, "Number of records to show first; -1 shows the whole log")
+	mcpLogsCmd.Flags().BoolP("follow", "f", false, "Keep printing records as they are written")
+	mcpCmd.AddCommand(mcpLogsCmd)
+}
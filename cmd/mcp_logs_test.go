@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFormatMCPLogLine(t *testing.T) {
+	line := `{"time":"2026-10-18T09:30:00.5Z","level":"ERROR","msg":"tool call","tool":"get_youtube_transcript","video_id":"dQw4w9WgXcQ","duration_ms":12,"outcome":"error","error":"no captions available"}`
+
+	got := formatMCPLogLine([]byte(line))
+
+	timestamp := time.Date(2026, 10, 18, 9, 30, 0, 5e8, time.UTC).Local().Format("2006-01-02 15:04:05.000")
+	want := timestamp + ` ERROR tool call tool=get_youtube_transcript video_id=dQw4w9WgXcQ duration_ms=12 outcome=error error="no captions available"`
+	if got != want {
+		t.Fatalf("formatMCPLogLine() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFormatMCPLogLineKeepsPlainText(t *testing.T) {
+	line := "2025/01/02 10:00:00.000000 [MCP] [INFO] Starting MCP server with stdio transport"
+	if got := formatMCPLogLine([]byte(line)); got != line {
+		t.Fatalf("formatMCPLogLine() = %q, want the line unchanged", got)
+	}
+}
+
+func TestRunMCPLogsShowsLastLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.log")
+	if err := os.WriteFile(path, []byte("one\ntwo\nthree\npartial"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := runMCPLogs(context.Background(), &out, path, 2, false); err != nil {
+		t.Fatalf("runMCPLogs() error = %v", err)
+	}
+	if out.String() != "two\nthree\n" {
+		t.Fatalf("output = %q, want the last two complete lines", out.String())
+	}
+}
+
+func TestRunMCPLogsReportsMissingLog(t *testing.T) {
+	err := runMCPLogs(context.Background(), &bytes.Buffer{}, filepath.Join(t.TempDir(), "mcp.log"), 20, false)
+	if err == nil || !strings.Contains(err.Error(), "mcp_log_enabled") {
+		t.Fatalf("runMCPLogs() error = %v, want a hint to enable the log", err)
+	}
+}
+
+func TestRunMCPLogsFollowsAcrossRotation(t *testing.T) {
+	previous := mcpLogPollInterval
+	mcpLogPollInterval = 5 * time.Millisecond
+	t.Cleanup(func() { mcpLogPollInterval = previous })
+
+	path := filepath.Join(t.TempDir(), "mcp.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := &lockedBuffer{}
+	done := make(chan error, 1)
+	go func() { done <- runMCPLogs(ctx, out, path, 20, true) }()
+
+	waitForOutput(t, out, "old\n")
+	appendFile(t, path, "before rotation\n")
+	waitForOutput(t, out, "old\nbefore rotation\n")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	appendFile(t, path, "after rotation\n")
+	waitForOutput(t, out, "old\nbefore rotation\nafter rotation\n")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("runMCPLogs() error = %v", err)
+	}
+}
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func waitForOutput(t *testing.T, out *lockedBuffer, want string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for out.String() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("output = %q, want %q", out.String(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func appendFile(t *testing.T, path, text string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	defer func() { _ = file.Close() }()
+	if _, err := file.WriteString(text); err != nil {
+		t.Fatalf("WriteString() error = %v", err)
+	}
+}
@@ -0,0 +1,686 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/adrg/xdg"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+)
+
+// mcpServerName is the key TL;DW is registered under in client configs.
+const mcpServerName = "tldw"
+
+type mcpServerConfig struct {
+	Command string            `json:"command" toml:"command"`
+	Args    []string          `json:"args" toml:"args"`
+	Env     map[string]string `json:"env" toml:"env"`
+}
+
+// mcpClient describes where an MCP client keeps its server list and how it
+// spells an entry. Clients without a config path only print a snippet.
+type mcpClient struct {
+	Label      string
+	ServersKey string
+	ConfigPath func() (string, error)
+	Entry      func(mcpServerConfig) any
+}
+
+var mcpClients = map[string]mcpClient{
+	"claude": {
+		Label: "Claude Desktop", ServersKey: "mcpServers",
+		ConfigPath: getClaudeDesktopConfigPath, Entry: plainMCPEntry,
+	},
+	"cursor": {
+		Label: "Cursor", ServersKey: "mcpServers",
+		ConfigPath: homeConfigPath(".cursor", "mcp.json"), Entry: plainMCPEntry,
+	},
+	"vscode": {
+		Label: "VS Code", ServersKey: "servers",
+		ConfigPath: vscodeConfigPath,
+		Entry: func(server mcpServerConfig) any {
+			return struct {
+				Type string `json:"type"`
+				mcpServerConfig
+			}{"stdio", server}
+		},
+	},
+	"windsurf": {
+		Label: "Windsurf", ServersKey: "mcpServers",
+		ConfigPath: homeConfigPath(".codeium", "windsurf", "mcp_config.json"), Entry: plainMCPEntry,
+	},
+	"zed": {
+		Label: "Zed", ServersKey: "context_servers",
+		ConfigPath: zedConfigPath,
+		Entry: func(server mcpServerConfig) any {
+			return struct {
+				Source string `json:"source"`
+				mcpServerConfig
+			}{"custom", server}
+		},
+	},
+	"json": {Label: "JSON", ServersKey: "mcpServers", Entry: plainMCPEntry},
+	"toml": {Label: "TOML", ServersKey: "mcp_servers"},
+}
+
+func plainMCPEntry(server mcpServerConfig) any { return server }
+
+func mcpClientNames() []string {
+	names := make([]string, 0, len(mcpClients))
+	for name := range mcpClients {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+type mcpSetupOptions struct {
+	Client string
+	Path   string
+	DryRun bool
+	Remove bool
+}
+
+// mcpSetupCmd represents the setup subcommand
+var mcpSetupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Configure an MCP client to use the TL;DW MCP server",
+	Long: `Add TL;DW to an MCP client's configuration, or remove it again.
+
+Supported clients: claude (Claude Desktop), cursor, vscode, windsurf, and zed.
+The json and toml clients print a snippet to paste into any other client.
+
+The config file is created when it does not exist. Other servers and settings
+are preserved, and the previous file is saved next to it with a .bak suffix
+before any change. The server runs this executable with the current platform's
+XDG directories, so it finds the same config, cache, and transcripts as the CLI.`,
+	Example: `  # Configure Cursor
+  tldw mcp setup --client cursor
+
+  # Show what would change in VS Code's config without writing it
+  tldw mcp setup --client vscode --dry-run
+
+  # Remove TL;DW from Zed
+  tldw mcp setup --client zed --remove
+
+  # Print a TOML snippet for another client
+  tldw mcp setup --client toml`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		options := mcpSetupOptions{}
+		var err error
+		if options.Client, err = cmd.Flags().GetString("client"); err != nil {
+			return err
+		}
+		if options.Path, err = cmd.Flags().GetString("path"); err != nil {
+			return err
+		}
+		if options.DryRun, err = cmd.Flags().GetBool("dry-run"); err != nil {
+			return err
+		}
+		if options.Remove, err = cmd.Flags().GetBool("remove"); err != nil {
+			return err
+		}
+		server, err := tldwMCPServerConfig()
+		if err != nil {
+			return err
+		}
+		return runMCPSetup(cmd.OutOrStdout(), options, server)
+	},
+}
+
+// setupClaudeCmd represents the setup-claude subcommand
+var setupClaudeCmd = &cobra.Command{
+	Use:        "setup-claude",
+	Short:      "Configure Claude Desktop to use TL;DW MCP server",
+	Deprecated: "use \"tldw mcp setup --client claude\" instead",
+	Args:       cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		server, err := tldwMCPServerConfig()
+		if err != nil {
+			return err
+		}
+		return runMCPSetup(cmd.OutOrStdout(), mcpSetupOptions{Client: "claude"}, server)
+	},
+}
+
+// tldwMCPServerConfig describes how clients start this executable's MCP server.
+func tldwMCPServerConfig() (mcpServerConfig, error) {
+	// Get the path to the current binary
+	execPath, err := os.Executable()
+	if err != nil {
+		return mcpServerConfig{}, fmt.Errorf("getting executable path: %w", err)
+	}
+
+	// Only resolve symlinks if not running from Homebrew
+	// This preserves the Homebrew symlink path which will always point to latest version
+	if !strings.Contains(execPath, "/Cellar/") && !strings.Contains(execPath, "/homebrew/") {
+		execPath, err = filepath.EvalSymlinks(execPath)
+		if err != nil {
+			return mcpServerConfig{}, fmt.Errorf("resolving executable path: %w", err)
+		}
+	}
+
+	// Get XDG base paths so internal config can add tldw
+	return mcpServerConfig{
+		Command: execPath,
+		Args:    []string{"mcp"},
+		Env: map[string]string{
+			"HOME":            xdg.Home,
+			"XDG_DATA_HOME":   xdg.DataHome,
+			"XDG_CONFIG_HOME": xdg.ConfigHome,
+			"XDG_CACHE_HOME":  xdg.CacheHome,
+		},
+	}, nil
+}
+
+func runMCPSetup(out io.Writer, options mcpSetupOptions, server mcpServerConfig) error {
+	if options.Client == "" {
+		return fmt.Errorf("--client is required: use one of %s", strings.Join(mcpClientNames(), ", "))
+	}
+	client, ok := mcpClients[options.Client]
+	if !ok {
+		return fmt.Errorf("unsupported client %q: use one of %s", options.Client, strings.Join(mcpClientNames(), ", "))
+	}
+
+	path := options.Path
+	if path == "" && client.ConfigPath != nil {
+		var err error
+		if path, err = client.ConfigPath(); err != nil {
+			return fmt.Errorf("getting %s config path: %w", client.Label, err)
+		}
+	}
+	if path == "" {
+		if options.Remove {
+			return fmt.Errorf("the %s client only prints a snippet; remove the %s entry by hand", options.Client, mcpServerName)
+		}
+		return writeMCPSnippet(out, client, server)
+	}
+
+	original, err := os.ReadFile(path)
+	exists := err == nil
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	if !exists && options.Remove {
+		fmt.Fprintf(out, "%s is not configured: %s does not exist\n", client.Label, path)
+		return nil
+	}
+
+	updated, changed, err := updateMCPConfig(original, client, server, options.Remove)
+	if err != nil {
+		return fmt.Errorf("updating %s: %w", path, err)
+	}
+	if !changed {
+		if options.Remove {
+			fmt.Fprintf(out, "TL;DW is not configured in %s\n", path)
+		} else {
+			fmt.Fprintf(out, "%s is already configured in %s\n", client.Label, path)
+		}
+		return nil
+	}
+	if options.DryRun {
+		fmt.Fprint(out, lineDiff(path, string(original), string(updated)))
+		return nil
+	}
+
+	mode := fs.FileMode(0644)
+	if exists {
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+		backup := path + ".bak"
+		if err := os.WriteFile(backup, original, mode); err != nil {
+			return fmt.Errorf("writing backup %s: %w", backup, err)
+		}
+		fmt.Fprintf(out, "Saved the previous config to %s\n", backup)
+	} else if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	if hasJSONComments(original) {
+		fmt.Fprintf(out, "Comments in %s are not kept; they remain in the backup\n", path)
+	}
+	if err := writeMCPConfig(path, updated, mode); err != nil {
+		return err
+	}
+
+	if options.Remove {
+		fmt.Fprintf(out, "Removed the TL;DW MCP server from %s\n", path)
+	} else {
+		fmt.Fprintf(out, "Successfully configured %s MCP server in %s\n", client.Label, path)
+	}
+	fmt.Fprintf(out, "Restart %s to apply the change\n", client.Label)
+	return nil
+}
+
+// updateMCPConfig adds or removes the TL;DW entry in a client config, keeping
+// every other key in its original order. It reports whether anything changed.
+func updateMCPConfig(original []byte, client mcpClient, server mcpServerConfig, remove bool) ([]byte, bool, error) {
+	config, err := parseJSONObject(stripJSONC(original))
+	if err != nil {
+		return nil, false, fmt.Errorf("parsing config (use --client json and edit the file by hand): %w", err)
+	}
+	var servers jsonObject
+	if raw, ok := config.get(client.ServersKey); ok {
+		if servers, err = parseJSONObject(raw); err != nil {
+			return nil, false, fmt.Errorf("parsing %q: %w", client.ServersKey, err)
+		}
+	}
+
+	if remove {
+		if !servers.remove(mcpServerName) {
+			return original, false, nil
+		}
+	} else {
+		entry, err := json.Marshal(client.Entry(server))
+		if err != nil {
+			return nil, false, fmt.Errorf("marshaling server entry: %w", err)
+		}
+		if current, ok := servers.get(mcpServerName); ok && jsonEqual(current, entry) {
+			return original, false, nil
+		}
+		servers.set(mcpServerName, entry)
+	}
+
+	rawServers, err := servers.MarshalJSON()
+	if err != nil {
+		return nil, false, err
+	}
+	config.set(client.ServersKey, rawServers)
+	compact, err := config.MarshalJSON()
+	if err != nil {
+		return nil, false, err
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, compact, "", "  "); err != nil {
+		return nil, false, fmt.Errorf("formatting config: %w", err)
+	}
+	indented.WriteByte('\n')
+	return indented.Bytes(), true, nil
+}
+
+// writeMCPConfig replaces the config through a temporary file, so a client
+// never reads a half-written config. A symlinked config, e.g. from a dotfiles
+// repository, is written through to its target.
+func writeMCPConfig(path string, data []byte, mode fs.FileMode) error {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	err := writeFileAtomically(path, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("writing config file: %w", err)
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		return fmt.Errorf("setting config file permissions: %w", err)
+	}
+	return nil
+}
+
+// stripJSONC turns JSON with comments and trailing commas, as VS Code and Zed
+// settings allow, into plain JSON.
+func stripJSONC(data []byte) []byte {
+	return stripTrailingCommas(stripJSONComments(data))
+}
+
+// stripJSONComments blanks // and /* */ comments outside strings, keeping
+// line breaks so parse errors keep their offsets.
+func stripJSONComments(data []byte) []byte {
+	stripped := make([]byte, 0, len(data))
+	inString, escaped := false, false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			if escaped {
+				escaped = false
+			} else if c == '\\' {
+				escaped = true
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				stripped = append(stripped, ' ')
+				i++
+			}
+			if i < len(data) {
+				stripped = append(stripped, '\n')
+			}
+			continue
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				end = len(data)
+			} else {
+				end += i + 4
+			}
+			for ; i < end; i++ {
+				if data[i] == '\n' {
+					stripped = append(stripped, '\n')
+				} else {
+					stripped = append(stripped, ' ')
+				}
+			}
+			i--
+			continue
+		}
+		stripped = append(stripped, c)
+	}
+	return stripped
+}
+
+// stripTrailingCommas blanks commas that directly precede a closing bracket.
+func stripTrailingCommas(data []byte) []byte {
+	inString, escaped := false, false
+	for i, c := range data {
+		switch {
+		case inString:
+			if escaped {
+				escaped = false
+			} else if c == '\\' {
+				escaped = true
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == ',':
+			next := bytes.TrimLeft(data[i+1:], " \t\r\n")
+			if len(next) > 0 && (next[0] == '}' || next[0] == ']') {
+				data[i] = ' '
+			}
+		}
+	}
+	return data
+}
+
+func hasJSONComments(data []byte) bool {
+	return !bytes.Equal(stripJSONComments(data), data)
+}
+
+func writeMCPSnippet(out io.Writer, client mcpClient, server mcpServerConfig) error {
+	var data []byte
+	var err error
+	if client.Entry == nil {
+		data, err = toml.Marshal(map[string]map[string]mcpServerConfig{
+			client.ServersKey: {mcpServerName: server},
+		})
+	} else {
+		data, err = json.MarshalIndent(map[string]map[string]any{
+			client.ServersKey: {mcpServerName: client.Entry(server)},
+		}, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return fmt.Errorf("marshaling %s snippet: %w", client.Label, err)
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+// jsonObject is a JSON object that keeps its keys in order, so rewriting a
+// client config only changes the entries TL;DW owns.
+type jsonObject []jsonField
+
+type jsonField struct {
+	Key   string
+	Value json.RawMessage
+}
+
+func parseJSONObject(data []byte) (jsonObject, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil {
+		return nil, err
+	} else if token != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+	var object jsonObject
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := token.(string)
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		object = append(object, jsonField{Key: key, Value: value})
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the JSON object")
+	}
+	return object, nil
+}
+
+func (o jsonObject) get(key string) (json.RawMessage, bool) {
+	for _, field := range o {
+		if field.Key == key {
+			return field.Value, true
+		}
+	}
+	return nil, false
+}
+
+func (o *jsonObject) set(key string, value json.RawMessage) {
+	for i, field := range *o {
+		if field.Key == key {
+			(*o)[i].Value = value
+			return
+		}
+	}
+	*o = append(*o, jsonField{Key: key, Value: value})
+}
+
+func (o *jsonObject) remove(key string) bool {
+	for i, field := range *o {
+		if field.Key == key {
+			*o = slices.Delete(*o, i, i+1)
+			return true
+		}
+	}
+	return false
+}
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(field.Value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func jsonEqual(a, b []byte) bool {
+	var left, right bytes.Buffer
+	if json.Compact(&left, a) != nil || json.Compact(&right, b) != nil {
+		return false
+	}
+	return bytes.Equal(left.Bytes(), right.Bytes())
+}
+
+// lineDiff renders the changes from before to after as a unified-style diff
+// with a few lines of context.
+func lineDiff(path, before, after string) string {
+	const context = 3
+	a := splitLines(before)
+	b := splitLines(after)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			lines = append(lines, line{'+', b[j]})
+			j++
+		default:
+			lines = append(lines, line{'-', a[i]})
+			i++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", path, path)
+	lastShown := -1
+	for k, l := range lines {
+		near := false
+		for d := max(0, k-context); d <= min(len(lines)-1, k+context); d++ {
+			if lines[d].op != ' ' {
+				near = true
+				break
+			}
+		}
+		if !near {
+			continue
+		}
+		if lastShown >= 0 && k > lastShown+1 {
+			sb.WriteString("@@\n")
+		}
+		sb.WriteByte(l.op)
+		sb.WriteString(l.text)
+		sb.WriteByte('\n')
+		lastShown = k
+	}
+	return sb.String()
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+func homeConfigPath(elements ...string) func() (string, error) {
+	return func() (string, error) {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(append([]string{homeDir}, elements...)...), nil
+	}
+}
+
+// vscodeConfigPath returns VS Code's user-level MCP config.
+func vscodeConfigPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "Code", "User", "mcp.json"), nil
+}
+
+// zedConfigPath returns Zed's settings file, which is under ~/.config on
+// macOS as well.
+func zedConfigPath() (string, error) {
+	if runtime.GOOS == "windows" {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(configDir, "Zed", "settings.json"), nil
+	}
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return filepath.Join(configHome, "zed", "settings.json"), nil
+	}
+	return homeConfigPath(".config", "zed", "settings.json")()
+}
+
+// getClaudeDesktopConfigPath returns the platform-specific config path for Claude Desktop
+func getClaudeDesktopConfigPath() (string, error) {
+	var configPath string
+
+	switch runtime.GOOS {
+	case "darwin":
+		// macOS: ~/Library/Application Support/Claude/claude_desktop_config.json
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configPath = filepath.Join(homeDir, "Library", "Application Support", "Claude", "claude_desktop_config.json")
+
+	case "windows":
+		// Windows: %APPDATA%/Claude/claude_desktop_config.json
+		appData := os.Getenv("APPDATA")
+		if appData == "" {
+			return "", fmt.Errorf("APPDATA environment variable not set")
+		}
+		configPath = filepath.Join(appData, "Claude", "claude_desktop_config.json")
+
+	case "linux":
+		// Linux: ~/.config/Claude/claude_desktop_config.json
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configPath = filepath.Join(homeDir, ".config", "Claude", "claude_desktop_config.json")
+
+	default:
+		return "", fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	}
+
+	return configPath, nil
+}
+
+func init() {
+	mcpSetupCmd.Flags().String("client", "", "Client to configure: "+strings.Join(mcpClientNames(), ", "))
+	mcpSetupCmd.Flags().String("path", "", "Config file to edit instead of the client's default")
+	mcpSetupCmd.Flags().Bool("dry-run", false, "Print the change as a diff without writing it")
+	mcpSetupCmd.Flags().Bool("remove", false, "Remove TL;DW from the client's config")
+	mcpCmd.AddCommand(mcpSetupCmd)
+	mcpCmd.AddCommand(setupClaudeCmd)
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testMCPServer() mcpServerConfig {
+	return mcpServerConfig{
+		Command: "/usr/local/bin/tldw",
+		Args:    []string{"mcp"},
+		Env:     map[string]string{"HOME": "/home/user"},
+	}
+}
+
+func TestMCPSetupCreatesMissingConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".cursor", "mcp.json")
+	var out bytes.Buffer
+	if err := runMCPSetup(&out, mcpSetupOptions{Client: "cursor", Path: path}, testMCPServer()); err != nil {
+		t.Fatalf("runMCPSetup() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading config: %v", err)
+	}
+	want := `{
+  "mcpServers": {
+    "tldw": {
+      "command": "/usr/local/bin/tldw",
+      "args": [
+        "mcp"
+      ],
+      "env": {
+        "HOME": "/home/user"
+      }
+    }
+  }
+}
+`
+	if string(data) != want {
+		t.Fatalf("config = %s, want %s", data, want)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Fatalf("backup of a new config exists: %v", err)
+	}
+}
+
+func TestMCPSetupPreservesOtherSettingsAndBacksUp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	original := `{"theme": "One Dark", "context_servers": {"other": {"command": "other-server"}}, "vim_mode": true}`
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runMCPSetup(&out, mcpSetupOptions{Client: "zed", Path: path}, testMCPServer()); err != nil {
+		t.Fatalf("runMCPSetup() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	theme, other, tldw, vim := strings.Index(text, `"theme"`), strings.Index(text, `"other"`),
+		strings.Index(text, `"tldw"`), strings.Index(text, `"vim_mode"`)
+	if theme < 0 || !(theme < other && other < tldw && tldw < vim) {
+		t.Fatalf("config lost or reordered keys:\n%s", text)
+	}
+	if !strings.Contains(text, `"source": "custom"`) {
+		t.Fatalf("zed entry is missing source:\n%s", text)
+	}
+	backup, err := os.ReadFile(path + ".bak")
+	if err != nil || string(backup) != original {
+		t.Fatalf("backup = %q, %v, want the original config", backup, err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("config mode = %v, %v, want 0600 kept", info.Mode().Perm(), err)
+	}
+
+	out.Reset()
+	if err := runMCPSetup(&out, mcpSetupOptions{Client: "zed", Path: path}, testMCPServer()); err != nil {
+		t.Fatalf("second runMCPSetup() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "already configured") {
+		t.Fatalf("second run output = %q, want no change", out.String())
+	}
+}
+
+func TestMCPSetupDryRunPrintsDiffWithoutWriting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.json")
+	original := "{\n  \"servers\": {}\n}\n"
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runMCPSetup(&out, mcpSetupOptions{Client: "vscode", Path: path, DryRun: true}, testMCPServer()); err != nil {
+		t.Fatalf("runMCPSetup() error = %v", err)
+	}
+	diff := out.String()
+	for _, want := range []string{"--- " + path, `-  "servers": {}`, `+  "servers": {`, `+      "type": "stdio",`} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff is missing %q:\n%s", want, diff)
+		}
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Fatalf("dry run changed the config to %s", data)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Fatalf("dry run wrote a backup: %v", err)
+	}
+}
+
+func TestMCPSetupRemoveDeletesOnlyTLDW(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "claude_desktop_config.json")
+	var out bytes.Buffer
+	if err := os.WriteFile(path, []byte(`{"mcpServers": {"other": {"command": "x"}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runMCPSetup(&out, mcpSetupOptions{Client: "claude", Path: path}, testMCPServer()); err != nil {
+		t.Fatalf("setup error = %v", err)
+	}
+	if err := runMCPSetup(&out, mcpSetupOptions{Client: "claude", Path: path, Remove: true}, testMCPServer()); err != nil {
+		t.Fatalf("remove error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "tldw") || !strings.Contains(string(data), `"other"`) {
+		t.Fatalf("config after remove = %s", data)
+	}
+
+	out.Reset()
+	if err := runMCPSetup(&out, mcpSetupOptions{Client: "claude", Path: path, Remove: true}, testMCPServer()); err != nil {
+		t.Fatalf("second remove error = %v", err)
+	}
+	if !strings.Contains(out.String(), "not configured") {
+		t.Fatalf("second remove output = %q", out.String())
+	}
+}
+
+func TestMCPSetupAcceptsCommentedZedSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	original := `// Zed settings
+{
+  /* "theme": "Ayu", */
+  "theme": "One Dark", // see https://zed.dev/docs/themes
+  "context_servers": {
+    "other": {"command": "other-server",},
+  },
+}
+`
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runMCPSetup(&out, mcpSetupOptions{Client: "zed", Path: path}, testMCPServer()); err != nil {
+		t.Fatalf("runMCPSetup() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	for _, want := range []string{`"theme": "One Dark"`, `"other"`, `"tldw"`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("config is missing %s:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Ayu") {
+		t.Errorf("config kept a commented-out setting:\n%s", text)
+	}
+	if !strings.Contains(out.String(), "Comments in "+path+" are not kept") {
+		t.Errorf("output = %q, want a note about dropped comments", out.String())
+	}
+	if backup, err := os.ReadFile(path + ".bak"); err != nil || string(backup) != original {
+		t.Fatalf("backup = %q, %v, want the commented original", backup, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 2 {
+		t.Fatalf("config directory has %d files, want the config and its backup", len(entries))
+	}
+}
+
+func TestMCPSetupRejectsUnparseableConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	original := "{\"theme\": \"One Dark\"\n"
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := runMCPSetup(&bytes.Buffer{}, mcpSetupOptions{Client: "zed", Path: path}, testMCPServer())
+	if err == nil || !strings.Contains(err.Error(), "--client json") {
+		t.Fatalf("runMCPSetup() error = %v, want a hint to use the snippet", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Fatalf("config changed to %s", data)
+	}
+}
+
+func TestMCPSetupPrintsSnippets(t *testing.T) {
+	var out bytes.Buffer
+	if err := runMCPSetup(&out, mcpSetupOptions{Client: "toml"}, testMCPServer()); err != nil {
+		t.Fatalf("toml snippet error = %v", err)
+	}
+	if !strings.Contains(out.String(), "[mcp_servers.tldw]") || !strings.Contains(out.String(), "command = '/usr/local/bin/tldw'") {
+		t.Fatalf("toml snippet = %s", out.String())
+	}
+
+	out.Reset()
+	if err := runMCPSetup(&out, mcpSetupOptions{Client: "json"}, testMCPServer()); err != nil {
+		t.Fatalf("json snippet error = %v", err)
+	}
+	if !strings.Contains(out.String(), `"mcpServers": {`) {
+		t.Fatalf("json snippet = %s", out.String())
+	}
+
+	if err := runMCPSetup(&out, mcpSetupOptions{Client: "json", Remove: true}, testMCPServer()); err == nil {
+		t.Fatal("runMCPSetup() removed a snippet")
+	}
+	if err := runMCPSetup(&out, mcpSetupOptions{Client: "emacs"}, testMCPServer()); err == nil {
+		t.Fatal("runMCPSetup() accepted an unknown client")
+	}
+}
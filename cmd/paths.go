@@ -17,6 +17,7 @@ var pathsCmd = &cobra.Command{
 		fmt.Printf("Data directory: %s\n", config.DataDir)
 		fmt.Printf("Cache directory: %s\n", config.CacheDir)
 		fmt.Printf("Transcripts directory: %s\n", config.TranscriptsDir)
+		fmt.Printf("Watch output directory: %s\n", config.WatchOutputDir)
 	},
 }
 
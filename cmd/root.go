@@ -48,6 +48,9 @@ or by editing the config file at $XDG_CONFIG_HOME/tldw/config.toml.`,
   # Fallback to Whisper if no captions available (costs money)
   tldw "https://youtu.be/tAP1eZYEuKA" --fallback-whisper
 
+  # Fetch an age-restricted video with your browser's cookies
+  tldw "https://youtu.be/tAP1eZYEuKA" --cookies-from-browser firefox
+
   # Run quietly without progress bars or extra output
   tldw "https://youtu.be/tAP1eZYEuKA" --quiet`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
@@ -65,6 +68,9 @@ or by editing the config file at $XDG_CONFIG_HOME/tldw/config.toml.`,
 		if err := internal.EnsureDefaultPrompt(config.ConfigDir); err != nil {
 			return fmt.Errorf("ensuring default prompt: %w", err)
 		}
+		if err := applyYtDLPFlags(cmd, config); err != nil {
+			return err
+		}
 		return applyOutputFlags(cmd, config)
 	},
 	Args: cobra.ExactArgs(1),
@@ -119,5 +125,6 @@ func init() {
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose output for debugging")
 	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Suppress progress bars and non-essential output")
 	rootCmd.PersistentFlags().StringP("config", "c", "", "Config file (default is $XDG_CONFIG_HOME/tldw/config.toml)")
+	addYtDLPFlags(rootCmd)
 
 }
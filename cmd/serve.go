@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rtzll/tldw/internal/api"
+	"github.com/rtzll/tldw/internal/feed"
+	"github.com/rtzll/tldw/internal/httpguard"
+	"github.com/rtzll/tldw/internal/ui"
+)
+
+// feedPath is where tldw serve --feed publishes the Atom feed.
+const feedPath = "/feed.xml"
+
+type serveApplication interface {
+	feed.Source
+	api.Application
+}
+
+type serveApplicationFactory func() (serveApplication, error)
+
+type serveOptions struct {
+	Feed           bool
+	API            bool
+	UI             bool
+	RequestTimeout time.Duration
+	// Host is the listen host. Other hosts than loopback need Tokens.
+	Host string
+	// Tokens, AllowedHosts, and AllowedOrigins are shared with the MCP HTTP
+	// transport.
+	Tokens         map[string]string
+	AllowedHosts   []string
+	AllowedOrigins []string
+}
+
+func newServeCommand(build serveApplicationFactory, now func() time.Time) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the library over HTTP",
+		Long: `Serve parts of the local library over HTTP.
+
+--feed publishes the latest summaries as an Atom feed at /feed.xml. The channel,
+tag, and limit query parameters filter it, e.g. /feed.xml?channel=cmu&tag=postgres.
+
+--api exposes metadata, transcripts, summaries, playlist summaries, and stats as
+a JSON API under /api/v1/, described by /api/v1/openapi.json. Summaries and
+Whisper transcription cost money, so only expose it to trusted clients.
+
+--ui serves a dashboard at / for browsing cached videos, reading transcripts,
+generating summaries, and charting stats. It runs on the JSON API, so --ui also
+serves /api/v1/.
+
+Requests must use localhost, a loopback address, the --host address, or a host
+in mcp_allowed_hosts, and browsers must come from a loopback page or an origin
+in mcp_allowed_origins. Listening on another host than loopback needs the bearer
+tokens of tldw mcp (mcp_tokens or mcp_tokens_file); with tokens configured, the
+feed and API require "Authorization: Bearer <token>" and the dashboard asks for
+one.`,
+		Example: `  # Serve the summary feed on localhost port 8766
+  tldw serve --feed
+
+  # Serve the JSON API alongside the feed
+  tldw serve --feed --api
+
+  # Browse the library at http://127.0.0.1:8766/
+  tldw serve --ui
+
+  # Serve on every interface
+  tldw serve --feed --host 0.0.0.0 --port 8080`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var options serveOptions
+			var err error
+			if options.Feed, err = cmd.Flags().GetBool("feed"); err != nil {
+				return err
+			}
+			if options.API, err = cmd.Flags().GetBool("api"); err != nil {
+				return err
+			}
+			if options.UI, err = cmd.Flags().GetBool("ui"); err != nil {
+				return err
+			}
+			if options.RequestTimeout, err = cmd.Flags().GetDuration("request-timeout"); err != nil {
+				return err
+			}
+			if options.Host, err = cmd.Flags().GetString("host"); err != nil {
+				return err
+			}
+			if options.Tokens, err = config.MCPHTTP.LoadTokens(); err != nil {
+				return err
+			}
+			options.AllowedHosts = config.MCPHTTP.AllowedHosts
+			options.AllowedOrigins = config.MCPHTTP.AllowedOrigins
+			port, err := cmd.Flags().GetInt("port")
+			if err != nil {
+				return err
+			}
+			if err := checkServeHost(options); err != nil {
+				return err
+			}
+			app, err := build()
+			if err != nil {
+				return fmt.Errorf("building application: %w", err)
+			}
+			handler, err := newServeHandler(app, options, now)
+			if err != nil {
+				return err
+			}
+
+			addr := net.JoinHostPort(options.Host, strconv.Itoa(port))
+			if options.Feed {
+				if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Serving feed at http://%s%s\n", addr, feedPath); err != nil {
+					return err
+				}
+			}
+			if options.UI {
+				if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Serving dashboard at http://%s/\n", addr); err != nil {
+					return err
+				}
+			}
+			if options.API || options.UI {
+				if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Serving API at http://%s%s\n", addr, api.Prefix); err != nil {
+					return err
+				}
+			}
+			return listenAndServe(cmd.Context(), addr, handler)
+		},
+	}
+	command.Flags().Bool("feed", false, "Serve the summary Atom feed at "+feedPath)
+	command.Flags().Bool("api", false, "Serve the JSON API under "+api.Prefix)
+	command.Flags().Bool("ui", false, "Serve the library dashboard at / (includes the JSON API)")
+	command.Flags().Duration("request-timeout", api.DefaultTimeout, "Cancel API requests that take longer than this")
+	command.Flags().String("host", "127.0.0.1", "Host to listen on")
+	command.Flags().Int("port", 8766, "Port to listen on")
+	return command
+}
+
+// checkServeHost refuses to expose paid endpoints beyond loopback without
+// tokens.
+func checkServeHost(options serveOptions) error {
+	if len(options.Tokens) == 0 && !httpguard.IsLoopbackHost(options.Host) {
+		return fmt.Errorf("refusing to serve on %s without tokens; set mcp_tokens or mcp_tokens_file", options.Host)
+	}
+	return nil
+}
+
+// newServeHandler routes the enabled endpoints behind the Host and Origin
+// checks. With tokens, the feed and API need one; the dashboard's static
+// files carry no data and stay open so that it can ask for a token.
+func newServeHandler(app serveApplication, options serveOptions, now func() time.Time) (http.Handler, error) {
+	if !options.Feed && !options.API && !options.UI {
+		return nil, errors.New("nothing to serve; pass --feed, --api, or --ui")
+	}
+	if err := checkServeHost(options); err != nil {
+		return nil, err
+	}
+	authenticate := func(handler http.Handler) http.Handler { return handler }
+	if len(options.Tokens) > 0 {
+		authenticate = func(handler http.Handler) http.Handler {
+			return httpguard.BearerToken(options.Tokens, nil, handler)
+		}
+	}
+
+	mux := http.NewServeMux()
+	if options.Feed {
+		mux.Handle(feedPath, authenticate(feed.Handler(app, now)))
+	}
+	if options.API || options.UI {
+		mux.Handle(api.Prefix, authenticate(api.Handler(app, options.RequestTimeout)))
+	}
+	if options.UI {
+		mux.Handle("/", ui.Handler())
+	}
+	hosts := httpguard.AllowedHosts(options.Host, options.AllowedHosts)
+	return httpguard.HostAndOrigin(hosts, options.AllowedOrigins, nil, mux), nil
+}
+
+// listenAndServe runs an HTTP server until ctx is cancelled, then gives
+// in-flight requests a few seconds to finish.
+func listenAndServe(ctx context.Context, addr string, handler http.Handler) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			_ = server.Close()
+		}
+		if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	case err := <-errCh:
+		return fmt.Errorf("serving on %s: %w", addr, err)
+	}
+}
+
+var serveCmd = newServeCommand(func() (serveApplication, error) {
+	return newEngine(config)
+}, time.Now)
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+}
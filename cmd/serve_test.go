@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+type serveApplicationStub struct {
+	feedApplicationStub
+	policies []tldw.TranscriptPolicy
+}
+
+func (stub *serveApplicationStub) MetadataFor(context.Context, tldw.YouTubeRef) (*tldw.VideoMetadata, error) {
+	return &tldw.VideoMetadata{Title: "B-trees"}, nil
+}
+
+func (stub *serveApplicationStub) Transcript(_ context.Context, _ tldw.YouTubeRef, request tldw.TranscriptRequest) (*tldw.Transcript, error) {
+	stub.policies = append(stub.policies, request.Policy)
+	return &tldw.Transcript{Text: "hello"}, nil
+}
+
+func (stub *serveApplicationStub) SummarizeVideo(context.Context, tldw.YouTubeRef, tldw.SummaryRequest) (tldw.Summary, error) {
+	return tldw.Summary{Markdown: "## Summary"}, nil
+}
+
+func (stub *serveApplicationStub) CreatePlaylistSummary(context.Context, tldw.YouTubeRef, tldw.PlaylistSummaryRequest) (tldw.PlaylistSummaryResult, error) {
+	return tldw.PlaylistSummaryResult{}, nil
+}
+
+func (stub *serveApplicationStub) Stats(tldw.StatsQuery) (tldw.StatsReport, error) {
+	return tldw.StatsReport{}, nil
+}
+
+func (stub *serveApplicationStub) Library(tldw.LibraryQuery) ([]tldw.LibraryEntry, error) {
+	return nil, nil
+}
+
+func (stub *serveApplicationStub) CachedSummary(tldw.YouTubeRef) (*tldw.StoredSummary, error) {
+	return nil, tldw.ErrStoreNotFound
+}
+
+func TestServeHandlerRoutesEnabledEndpoints(t *testing.T) {
+	stub := &serveApplicationStub{}
+	if _, err := newServeHandler(stub, serveOptions{Host: "127.0.0.1"}, time.Now); err == nil {
+		t.Fatal("newServeHandler() accepted no endpoints")
+	}
+	handler, err := newServeHandler(stub, serveOptions{Feed: true, Host: "127.0.0.1"}, time.Now)
+	if err != nil {
+		t.Fatalf("newServeHandler() error = %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8766/feed.xml?channel=cmu", nil))
+	if recorder.Code != http.StatusOK || stub.query.Channel != "cmu" {
+		t.Fatalf("GET /feed.xml status = %d, query %+v", recorder.Code, stub.query)
+	}
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8766/api/v1/stats", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("GET /api/v1/stats without --api status = %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+
+	handler, err = newServeHandler(stub, serveOptions{API: true, RequestTimeout: time.Minute, Host: "127.0.0.1"}, time.Now)
+	if err != nil {
+		t.Fatalf("newServeHandler() error = %v", err)
+	}
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8766/api/v1/videos/dQw4w9WgXcQ/metadata", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("GET metadata status = %d, body %s", recorder.Code, recorder.Body.String())
+	}
+
+	handler, err = newServeHandler(stub, serveOptions{UI: true, Host: "127.0.0.1"}, time.Now)
+	if err != nil {
+		t.Fatalf("newServeHandler() error = %v", err)
+	}
+	for _, path := range []string{"/", "/api/v1/library"} {
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8766"+path, nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("GET %s with --ui status = %d", path, recorder.Code)
+		}
+	}
+}
+
+func TestServeHandlerRequiresTokensOffLoopback(t *testing.T) {
+	stub := &serveApplicationStub{}
+	for _, host := range []string{"0.0.0.0", "192.168.1.20"} {
+		if _, err := newServeHandler(stub, serveOptions{API: true, Host: host}, time.Now); err == nil {
+			t.Errorf("newServeHandler(%q) succeeded without tokens", host)
+		}
+	}
+
+	handler, err := newServeHandler(stub, serveOptions{
+		API: true, UI: true, Feed: true, Host: "0.0.0.0", RequestTimeout: time.Minute,
+		Tokens: map[string]string{"laptop": "secret-1"},
+	}, time.Now)
+	if err != nil {
+		t.Fatalf("newServeHandler() error = %v", err)
+	}
+	tests := []struct {
+		name  string
+		path  string
+		token string
+		want  int
+	}{
+		{"API without token", "/api/v1/library", "", http.StatusUnauthorized},
+		{"feed without token", "/feed.xml", "", http.StatusUnauthorized},
+		{"API with token", "/api/v1/library", "secret-1", http.StatusOK},
+		{"dashboard without token", "/", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "http://192.168.1.20:8766"+tt.path, nil)
+			if tt.token != "" {
+				request.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			if recorder.Code != tt.want {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestServeHandlerRejectsCrossSiteRequests(t *testing.T) {
+	handler, err := newServeHandler(&serveApplicationStub{}, serveOptions{API: true, Host: "127.0.0.1", RequestTimeout: time.Minute}, time.Now)
+	if err != nil {
+		t.Fatalf("newServeHandler() error = %v", err)
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:8766/api/v1/videos/dQw4w9WgXcQ/summary", strings.NewReader(`{"whisper":"only"}`))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Origin", "https://attacker.example")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("cross-origin POST status = %d, want %d", recorder.Code, http.StatusForbidden)
+	}
+
+	request = httptest.NewRequest(http.MethodGet, "http://attacker.example:8766/api/v1/library", nil)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("rebound Host status = %d, want %d", recorder.Code, http.StatusForbidden)
+	}
+}
+
+func TestServeHandlerKeepsSimpleGETsAwayFromWhisper(t *testing.T) {
+	stub := &serveApplicationStub{}
+	handler, err := newServeHandler(stub, serveOptions{API: true, Host: "127.0.0.1", RequestTimeout: time.Minute}, time.Now)
+	if err != nil {
+		t.Fatalf("newServeHandler() error = %v", err)
+	}
+
+	// An <img> or <script> tag on any page sends this GET without an Origin.
+	for _, whisper := range []string{"fallback", "only"} {
+		request := httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8766/api/v1/videos/dQw4w9WgXcQ/transcript?whisper="+whisper, nil)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("GET with whisper=%s: status = %d, want %d", whisper, recorder.Code, http.StatusBadRequest)
+		}
+	}
+	if len(stub.policies) != 0 {
+		t.Fatalf("GET requests reached the engine with policies %v", stub.policies)
+	}
+}
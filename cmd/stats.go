@@ -19,13 +19,6 @@ type statsApplication interface {
 
 type statsApplicationFactory func() (statsApplication, error)
 
-type statsPeriod struct {
-	name  string
-	label string
-	from  time.Time
-	to    time.Time
-}
-
 type statsJSONOutput struct {
 	Period          string             `json:"period"`
 	From            string             `json:"from,omitempty"`
@@ -54,7 +47,7 @@ func newStatsCommand(build statsApplicationFactory, now func() time.Time) *cobra
 			if err != nil {
 				return err
 			}
-			period, err := resolveStatsPeriod(periodName, currentTime)
+			period, err := tldw.ResolveStatsPeriod(periodName, currentTime)
 			if err != nil {
 				return err
 			}
@@ -62,7 +55,7 @@ func newStatsCommand(build statsApplicationFactory, now func() time.Time) *cobra
 			if err != nil {
 				return err
 			}
-			group, err := parseStatsGroup(groupName)
+			group, err := tldw.ParseStatsGroup(groupName)
 			if err != nil {
 				return err
 			}
@@ -71,7 +64,7 @@ func newStatsCommand(build statsApplicationFactory, now func() time.Time) *cobra
 				return fmt.Errorf("building application: %w", err)
 			}
 			report, err := app.Stats(tldw.StatsQuery{
-				From: period.from, To: period.to, GroupBy: group, Location: currentTime.Location(),
+				From: period.From, To: period.To, GroupBy: group, Location: currentTime.Location(),
 			})
 			if err != nil {
 				return err
@@ -92,52 +85,16 @@ func newStatsCommand(build statsApplicationFactory, now func() time.Time) *cobra
 	return command
 }
 
-func resolveStatsPeriod(name string, now time.Time) (statsPeriod, error) {
-	name = strings.ToLower(strings.TrimSpace(name))
-	location := now.Location()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
-	switch name {
-	case "today", "day":
-		return statsPeriod{name: "today", label: "today", from: today, to: today.AddDate(0, 0, 1)}, nil
-	case "week":
-		weekdayOffset := (int(today.Weekday()) + 6) % 7
-		from := today.AddDate(0, 0, -weekdayOffset)
-		return statsPeriod{name: name, label: "this week", from: from, to: from.AddDate(0, 0, 7)}, nil
-	case "month":
-		from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location)
-		return statsPeriod{name: name, label: "this month", from: from, to: from.AddDate(0, 1, 0)}, nil
-	case "all":
-		return statsPeriod{name: name, label: "all time"}, nil
-	default:
-		return statsPeriod{}, fmt.Errorf("unsupported period %q: use today, week, month, or all", name)
-	}
-}
-
-func parseStatsGroup(name string) (tldw.StatsGroup, error) {
-	switch strings.ToLower(strings.TrimSpace(name)) {
-	case "", "none":
-		return tldw.StatsGroupNone, nil
-	case "day":
-		return tldw.StatsGroupDay, nil
-	case "week":
-		return tldw.StatsGroupWeek, nil
-	case "month":
-		return tldw.StatsGroupMonth, nil
-	default:
-		return tldw.StatsGroupNone, fmt.Errorf("unsupported grouping %q: use day, week, or month", name)
-	}
-}
-
-func writeStatsJSON(writer io.Writer, period statsPeriod, report tldw.StatsReport) error {
+func writeStatsJSON(writer io.Writer, period tldw.StatsPeriod, report tldw.StatsReport) error {
 	output := statsJSONOutput{
-		Period: period.name, VideoCount: report.VideoCount, DurationSeconds: report.DurationSeconds,
+		Period: period.Name, VideoCount: report.VideoCount, DurationSeconds: report.DurationSeconds,
 		Groups: report.Groups,
 	}
-	if !period.from.IsZero() {
-		output.From = period.from.Format(time.RFC3339)
+	if !period.From.IsZero() {
+		output.From = period.From.Format(time.RFC3339)
 	}
-	if !period.to.IsZero() {
-		output.To = period.to.Format(time.RFC3339)
+	if !period.To.IsZero() {
+		output.To = period.To.Format(time.RFC3339)
 	}
 	data, err := json.MarshalIndent(output, "", "  ")
 	if err != nil {
@@ -147,9 +104,9 @@ func writeStatsJSON(writer io.Writer, period statsPeriod, report tldw.StatsRepor
 	return err
 }
 
-func writeStatsText(writer io.Writer, period statsPeriod, group tldw.StatsGroup, report tldw.StatsReport) error {
+func writeStatsText(writer io.Writer, period tldw.StatsPeriod, group tldw.StatsGroup, report tldw.StatsReport) error {
 	duration := formatStatsDuration(report.DurationSeconds)
-	if _, err := fmt.Fprintf(writer, "tldw stats — %s\n", period.label); err != nil {
+	if _, err := fmt.Fprintf(writer, "tldw stats — %s\n", period.Label); err != nil {
 		return err
 	}
 	if _, err := fmt.Fprintf(writer, "%d unique %s\n", report.VideoCount, plural(report.VideoCount, "video", "videos")); err != nil {
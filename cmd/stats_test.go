@@ -90,46 +90,6 @@ func TestFormatStatsDurationUsesWeeksAndDaysForLargeTotals(t *testing.T) {
 	}
 }
 
-func TestResolveStatsPeriodUsesLocalCalendarBoundaries(t *testing.T) {
-	berlin, err := time.LoadLocation("Europe/Berlin")
-	if err != nil {
-		t.Fatalf("LoadLocation() error = %v", err)
-	}
-	now := time.Date(2026, time.July, 19, 23, 30, 0, 0, berlin) // Sunday
-	tests := []struct {
-		name     string
-		wantFrom time.Time
-		wantTo   time.Time
-	}{
-		{
-			name:     "today",
-			wantFrom: time.Date(2026, time.July, 19, 0, 0, 0, 0, berlin),
-			wantTo:   time.Date(2026, time.July, 20, 0, 0, 0, 0, berlin),
-		},
-		{
-			name:     "week",
-			wantFrom: time.Date(2026, time.July, 13, 0, 0, 0, 0, berlin),
-			wantTo:   time.Date(2026, time.July, 20, 0, 0, 0, 0, berlin),
-		},
-		{
-			name:     "month",
-			wantFrom: time.Date(2026, time.July, 1, 0, 0, 0, 0, berlin),
-			wantTo:   time.Date(2026, time.August, 1, 0, 0, 0, 0, berlin),
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			period, err := resolveStatsPeriod(tt.name, now)
-			if err != nil {
-				t.Fatalf("resolveStatsPeriod() error = %v", err)
-			}
-			if !period.from.Equal(tt.wantFrom) || !period.to.Equal(tt.wantTo) {
-				t.Fatalf("resolveStatsPeriod() = %v to %v, want %v to %v", period.from, period.to, tt.wantFrom, tt.wantTo)
-			}
-		})
-	}
-}
-
 func TestStatsCommandRejectsUnknownPeriodAndGrouping(t *testing.T) {
 	build := func() (statsApplication, error) { return &statsApplicationStub{}, nil }
 	now := func() time.Time { return time.Now() }
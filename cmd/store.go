@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+type storeApplication interface {
+	CheckStore() ([]tldw.StoreIssue, error)
+	MigrateStore(context.Context) ([]tldw.StoreChange, error)
+}
+
+type storeApplicationFactory func() (storeApplication, error)
+
+func newStoreCommand(build storeApplicationFactory) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "store",
+		Short: "Check and upgrade the transcript store",
+		Long: `Check and upgrade the on-disk transcript and metadata store.
+
+fsck reports orphaned temporary files, unparsable entries, metadata written by
+older versions, transcripts that only exist as plain text, and a library
+catalog that no longer matches the entry files. migrate resolves them in
+place, fetching metadata from YouTube again where necessary and rebuilding the
+catalog.`,
+		Example: `  # Report problems without changing anything
+  tldw store fsck
+
+  # Upgrade and repair every entry
+  tldw store migrate`,
+	}
+	command.AddCommand(newStoreCheckCommand(build), newStoreMigrateCommand(build))
+	return command
+}
+
+func newStoreCheckCommand(build storeApplicationFactory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "fsck",
+		Short: "Report problems in the transcript store",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			app, err := build()
+			if err != nil {
+				return fmt.Errorf("building application: %w", err)
+			}
+			issues, err := app.CheckStore()
+			if err != nil {
+				return err
+			}
+			if len(issues) == 0 {
+				_, err := fmt.Fprintln(cmd.OutOrStdout(), "No problems found")
+				return err
+			}
+			if err := writeStoreIssues(cmd.OutOrStdout(), issues); err != nil {
+				return err
+			}
+			return fmt.Errorf("found %d %s; run tldw store migrate to resolve %s", len(issues),
+				plural(len(issues), "problem", "problems"), plural(len(issues), "it", "them"))
+		},
+	}
+}
+
+func newStoreMigrateCommand(build storeApplicationFactory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade and repair entries in the transcript store",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			app, err := build()
+			if err != nil {
+				return fmt.Errorf("building application: %w", err)
+			}
+			changes, err := app.MigrateStore(cmd.Context())
+			if writeErr := writeStoreChanges(cmd.OutOrStdout(), changes); writeErr != nil {
+				return writeErr
+			}
+			if err != nil {
+				return err
+			}
+			failed := 0
+			for _, change := range changes {
+				if change.Err != nil {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d %s could not be resolved", failed, len(changes),
+					plural(len(changes), "problem", "problems"))
+			}
+			return nil
+		},
+	}
+}
+
+func writeStoreIssues(writer io.Writer, issues []tldw.StoreIssue) error {
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(table, "PROBLEM\tFILE\tDETAIL"); err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		if _, err := fmt.Fprintf(table, "%s\t%s\t%s\n", issue.Kind, issue.File, issue.Detail); err != nil {
+			return err
+		}
+	}
+	return table.Flush()
+}
+
+func writeStoreChanges(writer io.Writer, changes []tldw.StoreChange) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(writer, "Nothing to migrate")
+		return err
+	}
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	resolved := 0
+	for _, change := range changes {
+		result := change.Action
+		if change.Err != nil {
+			result = "failed: " + change.Err.Error()
+		} else {
+			resolved++
+		}
+		if _, err := fmt.Fprintf(table, "%s\t%s\t%s\n", change.Issue.File, change.Issue.Kind, result); err != nil {
+			return err
+		}
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(writer, "\nResolved %d of %d %s\n", resolved, len(changes), plural(len(changes), "problem", "problems"))
+	return err
+}
+
+var storeCmd = newStoreCommand(func() (storeApplication, error) {
+	return newEngine(config)
+})
+
+func init() {
+	rootCmd.AddCommand(storeCmd)
+}
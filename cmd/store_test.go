@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+type storeApplicationStub struct {
+	issues  []tldw.StoreIssue
+	changes []tldw.StoreChange
+}
+
+func (stub *storeApplicationStub) CheckStore() ([]tldw.StoreIssue, error) {
+	return stub.issues, nil
+}
+
+func (stub *storeApplicationStub) MigrateStore(context.Context) ([]tldw.StoreChange, error) {
+	return stub.changes, nil
+}
+
+func TestStoreFsckReportsIssuesAndFails(t *testing.T) {
+	stub := &storeApplicationStub{issues: []tldw.StoreIssue{{
+		Kind: tldw.StoreIssueStaleMetadata, VideoID: "dQw4w9WgXcQ", File: "dQw4w9WgXcQ.meta.json", Detail: "cache version 2, current is 3",
+	}}}
+	command := newStoreCommand(func() (storeApplication, error) { return stub, nil })
+	var output bytes.Buffer
+	command.SetOut(&output)
+	command.SetArgs([]string{"fsck"})
+
+	err := command.Execute()
+	if err == nil || !strings.Contains(err.Error(), "found 1 problem") {
+		t.Fatalf("store fsck error = %v, want a problem count", err)
+	}
+	for _, fragment := range []string{"stale-metadata", "dQw4w9WgXcQ.meta.json", "cache version 2"} {
+		if !strings.Contains(output.String(), fragment) {
+			t.Fatalf("store fsck output %q does not contain %q", output.String(), fragment)
+		}
+	}
+}
+
+func TestStoreMigrateReportsChanges(t *testing.T) {
+	stub := &storeApplicationStub{changes: []tldw.StoreChange{
+		{Issue: tldw.StoreIssue{Kind: tldw.StoreIssueLegacyTranscript, File: "dQw4w9WgXcQ.txt"}, Action: "wrote structured transcript from plain text"},
+		{Issue: tldw.StoreIssue{Kind: tldw.StoreIssueCorruptMetadata, File: "tAP1eZYEuKA.meta.json"}, Err: errors.New("video unavailable")},
+	}}
+	command := newStoreCommand(func() (storeApplication, error) { return stub, nil })
+	var output bytes.Buffer
+	command.SetOut(&output)
+	command.SetArgs([]string{"migrate"})
+
+	if err := command.Execute(); err == nil || !strings.Contains(err.Error(), "1 of 2 problems") {
+		t.Fatalf("store migrate error = %v, want one unresolved problem", err)
+	}
+	for _, fragment := range []string{"wrote structured transcript", "failed: video unavailable", "Resolved 1 of 2 problems"} {
+		if !strings.Contains(output.String(), fragment) {
+			t.Fatalf("store migrate output %q does not contain %q", output.String(), fragment)
+		}
+	}
+}
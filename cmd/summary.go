@@ -4,54 +4,89 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/rtzll/tldw/internal"
 	"github.com/rtzll/tldw/internal/tldw"
 )
 
-type summaryProgress struct {
-	bar     internal.Spinner
+type cliProgress struct {
+	bar     internal.ProgressBar
 	verbose bool
 }
 
-func newSummaryProgress(config *internal.Config, description string) *summaryProgress {
-	bar := internal.Spinner(internal.NoOpSpinner{})
+func newCLIProgress(config *internal.Config, description string) *cliProgress {
+	bar := internal.ProgressBar(internal.NoOpProgressBar{})
 	if !config.Quiet && !config.Verbose {
-		bar = internal.NewSpinner(description)
+		bar = internal.NewProgressBar(description)
 	}
-	return &summaryProgress{bar: bar, verbose: config.Verbose && !config.Quiet}
+	return &cliProgress{bar: bar, verbose: config.Verbose && !config.Quiet}
 }
 
-func (p *summaryProgress) update(description string) {
+func (p *cliProgress) update(description string) {
+	p.bar.Set(0, 0)
 	p.bar.Describe(description)
 	if p.verbose {
 		fmt.Printf("[Status] %s\n", description)
 	}
 }
 
-func (p *summaryProgress) finish() {
+// report shows an engine progress event. Events with a total, such as audio
+// downloads, fill the bar; verbose output skips their intermediate steps.
+func (p *cliProgress) report(progress tldw.Progress) {
+	if progress.Total <= 0 {
+		p.update(progress.Message)
+		return
+	}
+	p.bar.Describe(withoutPercent(progress.Message))
+	p.bar.Set(int64(progress.Current), int64(progress.Total))
+	if p.verbose && progress.Current >= progress.Total {
+		fmt.Printf("[Status] %s\n", progress.Message)
+	}
+}
+
+func (p *cliProgress) finish() {
 	p.bar.Finish()
 }
 
+// withProgress reports the engine's progress on *progress, which callers may
+// replace, e.g. after prompting the user.
+func withProgress(ctx context.Context, progress **cliProgress) context.Context {
+	return tldw.WithProgress(ctx, func(event tldw.Progress) {
+		(*progress).report(event)
+	})
+}
+
+// withoutPercent drops a trailing " (45%)" from a progress message, since the
+// bar shows the percentage itself.
+func withoutPercent(message string) string {
+	if i := strings.LastIndex(message, " ("); i >= 0 && strings.HasSuffix(message, "%)") {
+		return message[:i]
+	}
+	return message
+}
+
 func runSummary(ctx context.Context, engine *tldw.Engine, config *internal.Config, ref tldw.YouTubeRef, fallbackWhisper bool) error {
 	if ref.IsPlaylist() {
 		return runPlaylistSummary(ctx, engine, config, ref, fallbackWhisper)
 	}
 
-	progress := newSummaryProgress(config, "Processing video...")
+	progress := newCLIProgress(config, "Processing video...")
+	ctx = withProgress(ctx, &progress)
 	policy := tldw.TranscriptPolicyCaptionsOnly
 	if fallbackWhisper {
 		policy = tldw.TranscriptPolicyCaptionsThenWhisper
 	}
-	progress.update("Generating summary with OpenAI...")
-	summary, err := engine.SummarizeVideo(ctx, ref, tldw.TranscriptRequest{Policy: policy})
+	summary, err := engine.SummarizeVideo(ctx, ref, tldw.SummaryRequest{Transcript: tldw.TranscriptRequest{Policy: policy}})
 	if errors.Is(err, tldw.ErrCaptionsUnavailable) && !fallbackWhisper {
 		progress.finish()
 		if !askUser("Do you want to transcribe it using OpenAI's whisper ($$$)?") {
 			return fmt.Errorf("transcription declined by user")
 		}
-		progress = newSummaryProgress(config, "Transcribing with OpenAI Whisper...")
-		summary, err = engine.SummarizeVideo(ctx, ref, tldw.TranscriptRequest{Policy: tldw.TranscriptPolicyWhisperOnly})
+		progress = newCLIProgress(config, "Transcribing with OpenAI Whisper...")
+		summary, err = engine.SummarizeVideo(ctx, ref, tldw.SummaryRequest{
+			Transcript: tldw.TranscriptRequest{Policy: tldw.TranscriptPolicyWhisperOnly},
+		})
 	}
 	if err != nil {
 		progress.finish()
@@ -74,13 +109,20 @@ func runPlaylistSummary(ctx context.Context, engine *tldw.Engine, config *intern
 	}
 	if fallbackWhisper {
 		request.Transcript.Policy = tldw.TranscriptPolicyCaptionsThenWhisper
-	} else {
+	}
+	progress := newCLIProgress(config, "Processing playlist...")
+	ctx = withProgress(ctx, &progress)
+	if !fallbackWhisper {
 		request.ConfirmWhisper = func(video tldw.YouTubeRef, metadata *tldw.VideoMetadata) bool {
-			return askUser(fmt.Sprintf("Video %s: '%s' has no captions. Use Whisper ($$$)?", video.ID(), metadata.Title))
+			progress.finish()
+			confirmed := askUser(fmt.Sprintf("Video %s: '%s' has no captions. Use Whisper ($$$)?", video.ID(), metadata.Title))
+			progress = newCLIProgress(config, "Processing playlist...")
+			return confirmed
 		}
 	}
 
 	result, err := engine.CreatePlaylistSummary(ctx, ref, request)
+	progress.finish()
 	if err != nil {
 		return err
 	}
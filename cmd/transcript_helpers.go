@@ -25,10 +25,13 @@ func fetchTranscript(cmd *cobra.Command, app *tldw.Engine, arg string) (string,
 		return "", err
 	}
 	format := requestedTranscriptFormat(cmd)
+	progress := newCLIProgress(config, "Fetching transcript...")
+	defer progress.finish()
+	ctx := withProgress(cmd.Context(), &progress)
 
 	fallbackWhisper, _ := cmd.Flags().GetBool("fallback-whisper")
 	if format == tldw.TranscriptRenderFormatTimestamps {
-		transcript, err := app.Transcript(cmd.Context(), parsed, tldw.TranscriptRequest{
+		transcript, err := app.Transcript(ctx, parsed, tldw.TranscriptRequest{
 			Policy:            tldw.TranscriptPolicyCaptionsOnly,
 			RequireTimestamps: true,
 		})
@@ -45,7 +48,7 @@ func fetchTranscript(cmd *cobra.Command, app *tldw.Engine, arg string) (string,
 	if fallbackWhisper {
 		policy = tldw.TranscriptPolicyCaptionsThenWhisper
 	}
-	transcript, err := app.Transcript(cmd.Context(), parsed, tldw.TranscriptRequest{Policy: policy})
+	transcript, err := app.Transcript(ctx, parsed, tldw.TranscriptRequest{Policy: policy})
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rtzll/tldw/internal/tldw"
+	"github.com/rtzll/tldw/internal/watch"
+)
+
+type watchSettings func() (watch.List, string)
+
+type watchApplicationFactory func(cmd *cobra.Command) (watch.Application, error)
+
+func newWatchCommand(settings watchSettings, build watchApplicationFactory, now func() time.Time) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "watch",
+		Short: "Summarize new uploads from watched channels",
+		Long: `Keep a list of YouTube channels and summarize their new uploads.
+
+watch run checks every channel for uploads newer than the last one it handled,
+summarizes them, and writes one Markdown file per upload to watch_output_dir.
+Each channel's position is saved after every upload, so the command is safe to
+run from cron or a systemd timer. A failed upload is retried on later runs and
+skipped after --max-attempts runs, or at once when it has no captions.`,
+		Example: `  # Watch a channel, summarizing its three latest uploads on the first run
+  tldw watch add @CMUDatabaseGroup --backfill 3
+
+  # Summarize everything new since the last run
+  tldw watch run`,
+	}
+	command.AddCommand(
+		newWatchAddCommand(settings, now),
+		newWatchListCommand(settings),
+		newWatchRemoveCommand(settings),
+		newWatchRunCommand(settings, build, now),
+	)
+	return command
+}
+
+func newWatchAddCommand(settings watchSettings, now func() time.Time) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "add [CHANNEL]",
+		Short: "Watch a channel by @handle, channel ID, or URL",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ref, err := tldw.ParseChannelRef(args[0])
+			if err != nil {
+				return err
+			}
+			backfill, err := cmd.Flags().GetInt("backfill")
+			if err != nil {
+				return err
+			}
+			list, _ := settings()
+			channel, err := list.Add(ref, backfill, now())
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Watching %s\n", channel.ID)
+			return err
+		},
+	}
+	command.Flags().Int("backfill", 0, "Recent uploads to summarize on the first run (0 starts with the next upload)")
+	return command
+}
+
+func newWatchListCommand(settings watchSettings) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List watched channels",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			list, _ := settings()
+			channels, err := list.Load()
+			if err != nil {
+				return err
+			}
+			return writeWatchList(cmd.OutOrStdout(), channels)
+		},
+	}
+}
+
+func newWatchRemoveCommand(settings watchSettings) *cobra.Command {
+	return &cobra.Command{
+		Use:     "rm [CHANNEL]",
+		Aliases: []string{"remove"},
+		Short:   "Stop watching a channel",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ref, err := tldw.ParseChannelRef(args[0])
+			if err != nil {
+				return err
+			}
+			list, _ := settings()
+			if err := list.Remove(ref); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Stopped watching %s\n", ref.ID())
+			return err
+		},
+	}
+}
+
+func newWatchRunCommand(settings watchSettings, build watchApplicationFactory, now func() time.Time) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "run",
+		Short: "Summarize new uploads from every watched channel",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			list, outputDir := settings()
+			if override, err := cmd.Flags().GetString("output-dir"); err != nil {
+				return err
+			} else if override != "" {
+				outputDir = override
+			}
+			scanLimit, err := cmd.Flags().GetInt("scan-limit")
+			if err != nil {
+				return err
+			}
+			fallbackWhisper, err := cmd.Flags().GetBool("fallback-whisper")
+			if err != nil {
+				return err
+			}
+			request := tldw.TranscriptRequest{Policy: tldw.TranscriptPolicyCaptionsOnly}
+			if fallbackWhisper {
+				request.Policy = tldw.TranscriptPolicyCaptionsThenWhisper
+			}
+			app, err := build(cmd)
+			if err != nil {
+				return err
+			}
+
+			maxAttempts, err := cmd.Flags().GetInt("max-attempts")
+			if err != nil {
+				return err
+			}
+			runner := watch.Runner{
+				App: app, List: list, OutputDir: outputDir, Request: request, ScanLimit: scanLimit,
+				MaxAttempts: maxAttempts, Now: now,
+			}
+			results, err := runner.Run(cmd.Context())
+			failed, writeErr := writeWatchResults(cmd.OutOrStdout(), results)
+			if writeErr != nil {
+				return writeErr
+			}
+			if err != nil {
+				return err
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d %s failed; they will be retried on the next run", failed, plural(failed, "check", "checks"))
+			}
+			return nil
+		},
+	}
+	command.Flags().String("output-dir", "", "Directory for summaries (defaults to watch_output_dir)")
+	command.Flags().Int("scan-limit", 15, "Recent uploads to inspect per channel")
+	command.Flags().Int("max-attempts", watch.DefaultMaxAttempts, "Runs that retry a failing upload before skipping it")
+	command.Flags().Bool("fallback-whisper", false, "Fallback to Whisper if no captions available (costs money)")
+	addOpenAIFlags(command)
+	return command
+}
+
+func writeWatchList(writer io.Writer, channels []watch.Channel) error {
+	if len(channels) == 0 {
+		_, err := fmt.Fprintln(writer, "No watched channels")
+		return err
+	}
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(table, "CHANNEL\tLAST VIDEO\tLAST CHECKED\tURL"); err != nil {
+		return err
+	}
+	for _, channel := range channels {
+		checked := "never"
+		if !channel.CheckedAt.IsZero() {
+			checked = channel.CheckedAt.Local().Format(time.DateTime)
+		}
+		if _, err := fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", channel.ID, valueOrDash(channel.HighWater),
+			checked, channel.URL); err != nil {
+			return err
+		}
+	}
+	return table.Flush()
+}
+
+func writeWatchResults(writer io.Writer, results []watch.Result) (int, error) {
+	failed, summarized := 0, 0
+	for _, result := range results {
+		var err error
+		switch {
+		case result.Skipped:
+			_, err = fmt.Fprintf(writer, "Skipped %s %s: %v\n", result.Channel, result.VideoID, result.Err)
+		case result.Err != nil && result.VideoID == "":
+			failed++
+			_, err = fmt.Fprintf(writer, "Failed %s: %v\n", result.Channel, result.Err)
+		case result.Err != nil:
+			failed++
+			_, err = fmt.Fprintf(writer, "Failed %s %s: %v\n", result.Channel, result.VideoID, result.Err)
+		default:
+			summarized++
+			_, err = fmt.Fprintf(writer, "Summarized %s %s -> %s\n", result.Channel, result.VideoID, result.Path)
+		}
+		if err != nil {
+			return failed, err
+		}
+	}
+	_, err := fmt.Fprintf(writer, "%d new %s\n", summarized, plural(summarized, "summary", "summaries"))
+	return failed, err
+}
+
+var watchCmd = newWatchCommand(func() (watch.List, string) {
+	return watchList(config), config.WatchOutputDir
+}, func(cmd *cobra.Command) (watch.Application, error) {
+	if err := validateOpenAIRequirements(cmd, config); err != nil {
+		return nil, err
+	}
+	if err := handlePromptFlag(cmd, config); err != nil {
+		return nil, err
+	}
+	app, err := newEngine(config)
+	if err != nil {
+		return nil, fmt.Errorf("building application: %w", err)
+	}
+	return app, nil
+}, time.Now)
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+}
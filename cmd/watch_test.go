@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rtzll/tldw/internal/tldw"
+	"github.com/rtzll/tldw/internal/watch"
+)
+
+type watchApplicationStub struct {
+	request tldw.TranscriptRequest
+}
+
+func (stub *watchApplicationStub) NewUploads(context.Context, tldw.YouTubeRef, string, int) ([]tldw.YouTubeRef, error) {
+	ref, err := tldw.ParseVideoRef("dQw4w9WgXcQ")
+	return []tldw.YouTubeRef{ref}, err
+}
+
+func (stub *watchApplicationStub) SummarizeVideo(_ context.Context, _ tldw.YouTubeRef, request tldw.SummaryRequest) (tldw.Summary, error) {
+	stub.request = request.Transcript
+	return tldw.Summary{Markdown: "## Summary"}, nil
+}
+
+func (stub *watchApplicationStub) MetadataFor(context.Context, tldw.YouTubeRef) (*tldw.VideoMetadata, error) {
+	return &tldw.VideoMetadata{Title: "Example"}, nil
+}
+
+func TestWatchAddListAndRun(t *testing.T) {
+	dir := t.TempDir()
+	list := watch.List{Path: filepath.Join(dir, "watchlist.json")}
+	stub := &watchApplicationStub{}
+	settings := func() (watch.List, string) { return list, filepath.Join(dir, "summaries") }
+	build := func(*cobra.Command) (watch.Application, error) { return stub, nil }
+	execute := func(args ...string) string {
+		t.Helper()
+		command := newWatchCommand(settings, build, time.Now)
+		var output bytes.Buffer
+		command.SetOut(&output)
+		command.SetArgs(args)
+		if err := command.Execute(); err != nil {
+			t.Fatalf("watch %v error = %v", args, err)
+		}
+		return output.String()
+	}
+
+	if output := execute("add", "@CMUDatabaseGroup", "--backfill", "1"); !strings.Contains(output, "Watching @CMUDatabaseGroup") {
+		t.Fatalf("watch add output = %q", output)
+	}
+	output := execute("run", "--fallback-whisper")
+	want := filepath.Join(dir, "summaries", "CMUDatabaseGroup", "dQw4w9WgXcQ.md")
+	if !strings.Contains(output, "-> "+want) || !strings.Contains(output, "1 new summary") {
+		t.Fatalf("watch run output = %q, want %s", output, want)
+	}
+	if stub.request.Policy != tldw.TranscriptPolicyCaptionsThenWhisper {
+		t.Fatalf("watch run request = %+v, want Whisper fallback", stub.request)
+	}
+	if output := execute("list"); !strings.Contains(output, "@CMUDatabaseGroup") || !strings.Contains(output, "dQw4w9WgXcQ") {
+		t.Fatalf("watch list output = %q", output)
+	}
+}
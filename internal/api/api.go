@@ -0,0 +1,508 @@
+// Package api exposes the engine as a JSON REST API.
+package api
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rtzll/tldw/internal/markdown"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+// Prefix is the path every API route lives under.
+const Prefix = "/api/v1/"
+
+// DefaultTimeout bounds a request unless the caller configures otherwise.
+// Playlist summaries fetch every video, so the default is generous.
+const DefaultTimeout = 10 * time.Minute
+
+const maxRequestBodySize = 1 << 20
+
+//go:embed openapi.json
+var openAPIDocument []byte
+
+// Application is the part of the engine the API exposes. It is satisfied by
+// *tldw.Engine, exactly as the MCP server's application is.
+type Application interface {
+	MetadataFor(context.Context, tldw.YouTubeRef) (*tldw.VideoMetadata, error)
+	Transcript(context.Context, tldw.YouTubeRef, tldw.TranscriptRequest) (*tldw.Transcript, error)
+	SummarizeVideo(context.Context, tldw.YouTubeRef, tldw.SummaryRequest) (tldw.Summary, error)
+	CreatePlaylistSummary(context.Context, tldw.YouTubeRef, tldw.PlaylistSummaryRequest) (tldw.PlaylistSummaryResult, error)
+	Stats(tldw.StatsQuery) (tldw.StatsReport, error)
+	Library(tldw.LibraryQuery) ([]tldw.LibraryEntry, error)
+	CachedSummary(tldw.YouTubeRef) (*tldw.StoredSummary, error)
+}
+
+// Error codes returned in error bodies.
+const (
+	CodeInvalidRequest        = "invalid_request"
+	CodeNotFound              = "not_found"
+	CodeCaptionsUnavailable   = "captions_unavailable"
+	CodeTimestampsUnavailable = "timestamps_unavailable"
+	CodeDownloadFailed        = "download_failed"
+	CodeTimeout               = "timeout"
+	CodeCanceled              = "canceled"
+	CodeInternal              = "internal"
+)
+
+// ErrorBody is the body of every failed response.
+type ErrorBody struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes a failed request.
+type ErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// MetadataResponse is returned by GET /videos/{id}/metadata.
+type MetadataResponse struct {
+	VideoID  string              `json:"video_id"`
+	Metadata *tldw.VideoMetadata `json:"metadata"`
+}
+
+// TranscriptResponse is returned by the transcript endpoints. Segments are
+// only included with format=json.
+type TranscriptResponse struct {
+	VideoID  string                   `json:"video_id"`
+	Source   tldw.TranscriptSource    `json:"source,omitempty"`
+	Language string                   `json:"language,omitempty"`
+	Text     string                   `json:"text"`
+	Segments []tldw.TranscriptSegment `json:"segments,omitempty"`
+}
+
+// TranscriptRequest is the optional body of POST /videos/{id}/transcript,
+// which may start paid Whisper transcription.
+type TranscriptRequest struct {
+	Whisper    string `json:"whisper,omitempty"`
+	Language   string `json:"lang,omitempty"`
+	Format     string `json:"format,omitempty"`
+	Timestamps bool   `json:"timestamps,omitempty"`
+}
+
+// SummaryRequest is the optional body of the summary endpoints.
+type SummaryRequest struct {
+	Whisper  string `json:"whisper,omitempty"`
+	Language string `json:"lang,omitempty"`
+}
+
+// SummaryResponse is returned by the video summary endpoints. HTML is the
+// Markdown rendered without any raw HTML from the model.
+type SummaryResponse struct {
+	VideoID      string    `json:"video_id"`
+	Markdown     string    `json:"markdown"`
+	HTML         string    `json:"html"`
+	SummarizedAt time.Time `json:"summarized_at,omitzero"`
+}
+
+// PlaylistSummaryResponse is returned by POST /playlists/{id}/summary.
+type PlaylistSummaryResponse struct {
+	PlaylistID string   `json:"playlist_id"`
+	Title      string   `json:"title"`
+	Markdown   string   `json:"markdown"`
+	HTML       string   `json:"html"`
+	Processed  int      `json:"processed"`
+	Total      int      `json:"total"`
+	Skipped    []string `json:"skipped"`
+}
+
+// LibraryResponse is returned by GET /library.
+type LibraryResponse struct {
+	Entries []tldw.LibraryEntry `json:"entries"`
+}
+
+// requestError marks input the client has to fix.
+type requestError struct {
+	err error
+}
+
+func (e requestError) Error() string { return e.err.Error() }
+func (e requestError) Unwrap() error { return e.err }
+
+func invalidRequest(format string, args ...any) error {
+	return requestError{err: fmt.Errorf(format, args...)}
+}
+
+type server struct {
+	app Application
+}
+
+// Handler serves the API under Prefix. Every request is cancelled after
+// timeout; the engine aborts its downloads and model calls when that happens.
+func Handler(app Application, timeout time.Duration) http.Handler {
+	s := server{app: app}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+Prefix+"openapi.json", serveOpenAPI)
+	mux.Handle("GET "+Prefix+"videos/{id}/metadata", s.handle(s.metadata))
+	mux.Handle("GET "+Prefix+"videos/{id}/transcript", s.handle(s.transcript))
+	mux.Handle("POST "+Prefix+"videos/{id}/transcript", requireJSON(s.handle(s.transcribe)))
+	mux.Handle("GET "+Prefix+"videos/{id}/summary", s.handle(s.cachedSummary))
+	mux.Handle("POST "+Prefix+"videos/{id}/summary", requireJSON(s.handle(s.summary)))
+	mux.Handle("POST "+Prefix+"playlists/{id}/summary", requireJSON(s.handle(s.playlistSummary)))
+	mux.Handle("GET "+Prefix+"stats", s.handle(s.stats))
+	mux.Handle("GET "+Prefix+"library", s.handle(s.library))
+	mux.HandleFunc(Prefix, func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, CodeNotFound, fmt.Sprintf("no endpoint %s %s", r.Method, r.URL.Path))
+	})
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return withTimeout(mux, timeout)
+}
+
+func withTimeout(next http.Handler, timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requireJSON rejects requests that are not declared as JSON. Browsers send
+// form and text/plain bodies cross-origin without a CORS preflight, so this
+// keeps other sites from starting paid work.
+func requireJSON(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mediaType != "application/json" {
+			writeError(w, http.StatusUnsupportedMediaType, CodeInvalidRequest, "Content-Type must be application/json")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func serveOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPIDocument)
+}
+
+func (s server) handle(endpoint func(*http.Request) (any, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, err := endpoint(r)
+		if err != nil {
+			status, code := classifyError(r.Context(), err)
+			writeError(w, status, code, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, response)
+	})
+}
+
+func (s server) metadata(r *http.Request) (any, error) {
+	ref, err := videoRef(r)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := s.app.MetadataFor(r.Context(), ref)
+	if err != nil {
+		return nil, err
+	}
+	return MetadataResponse{VideoID: ref.ID(), Metadata: metadata}, nil
+}
+
+// transcript serves captions only. Any page can make a browser send a GET
+// without an Origin header, so paid Whisper transcription needs the JSON POST.
+func (s server) transcript(r *http.Request) (any, error) {
+	ref, err := videoRef(r)
+	if err != nil {
+		return nil, err
+	}
+	params := r.URL.Query()
+	timestamps, err := boolParam(params.Get("timestamps"), "timestamps")
+	if err != nil {
+		return nil, err
+	}
+	policy, err := whisperPolicy(params.Get("whisper"))
+	if err != nil {
+		return nil, err
+	}
+	if policy != tldw.TranscriptPolicyCaptionsOnly {
+		return nil, invalidRequest("GET only serves captions; use POST %svideos/%s/transcript for Whisper", Prefix, ref.ID())
+	}
+	return s.renderTranscript(r.Context(), ref, params.Get("format"), tldw.TranscriptRequest{
+		Policy: policy, RequireTimestamps: timestamps, Language: params.Get("lang"),
+	})
+}
+
+func (s server) transcribe(r *http.Request) (any, error) {
+	ref, err := videoRef(r)
+	if err != nil {
+		return nil, err
+	}
+	var body TranscriptRequest
+	if err := decodeBody(r, &body); err != nil {
+		return nil, err
+	}
+	policy, err := whisperPolicy(body.Whisper)
+	if err != nil {
+		return nil, err
+	}
+	return s.renderTranscript(r.Context(), ref, body.Format, tldw.TranscriptRequest{
+		Policy: policy, RequireTimestamps: body.Timestamps, Language: body.Language,
+	})
+}
+
+func (s server) renderTranscript(ctx context.Context, ref tldw.YouTubeRef, format string, request tldw.TranscriptRequest) (any, error) {
+	if format == "" {
+		format = "text"
+	}
+	if format != "text" && format != "json" {
+		return nil, invalidRequest("format must be text or json, got %q", format)
+	}
+	transcript, err := s.app.Transcript(ctx, ref, request)
+	if err != nil {
+		return nil, err
+	}
+	render := tldw.TranscriptRenderFormatPlain
+	if request.RequireTimestamps {
+		render = tldw.TranscriptRenderFormatTimestamps
+	}
+	text, err := transcript.Render(render)
+	if err != nil {
+		return nil, err
+	}
+	response := TranscriptResponse{
+		VideoID: ref.ID(), Source: transcript.Source, Language: transcript.Language, Text: text,
+	}
+	if format == "json" {
+		response.Segments = transcript.Segments
+	}
+	return response, nil
+}
+
+func (s server) summary(r *http.Request) (any, error) {
+	ref, err := videoRef(r)
+	if err != nil {
+		return nil, err
+	}
+	request, err := transcriptRequestFromBody(r)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := s.app.SummarizeVideo(r.Context(), ref, tldw.SummaryRequest{Transcript: request})
+	if err != nil {
+		return nil, err
+	}
+	html, err := markdown.ToHTML(summary.Markdown)
+	if err != nil {
+		return nil, err
+	}
+	return SummaryResponse{VideoID: ref.ID(), Markdown: summary.Markdown, HTML: html}, nil
+}
+
+func (s server) cachedSummary(r *http.Request) (any, error) {
+	ref, err := videoRef(r)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := s.app.CachedSummary(ref)
+	if err != nil {
+		return nil, err
+	}
+	html, err := markdown.ToHTML(summary.Markdown)
+	if err != nil {
+		return nil, err
+	}
+	return SummaryResponse{
+		VideoID: ref.ID(), Markdown: summary.Markdown, HTML: html, SummarizedAt: summary.SummarizedAt,
+	}, nil
+}
+
+func (s server) playlistSummary(r *http.Request) (any, error) {
+	ref, err := tldw.ParseReference(r.PathValue("id"))
+	if err != nil || !ref.IsPlaylist() {
+		return nil, invalidRequest("invalid YouTube playlist ID: %q", r.PathValue("id"))
+	}
+	request, err := transcriptRequestFromBody(r)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.app.CreatePlaylistSummary(r.Context(), ref, tldw.PlaylistSummaryRequest{Transcript: request})
+	if err != nil {
+		return nil, err
+	}
+	html, err := markdown.ToHTML(result.Markdown)
+	if err != nil {
+		return nil, err
+	}
+	skipped := result.Skipped
+	if skipped == nil {
+		skipped = []string{}
+	}
+	return PlaylistSummaryResponse{
+		PlaylistID: ref.ID(), Title: result.Title, Markdown: result.Markdown, HTML: html,
+		Processed: result.Processed, Total: result.Total, Skipped: skipped,
+	}, nil
+}
+
+func (s server) library(r *http.Request) (any, error) {
+	params := r.URL.Query()
+	query := tldw.LibraryQuery{Channel: params.Get("channel"), Search: params.Get("search")}
+	switch source := tldw.TranscriptSource(params.Get("source")); source {
+	case "", tldw.TranscriptSourceCaptions, tldw.TranscriptSourceWhisper:
+		query.Source = source
+	default:
+		return nil, invalidRequest("source must be captions or whisper, got %q", source)
+	}
+	switch sortBy := tldw.LibrarySort(params.Get("sort")); sortBy {
+	case "", tldw.LibrarySortSeen, tldw.LibrarySortTitle, tldw.LibrarySortChannel, tldw.LibrarySortDuration, tldw.LibrarySortSize:
+		query.SortBy = sortBy
+	default:
+		return nil, invalidRequest("sort must be seen, title, channel, duration, or size, got %q", sortBy)
+	}
+	var err error
+	if query.Reverse, err = boolParam(params.Get("reverse"), "reverse"); err != nil {
+		return nil, err
+	}
+	if value := params.Get("limit"); value != "" {
+		if query.Limit, err = strconv.Atoi(value); err != nil || query.Limit < 0 {
+			return nil, invalidRequest("limit must be a non-negative integer, got %q", value)
+		}
+	}
+	entries, err := s.app.Library(query)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []tldw.LibraryEntry{}
+	}
+	return LibraryResponse{Entries: entries}, nil
+}
+
+func (s server) stats(r *http.Request) (any, error) {
+	params := r.URL.Query()
+	query := tldw.StatsQuery{Location: time.UTC}
+	var err error
+	if query.From, err = timeParam(params.Get("from"), "from"); err != nil {
+		return nil, err
+	}
+	if query.To, err = timeParam(params.Get("to"), "to"); err != nil {
+		return nil, err
+	}
+	switch group := tldw.StatsGroup(params.Get("group_by")); group {
+	case tldw.StatsGroupNone, tldw.StatsGroupDay, tldw.StatsGroupWeek, tldw.StatsGroupMonth:
+		query.GroupBy = group
+	default:
+		return nil, invalidRequest("group_by must be day, week, or month, got %q", group)
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return nil, invalidRequest("from must be before to")
+	}
+	return s.app.Stats(query)
+}
+
+func videoRef(r *http.Request) (tldw.YouTubeRef, error) {
+	id := r.PathValue("id")
+	if !tldw.IsValidVideoID(id) {
+		return tldw.YouTubeRef{}, invalidRequest("invalid YouTube video ID: %q", id)
+	}
+	return tldw.ParseVideoRef(id)
+}
+
+func transcriptRequestFromBody(r *http.Request) (tldw.TranscriptRequest, error) {
+	var body SummaryRequest
+	if err := decodeBody(r, &body); err != nil {
+		return tldw.TranscriptRequest{}, err
+	}
+	policy, err := whisperPolicy(body.Whisper)
+	if err != nil {
+		return tldw.TranscriptRequest{}, err
+	}
+	return tldw.TranscriptRequest{Policy: policy, Language: body.Language}, nil
+}
+
+// decodeBody reads an optional JSON body; an empty body leaves body unchanged.
+func decodeBody(r *http.Request, body any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxRequestBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(body); err != nil && !errors.Is(err, io.EOF) {
+		return invalidRequest("parsing request body: %v", err)
+	}
+	return nil
+}
+
+// whisperPolicy reads when paid Whisper transcription may be used: never
+// (the default), as a fallback when captions are missing, or only.
+func whisperPolicy(value string) (tldw.TranscriptPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "never":
+		return tldw.TranscriptPolicyCaptionsOnly, nil
+	case "fallback":
+		return tldw.TranscriptPolicyCaptionsThenWhisper, nil
+	case "only":
+		return tldw.TranscriptPolicyWhisperOnly, nil
+	default:
+		return 0, invalidRequest("whisper must be never, fallback, or only, got %q", value)
+	}
+}
+
+func boolParam(value, name string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, invalidRequest("%s must be true or false, got %q", name, value)
+	}
+	return parsed, nil
+}
+
+func timeParam(value, name string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.DateOnly, value); err == nil {
+		return parsed, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, invalidRequest("%s must be a date (YYYY-MM-DD) or RFC 3339 time, got %q", name, value)
+	}
+	return parsed, nil
+}
+
+// classifyError maps the engine's sentinel errors to HTTP statuses.
+func classifyError(ctx context.Context, err error) (int, string) {
+	var invalid requestError
+	switch {
+	case errors.As(err, &invalid), errors.Is(err, tldw.ErrInvalidTranscriptPolicy):
+		return http.StatusBadRequest, CodeInvalidRequest
+	case errors.Is(err, tldw.ErrStoreNotFound):
+		return http.StatusNotFound, CodeNotFound
+	case errors.Is(err, tldw.ErrCaptionsUnavailable):
+		return http.StatusUnprocessableEntity, CodeCaptionsUnavailable
+	case errors.Is(err, tldw.ErrTranscriptTimestampsUnavailable):
+		return http.StatusUnprocessableEntity, CodeTimestampsUnavailable
+	case errors.Is(err, tldw.ErrDownloadFailed):
+		return http.StatusBadGateway, CodeDownloadFailed
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, CodeTimeout
+	case errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable, CodeCanceled
+	default:
+		return http.StatusInternalServerError, CodeInternal
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, ErrorBody{Error: ErrorDetail{Code: code, Message: message}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(body)
+}
@@ -0,0 +1,269 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rtzll/tldw/internal/api"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+const testVideoID = "dQw4w9WgXcQ"
+
+type applicationStub struct {
+	transcript    *tldw.Transcript
+	transcriptErr error
+	requests      []tldw.TranscriptRequest
+	block         bool
+	libraryQuery  tldw.LibraryQuery
+	summaries     map[string]string
+}
+
+func (stub *applicationStub) MetadataFor(ctx context.Context, _ tldw.YouTubeRef) (*tldw.VideoMetadata, error) {
+	if stub.block {
+		<-ctx.Done()
+		return nil, fmt.Errorf("fetching metadata: %w", ctx.Err())
+	}
+	return &tldw.VideoMetadata{Title: "B-trees", HasCaptions: true}, nil
+}
+
+func (stub *applicationStub) Transcript(_ context.Context, _ tldw.YouTubeRef, request tldw.TranscriptRequest) (*tldw.Transcript, error) {
+	stub.requests = append(stub.requests, request)
+	return stub.transcript, stub.transcriptErr
+}
+
+func (stub *applicationStub) SummarizeVideo(_ context.Context, ref tldw.YouTubeRef, request tldw.SummaryRequest) (tldw.Summary, error) {
+	stub.requests = append(stub.requests, request.Transcript)
+	return tldw.Summary{Markdown: "## " + ref.ID()}, nil
+}
+
+func (stub *applicationStub) CreatePlaylistSummary(_ context.Context, _ tldw.YouTubeRef, request tldw.PlaylistSummaryRequest) (tldw.PlaylistSummaryResult, error) {
+	stub.requests = append(stub.requests, request.Transcript)
+	return tldw.PlaylistSummaryResult{Title: "Databases", Markdown: "## Playlist", Processed: 2, Total: 2}, nil
+}
+
+func (stub *applicationStub) Stats(tldw.StatsQuery) (tldw.StatsReport, error) {
+	return tldw.StatsReport{VideoCount: 3, DurationSeconds: 60}, nil
+}
+
+func (stub *applicationStub) Library(query tldw.LibraryQuery) ([]tldw.LibraryEntry, error) {
+	stub.libraryQuery = query
+	return []tldw.LibraryEntry{{VideoID: testVideoID, HasSummary: true}}, nil
+}
+
+func (stub *applicationStub) CachedSummary(ref tldw.YouTubeRef) (*tldw.StoredSummary, error) {
+	if stub.summaries[ref.ID()] == "" {
+		return nil, fmt.Errorf("loading summary: %w", tldw.ErrStoreNotFound)
+	}
+	return &tldw.StoredSummary{VideoID: ref.ID(), Markdown: stub.summaries[ref.ID()]}, nil
+}
+
+func serve(t *testing.T, handler http.Handler, method, target, body string) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	if method == http.MethodPost {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Fatalf("%s %s content type = %q", method, target, contentType)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(recorder.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("%s %s body %q is not JSON: %v", method, target, recorder.Body.String(), err)
+	}
+	return recorder, decoded
+}
+
+func errorCode(body map[string]any) string {
+	detail, _ := body["error"].(map[string]any)
+	code, _ := detail["code"].(string)
+	return code
+}
+
+func TestHandlerServesTranscriptWithRequestedOptions(t *testing.T) {
+	stub := &applicationStub{transcript: &tldw.Transcript{
+		Source: tldw.TranscriptSourceCaptions, Language: "de",
+		Segments: []tldw.TranscriptSegment{{Start: 61, End: 62, Text: "Hallo"}},
+	}}
+	handler := api.Handler(stub, time.Minute)
+
+	recorder, body := serve(t, handler, http.MethodGet,
+		"/api/v1/videos/"+testVideoID+"/transcript?format=json&timestamps=true&lang=de&whisper=never", "")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %v", recorder.Code, body)
+	}
+	want := tldw.TranscriptRequest{Policy: tldw.TranscriptPolicyCaptionsOnly, RequireTimestamps: true, Language: "de"}
+	if len(stub.requests) != 1 || stub.requests[0] != want {
+		t.Fatalf("requests = %+v, want %+v", stub.requests, want)
+	}
+	if body["text"] != "[01:01] Hallo" || body["language"] != "de" || body["segments"] == nil {
+		t.Fatalf("body = %v", body)
+	}
+
+	recorder, body = serve(t, handler, http.MethodPost, "/api/v1/videos/"+testVideoID+"/transcript",
+		`{"whisper":"fallback","lang":"de","format":"json","timestamps":true}`)
+	if recorder.Code != http.StatusOK || body["segments"] == nil {
+		t.Fatalf("POST status = %d, body %v", recorder.Code, body)
+	}
+	want.Policy = tldw.TranscriptPolicyCaptionsThenWhisper
+	if len(stub.requests) != 2 || stub.requests[1] != want {
+		t.Fatalf("requests = %+v, want %+v", stub.requests, want)
+	}
+}
+
+func TestHandlerServesOnlyCaptionsOverGET(t *testing.T) {
+	stub := &applicationStub{transcript: &tldw.Transcript{Text: "paid"}}
+	handler := api.Handler(stub, time.Minute)
+	for _, whisper := range []string{"fallback", "only"} {
+		recorder, body := serve(t, handler, http.MethodGet, "/api/v1/videos/"+testVideoID+"/transcript?whisper="+whisper, "")
+		if recorder.Code != http.StatusBadRequest || errorCode(body) != api.CodeInvalidRequest {
+			t.Errorf("whisper=%s: status = %d, body %v, want 400", whisper, recorder.Code, body)
+		}
+	}
+	if len(stub.requests) != 0 {
+		t.Fatalf("GET requested transcripts %+v", stub.requests)
+	}
+}
+
+func TestHandlerMapsEngineErrorsToStatuses(t *testing.T) {
+	tests := []struct {
+		name       string
+		stub       *applicationStub
+		timeout    time.Duration
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name: "invalid video ID", stub: &applicationStub{}, method: http.MethodGet,
+			target: "/api/v1/videos/nope/metadata", wantStatus: http.StatusBadRequest, wantCode: api.CodeInvalidRequest,
+		},
+		{
+			name: "captions unavailable", method: http.MethodGet, target: "/api/v1/videos/" + testVideoID + "/transcript",
+			stub:       &applicationStub{transcriptErr: fmt.Errorf("%w for %s", tldw.ErrCaptionsUnavailable, testVideoID)},
+			wantStatus: http.StatusUnprocessableEntity, wantCode: api.CodeCaptionsUnavailable,
+		},
+		{
+			name: "download failed", method: http.MethodGet, target: "/api/v1/videos/" + testVideoID + "/transcript",
+			stub:       &applicationStub{transcriptErr: fmt.Errorf("fetching captions: %w", tldw.ErrDownloadFailed)},
+			wantStatus: http.StatusBadGateway, wantCode: api.CodeDownloadFailed,
+		},
+		{
+			name: "timeout", stub: &applicationStub{block: true}, timeout: time.Millisecond, method: http.MethodGet,
+			target: "/api/v1/videos/" + testVideoID + "/metadata", wantStatus: http.StatusGatewayTimeout, wantCode: api.CodeTimeout,
+		},
+		{
+			name: "unknown whisper policy", stub: &applicationStub{}, method: http.MethodPost,
+			target: "/api/v1/videos/" + testVideoID + "/summary", body: `{"whisper":"always"}`,
+			wantStatus: http.StatusBadRequest, wantCode: api.CodeInvalidRequest,
+		},
+		{
+			name: "unknown stats grouping", stub: &applicationStub{}, method: http.MethodGet,
+			target: "/api/v1/stats?group_by=year", wantStatus: http.StatusBadRequest, wantCode: api.CodeInvalidRequest,
+		},
+		{
+			name: "unknown endpoint", stub: &applicationStub{}, method: http.MethodGet,
+			target: "/api/v1/channels", wantStatus: http.StatusNotFound, wantCode: api.CodeNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeout := tt.timeout
+			if timeout == 0 {
+				timeout = time.Minute
+			}
+			recorder, body := serve(t, api.Handler(tt.stub, timeout), tt.method, tt.target, tt.body)
+			if recorder.Code != tt.wantStatus || errorCode(body) != tt.wantCode {
+				t.Fatalf("status = %d, body %v, want %d %s", recorder.Code, body, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestHandlerRequiresJSONForPaidRequests(t *testing.T) {
+	stub := &applicationStub{}
+	handler := api.Handler(stub, time.Minute)
+	for _, path := range []string{"/summary", "/transcript"} {
+		for _, contentType := range []string{"", "text/plain", "application/x-www-form-urlencoded"} {
+			request := httptest.NewRequest(http.MethodPost, "/api/v1/videos/"+testVideoID+path, strings.NewReader(`{"whisper":"only"}`))
+			if contentType != "" {
+				request.Header.Set("Content-Type", contentType)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			if recorder.Code != http.StatusUnsupportedMediaType {
+				t.Errorf("POST %s with Content-Type %q: status = %d, want %d", path, contentType, recorder.Code, http.StatusUnsupportedMediaType)
+			}
+		}
+	}
+	if len(stub.requests) != 0 {
+		t.Fatalf("paid work ran %d times for non-JSON requests", len(stub.requests))
+	}
+}
+
+func TestHandlerSummarizesVideosAndPlaylists(t *testing.T) {
+	stub := &applicationStub{}
+	handler := api.Handler(stub, time.Minute)
+
+	recorder, body := serve(t, handler, http.MethodPost, "/api/v1/videos/"+testVideoID+"/summary", "")
+	if recorder.Code != http.StatusOK || body["markdown"] != "## "+testVideoID {
+		t.Fatalf("video summary status = %d, body %v", recorder.Code, body)
+	}
+	recorder, body = serve(t, handler, http.MethodPost, "/api/v1/playlists/PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf/summary", `{"whisper":"fallback"}`)
+	if recorder.Code != http.StatusOK || body["title"] != "Databases" || body["skipped"] == nil {
+		t.Fatalf("playlist summary status = %d, body %v", recorder.Code, body)
+	}
+	if stub.requests[1].Policy != tldw.TranscriptPolicyCaptionsThenWhisper {
+		t.Fatalf("playlist request = %+v, want Whisper fallback", stub.requests[1])
+	}
+}
+
+func TestHandlerListsLibraryAndCachedSummaries(t *testing.T) {
+	stub := &applicationStub{summaries: map[string]string{testVideoID: "**Pages split**"}}
+	handler := api.Handler(stub, time.Minute)
+
+	recorder, body := serve(t, handler, http.MethodGet, "/api/v1/library?channel=cmu&sort=duration&reverse=true&limit=5", "")
+	want := tldw.LibraryQuery{Channel: "cmu", SortBy: tldw.LibrarySortDuration, Reverse: true, Limit: 5}
+	if recorder.Code != http.StatusOK || stub.libraryQuery != want {
+		t.Fatalf("library status = %d, query %+v, want %+v", recorder.Code, stub.libraryQuery, want)
+	}
+	if entries, _ := body["entries"].([]any); len(entries) != 1 {
+		t.Fatalf("library body = %v", body)
+	}
+
+	recorder, body = serve(t, handler, http.MethodGet, "/api/v1/videos/"+testVideoID+"/summary", "")
+	if recorder.Code != http.StatusOK || body["html"] != "<p><strong>Pages split</strong></p>\n" {
+		t.Fatalf("cached summary status = %d, body %v", recorder.Code, body)
+	}
+	recorder, body = serve(t, handler, http.MethodGet, "/api/v1/videos/tAP1eZYEuKA/summary", "")
+	if recorder.Code != http.StatusNotFound || errorCode(body) != api.CodeNotFound {
+		t.Fatalf("missing summary status = %d, body %v", recorder.Code, body)
+	}
+}
+
+func TestOpenAPIDocumentDescribesEveryRoute(t *testing.T) {
+	handler := api.Handler(&applicationStub{}, time.Minute)
+	recorder, body := serve(t, handler, http.MethodGet, "/api/v1/openapi.json", "")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d", recorder.Code)
+	}
+	paths, _ := body["paths"].(map[string]any)
+	for _, path := range []string{
+		"/videos/{id}/metadata", "/videos/{id}/transcript", "/videos/{id}/summary",
+		"/playlists/{id}/summary", "/stats", "/library", "/openapi.json",
+	} {
+		if _, ok := paths[path]; !ok {
+			t.Fatalf("OpenAPI document does not describe %s", path)
+		}
+	}
+}
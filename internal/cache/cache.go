@@ -0,0 +1,251 @@
+// Package cache manages downloaded audio, leftover subtitles, and
+// transcription chunks in the XDG cache directory.
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kind classifies a managed cache file.
+type Kind string
+
+const (
+	KindAudio     Kind = "audio"
+	KindSubtitles Kind = "subtitles"
+	KindChunks    Kind = "chunks"
+	KindPartial   Kind = "partial"
+)
+
+// PartialDirSuffix ends the name of a directory holding a download in
+// progress. Every file in such a directory is partial.
+const PartialDirSuffix = ".part"
+
+// Kinds lists every managed kind in display order.
+var Kinds = []Kind{KindAudio, KindSubtitles, KindChunks, KindPartial}
+
+// ParseKind validates a user-supplied kind name.
+func ParseKind(name string) (Kind, error) {
+	kind := Kind(strings.ToLower(strings.TrimSpace(name)))
+	if slices.Contains(Kinds, kind) {
+		return kind, nil
+	}
+	return "", fmt.Errorf("unsupported cache kind %q: use audio, subtitles, chunks, or partial", name)
+}
+
+// Policy bounds the managed cache. Zero values disable the corresponding limit.
+type Policy struct {
+	MaxSize int64
+	MaxAge  time.Duration
+}
+
+// File is one managed file in the cache directory. ModTime doubles as the
+// last-used time because reused files are touched.
+type File struct {
+	Path    string
+	Kind    Kind
+	Size    int64
+	ModTime time.Time
+}
+
+// Usage summarizes managed files of one kind.
+type Usage struct {
+	Kind  Kind
+	Files int
+	Size  int64
+}
+
+// Dir is a cache directory together with its transcription chunk directory.
+// Files the cache does not recognize, such as logs, are never touched.
+type Dir struct {
+	Path    string
+	TempDir string
+}
+
+// Files returns every managed file, least recently used first.
+func (d Dir) Files() ([]File, error) {
+	var files []File
+	err := filepath.WalkDir(d.Path, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		kind, ok := d.classify(path)
+		if !ok {
+			return nil
+		}
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		files = append(files, File{Path: path, Kind: kind, Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scanning cache directory: %w", err)
+	}
+	sort.SliceStable(files, func(i, j int) bool { return files[i].ModTime.Before(files[j].ModTime) })
+	return files, nil
+}
+
+// Usage reports managed file counts and sizes for every kind.
+func (d Dir) Usage() ([]Usage, error) {
+	files, err := d.Files()
+	if err != nil {
+		return nil, err
+	}
+	byKind := make(map[Kind]Usage, len(Kinds))
+	for _, file := range files {
+		usage := byKind[file.Kind]
+		usage.Files++
+		usage.Size += file.Size
+		byKind[file.Kind] = usage
+	}
+	usages := make([]Usage, 0, len(Kinds))
+	for _, kind := range Kinds {
+		usage := byKind[kind]
+		usage.Kind = kind
+		usages = append(usages, usage)
+	}
+	return usages, nil
+}
+
+// CleanOptions selects files for Clean. Empty Kinds selects every kind and a
+// zero Before selects files regardless of age.
+type CleanOptions struct {
+	Kinds  []Kind
+	Before time.Time
+	DryRun bool
+}
+
+// Clean removes the selected managed files and returns them.
+func (d Dir) Clean(options CleanOptions) ([]File, error) {
+	files, err := d.Files()
+	if err != nil {
+		return nil, err
+	}
+	var selected []File
+	for _, file := range files {
+		if len(options.Kinds) > 0 && !slices.Contains(options.Kinds, file.Kind) {
+			continue
+		}
+		if !options.Before.IsZero() && !file.ModTime.Before(options.Before) {
+			continue
+		}
+		selected = append(selected, file)
+	}
+	if options.DryRun {
+		return selected, nil
+	}
+	return removeFiles(selected)
+}
+
+// Enforce evicts audio and subtitles older than the policy's maximum age, then
+// removes the least recently used ones until they fit the maximum size. Paths
+// in keep are never evicted. Chunks and partial downloads belong to work in
+// progress, possibly in another process, so they are left to Clean.
+func (d Dir) Enforce(policy Policy, now time.Time, keep ...string) ([]File, error) {
+	if policy.MaxSize <= 0 && policy.MaxAge <= 0 {
+		return nil, nil
+	}
+	managed, err := d.Files()
+	if err != nil {
+		return nil, err
+	}
+	var files []File
+	for _, file := range managed {
+		if file.Kind == KindAudio || file.Kind == KindSubtitles {
+			files = append(files, file)
+		}
+	}
+	kept := make(map[string]struct{}, len(keep))
+	for _, path := range keep {
+		kept[filepath.Clean(path)] = struct{}{}
+	}
+
+	var total int64
+	for _, file := range files {
+		total += file.Size
+	}
+	var evict []File
+	for _, file := range files {
+		if _, ok := kept[filepath.Clean(file.Path)]; ok {
+			continue
+		}
+		expired := policy.MaxAge > 0 && now.Sub(file.ModTime) > policy.MaxAge
+		oversized := policy.MaxSize > 0 && total > policy.MaxSize
+		if !expired && !oversized {
+			continue
+		}
+		evict = append(evict, file)
+		total -= file.Size
+	}
+	return removeFiles(evict)
+}
+
+// Touch marks a reused cache file as recently used for LRU eviction.
+func Touch(path string, now time.Time) error {
+	if err := os.Chtimes(path, now, now); err != nil {
+		return fmt.Errorf("updating cache access time: %w", err)
+	}
+	return nil
+}
+
+func (d Dir) classify(path string) (Kind, bool) {
+	name := strings.ToLower(filepath.Base(path))
+	if d.TempDir != "" && pathWithin(path, d.TempDir) {
+		return KindChunks, true
+	}
+	if parent := filepath.Dir(path); parent != filepath.Clean(d.Path) && strings.HasSuffix(parent, PartialDirSuffix) {
+		return KindPartial, true
+	}
+	switch filepath.Ext(name) {
+	case ".mp3", ".m4a", ".webm", ".opus", ".ogg", ".wav":
+		if strings.Contains(name, ".temp.") {
+			return KindPartial, true
+		}
+		return KindAudio, true
+	case ".srt", ".vtt":
+		return KindSubtitles, true
+	case ".part", ".ytdl":
+		return KindPartial, true
+	default:
+		return "", false
+	}
+}
+
+func pathWithin(path, directory string) bool {
+	relative, err := filepath.Rel(directory, path)
+	if err != nil {
+		return false
+	}
+	return relative != "." && filepath.IsLocal(relative)
+}
+
+func removeFiles(files []File) ([]File, error) {
+	removed := make([]File, 0, len(files))
+	var errs []error
+	for _, file := range files {
+		if err := os.Remove(file.Path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("removing %s: %w", filepath.Base(file.Path), err))
+			continue
+		}
+		removed = append(removed, file)
+	}
+	return removed, errors.Join(errs...)
+}
@@ -0,0 +1,133 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rtzll/tldw/internal/cache"
+)
+
+func writeCacheFile(t *testing.T, path string, size int, modTime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+		t.Fatalf("WriteFile(%q) error = %v", path, err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Chtimes(%q) error = %v", path, err)
+	}
+}
+
+func baseNames(files []cache.File) []string {
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, filepath.Base(file.Path))
+	}
+	return names
+}
+
+func TestDirClassifiesManagedFilesAndIgnoresOthers(t *testing.T) {
+	root := t.TempDir()
+	dir := cache.Dir{Path: root, TempDir: filepath.Join(root, "temp_chunks")}
+	now := time.Date(2026, time.July, 19, 12, 0, 0, 0, time.UTC)
+	writeCacheFile(t, filepath.Join(root, "dQw4w9WgXcQ.mp3"), 300, now)
+	writeCacheFile(t, filepath.Join(root, "dQw4w9WgXcQ.en.srt"), 20, now)
+	writeCacheFile(t, filepath.Join(root, "tAP1eZYEuKA.webm.part"), 50, now)
+	writeCacheFile(t, filepath.Join(root, "temp_chunks", "dQw4w9WgXcQ.mp3_chunk_0.mp3"), 100, now)
+	writeCacheFile(t, filepath.Join(root, "mcp.log"), 999, now)
+
+	usages, err := dir.Usage()
+	if err != nil {
+		t.Fatalf("Usage() error = %v", err)
+	}
+	want := []cache.Usage{
+		{Kind: cache.KindAudio, Files: 1, Size: 300},
+		{Kind: cache.KindSubtitles, Files: 1, Size: 20},
+		{Kind: cache.KindChunks, Files: 1, Size: 100},
+		{Kind: cache.KindPartial, Files: 1, Size: 50},
+	}
+	if !slices.Equal(usages, want) {
+		t.Fatalf("Usage() = %+v, want %+v", usages, want)
+	}
+
+	removed, err := dir.Clean(cache.CleanOptions{})
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if len(removed) != 4 {
+		t.Fatalf("Clean() removed %v, want every managed file", baseNames(removed))
+	}
+	if _, err := os.Stat(filepath.Join(root, "mcp.log")); err != nil {
+		t.Fatalf("Clean() touched an unmanaged file: %v", err)
+	}
+}
+
+func TestDirEnforceEvictsExpiredThenLeastRecentlyUsedFiles(t *testing.T) {
+	root := t.TempDir()
+	dir := cache.Dir{Path: root}
+	now := time.Date(2026, time.July, 19, 12, 0, 0, 0, time.UTC)
+	writeCacheFile(t, filepath.Join(root, "expired00000.mp3"), 10, now.Add(-40*24*time.Hour))
+	writeCacheFile(t, filepath.Join(root, "oldest00000.mp3"), 100, now.Add(-3*time.Hour))
+	writeCacheFile(t, filepath.Join(root, "middle00000.mp3"), 100, now.Add(-2*time.Hour))
+	writeCacheFile(t, filepath.Join(root, "newest00000.mp3"), 100, now.Add(-time.Hour))
+	writeCacheFile(t, filepath.Join(root, "keep0000000.mp3"), 100, now.Add(-4*time.Hour))
+
+	removed, err := dir.Enforce(cache.Policy{MaxSize: 250, MaxAge: 30 * 24 * time.Hour}, now, filepath.Join(root, "keep0000000.mp3"))
+	if err != nil {
+		t.Fatalf("Enforce() error = %v", err)
+	}
+	if got, want := baseNames(removed), []string{"expired00000.mp3", "oldest00000.mp3", "middle00000.mp3"}; !slices.Equal(got, want) {
+		t.Fatalf("Enforce() removed %v, want %v", got, want)
+	}
+	remaining, err := dir.Files()
+	if err != nil {
+		t.Fatalf("Files() error = %v", err)
+	}
+	if got, want := baseNames(remaining), []string{"keep0000000.mp3", "newest00000.mp3"}; !slices.Equal(got, want) {
+		t.Fatalf("Files() after Enforce() = %v, want %v", got, want)
+	}
+}
+
+func TestDirEnforceKeepsChunksAndPartialDownloads(t *testing.T) {
+	root := t.TempDir()
+	dir := cache.Dir{Path: root, TempDir: filepath.Join(root, "temp_chunks")}
+	now := time.Date(2026, time.July, 19, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-40 * 24 * time.Hour)
+	writeCacheFile(t, filepath.Join(root, "temp_chunks", "dQw4w9WgXcQ", "chunk_000.mp3"), 500, old)
+	writeCacheFile(t, filepath.Join(root, "dQw4w9WgXcQ.webm.part"), 500, old)
+	writeCacheFile(t, filepath.Join(root, "dQw4w9WgXcQ.123456.part", "dQw4w9WgXcQ.webm"), 500, old)
+	writeCacheFile(t, filepath.Join(root, "audio000000.mp3"), 100, old)
+
+	removed, err := dir.Enforce(cache.Policy{MaxSize: 50, MaxAge: 30 * 24 * time.Hour}, now)
+	if err != nil {
+		t.Fatalf("Enforce() error = %v", err)
+	}
+	if got, want := baseNames(removed), []string{"audio000000.mp3"}; !slices.Equal(got, want) {
+		t.Fatalf("Enforce() removed %v, want %v", got, want)
+	}
+}
+
+func TestDirCleanFiltersByKindAndAge(t *testing.T) {
+	root := t.TempDir()
+	dir := cache.Dir{Path: root}
+	now := time.Date(2026, time.July, 19, 12, 0, 0, 0, time.UTC)
+	writeCacheFile(t, filepath.Join(root, "old00000000.mp3"), 10, now.Add(-10*24*time.Hour))
+	writeCacheFile(t, filepath.Join(root, "new00000000.mp3"), 10, now)
+	writeCacheFile(t, filepath.Join(root, "old00000000.en.srt"), 10, now.Add(-10*24*time.Hour))
+
+	removed, err := dir.Clean(cache.CleanOptions{Kinds: []cache.Kind{cache.KindAudio}, Before: now.Add(-7 * 24 * time.Hour), DryRun: true})
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if got := baseNames(removed); !slices.Equal(got, []string{"old00000000.mp3"}) {
+		t.Fatalf("Clean() selected %v, want only the old audio", got)
+	}
+	if _, err := os.Stat(filepath.Join(root, "old00000000.mp3")); err != nil {
+		t.Fatalf("Clean(dry run) removed a file: %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/adrg/xdg"
@@ -23,6 +24,14 @@ type Config struct {
 	OpenAIAPIKey   string
 	Prompt         string
 	MCPLogEnabled  bool
+	MCPLogMaxSize  int64
+	MCPLogMaxFiles int
+	MCPHTTP        MCPHTTPConfig
+	CacheMaxSize   int64
+	CacheMaxAge    time.Duration
+	CommandRetry   CommandRetryConfig
+	YtDLP          YtDLPConfig
+	WatchOutputDir string
 
 	// Fixed XDG paths (not configurable)
 	ConfigDir string
@@ -31,6 +40,37 @@ type Config struct {
 	TempDir   string
 }
 
+// MCPHTTPConfig secures the MCP HTTP transport.
+type MCPHTTPConfig struct {
+	Tokens         map[string]string
+	TokensFile     string
+	AllowedHosts   []string
+	AllowedOrigins []string
+	TLSCert        string
+	TLSKey         string
+}
+
+// CommandRetryConfig controls how yt-dlp and ffmpeg runs are retried.
+type CommandRetryConfig struct {
+	Attempts       int
+	Delay          time.Duration
+	RateLimitDelay time.Duration
+	MaxDelay       time.Duration
+	MaxPerHost     int
+}
+
+// YtDLPConfig controls how yt-dlp reaches YouTube, e.g. for age-restricted,
+// members-only, or region-locked videos.
+type YtDLPConfig struct {
+	Cookies            string
+	CookiesFromBrowser string
+	Proxy              string
+	ExtractorArgs      string
+	SleepInterval      time.Duration
+	MaxSleepInterval   time.Duration
+	ExtraArgs          []string
+}
+
 //go:embed config.toml prompt.txt
 var defaultFS embed.FS
 
@@ -103,6 +143,16 @@ func InitConfig(configFile string) (*Config, error) {
 	v.SetDefault("quiet", false)
 	v.SetDefault("prompt", "") // empty => use default prompt template
 	v.SetDefault("mcp_log_enabled", false)
+	v.SetDefault("mcp_log_max_size_mb", 10)
+	v.SetDefault("mcp_log_max_files", 5)
+	v.SetDefault("cache_max_size_mb", 2048)
+	v.SetDefault("cache_max_age", 30*24*time.Hour)
+	v.SetDefault("command_retry_attempts", 3)
+	v.SetDefault("command_retry_delay", time.Second)
+	v.SetDefault("command_rate_limit_delay", 10*time.Second)
+	v.SetDefault("command_retry_max_delay", time.Minute)
+	v.SetDefault("command_max_per_host", 2)
+	v.SetDefault("watch_output_dir", filepath.Join(dataDir, "summaries"))
 
 	// Set config name and paths.
 	if configFile != "" {
@@ -145,6 +195,35 @@ func InitConfig(configFile string) (*Config, error) {
 		OpenAIAPIKey:   v.GetString("openai_api_key"),
 		Prompt:         v.GetString("prompt"),
 		MCPLogEnabled:  v.GetBool("mcp_log_enabled"),
+		MCPLogMaxSize:  v.GetInt64("mcp_log_max_size_mb") << 20,
+		MCPLogMaxFiles: v.GetInt("mcp_log_max_files"),
+		MCPHTTP: MCPHTTPConfig{
+			Tokens:         v.GetStringMapString("mcp_tokens"),
+			TokensFile:     v.GetString("mcp_tokens_file"),
+			AllowedHosts:   v.GetStringSlice("mcp_allowed_hosts"),
+			AllowedOrigins: v.GetStringSlice("mcp_allowed_origins"),
+			TLSCert:        v.GetString("mcp_tls_cert"),
+			TLSKey:         v.GetString("mcp_tls_key"),
+		},
+		CacheMaxSize: v.GetInt64("cache_max_size_mb") << 20,
+		CacheMaxAge:  v.GetDuration("cache_max_age"),
+		CommandRetry: CommandRetryConfig{
+			Attempts:       v.GetInt("command_retry_attempts"),
+			Delay:          v.GetDuration("command_retry_delay"),
+			RateLimitDelay: v.GetDuration("command_rate_limit_delay"),
+			MaxDelay:       v.GetDuration("command_retry_max_delay"),
+			MaxPerHost:     v.GetInt("command_max_per_host"),
+		},
+		YtDLP: YtDLPConfig{
+			Cookies:            v.GetString("ytdlp_cookies"),
+			CookiesFromBrowser: v.GetString("ytdlp_cookies_from_browser"),
+			Proxy:              v.GetString("ytdlp_proxy"),
+			ExtractorArgs:      v.GetString("ytdlp_extractor_args"),
+			SleepInterval:      v.GetDuration("ytdlp_sleep_interval"),
+			MaxSleepInterval:   v.GetDuration("ytdlp_max_sleep_interval"),
+			ExtraArgs:          v.GetStringSlice("ytdlp_extra_args"),
+		},
+		WatchOutputDir: v.GetString("watch_output_dir"),
 
 		// Fixed XDG paths.
 		ConfigDir: configDir,
@@ -159,3 +238,37 @@ func InitConfig(configFile string) (*Config, error) {
 
 	return config, nil
 }
+
+// LoadTokens merges the tokens from the config file with those in TokensFile.
+// Each non-empty line of the file is "name=token"; lines starting with # are
+// comments. A name may only be defined once.
+func (c MCPHTTPConfig) LoadTokens() (map[string]string, error) {
+	tokens := make(map[string]string, len(c.Tokens))
+	for name, token := range c.Tokens {
+		tokens[name] = token
+	}
+	if c.TokensFile == "" {
+		return tokens, nil
+	}
+
+	data, err := os.ReadFile(c.TokensFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading MCP tokens file: %w", err)
+	}
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, token, ok := strings.Cut(line, "=")
+		name, token = strings.TrimSpace(name), strings.TrimSpace(token)
+		if !ok || name == "" || token == "" {
+			return nil, fmt.Errorf("%s:%d: expected name=token", c.TokensFile, i+1)
+		}
+		if _, exists := tokens[name]; exists {
+			return nil, fmt.Errorf("%s:%d: MCP token %q is defined twice", c.TokensFile, i+1, name)
+		}
+		tokens[name] = token
+	}
+	return tokens, nil
+}
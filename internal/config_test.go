@@ -3,6 +3,7 @@ package internal
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 )
@@ -15,6 +16,17 @@ func TestInitConfigUsesExplicitFile(t *testing.T) {
 tldr_model = "gpt-5-mini"
 transcripts_dir = "/tmp/custom-transcripts"
 summary_timeout = "45s"
+cache_max_size_mb = 512
+cache_max_age = "48h"
+mcp_log_max_size_mb = 2
+mcp_log_max_files = 3
+command_retry_attempts = 5
+command_rate_limit_delay = "30s"
+ytdlp_cookies_from_browser = "firefox"
+ytdlp_proxy = "http://127.0.0.1:8080"
+ytdlp_sleep_interval = "2s"
+ytdlp_extra_args = ["--geo-bypass-country", "US"]
+watch_output_dir = "/tmp/watch-summaries"
 `)
 	if err := os.WriteFile(configPath, content, 0o644); err != nil {
 		t.Fatalf("WriteFile() error = %v", err)
@@ -33,6 +45,84 @@ summary_timeout = "45s"
 	if config.SummaryTimeout != 45*time.Second {
 		t.Errorf("SummaryTimeout = %v, want 45s", config.SummaryTimeout)
 	}
+	if config.CacheMaxSize != 512<<20 || config.CacheMaxAge != 48*time.Hour {
+		t.Errorf("cache limits = %d bytes, %v, want 512 MiB, 48h", config.CacheMaxSize, config.CacheMaxAge)
+	}
+	wantRetry := CommandRetryConfig{Attempts: 5, Delay: time.Second, RateLimitDelay: 30 * time.Second, MaxDelay: time.Minute, MaxPerHost: 2}
+	if config.CommandRetry != wantRetry {
+		t.Errorf("CommandRetry = %+v, want %+v", config.CommandRetry, wantRetry)
+	}
+	if ytdlp := config.YtDLP; ytdlp.CookiesFromBrowser != "firefox" || ytdlp.Proxy != "http://127.0.0.1:8080" ||
+		ytdlp.SleepInterval != 2*time.Second || !slices.Equal(ytdlp.ExtraArgs, []string{"--geo-bypass-country", "US"}) {
+		t.Errorf("YtDLP = %+v, want the configured cookies browser, proxy, sleep, and extra args", ytdlp)
+	}
+	if config.MCPLogMaxSize != 2<<20 || config.MCPLogMaxFiles != 3 {
+		t.Errorf("MCP log limits = %d bytes, %d files, want 2 MiB, 3 files", config.MCPLogMaxSize, config.MCPLogMaxFiles)
+	}
+	if config.WatchOutputDir != "/tmp/watch-summaries" {
+		t.Errorf("WatchOutputDir = %q, want /tmp/watch-summaries", config.WatchOutputDir)
+	}
+}
+
+func TestInitConfigReadsMCPHTTPSettings(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "custom.toml")
+	content := []byte(`
+mcp_tokens = { laptop = "secret-1" }
+mcp_allowed_hosts = ["tldw.example.com"]
+mcp_allowed_origins = ["https://chat.example.com"]
+mcp_tls_cert = "/etc/tldw/cert.pem"
+mcp_tls_key = "/etc/tldw/key.pem"
+`)
+	if err := os.WriteFile(configPath, content, 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	config, err := InitConfig(configPath)
+	if err != nil {
+		t.Fatalf("InitConfig() error = %v", err)
+	}
+	got := config.MCPHTTP
+	if got.Tokens["laptop"] != "secret-1" || len(got.AllowedHosts) != 1 || len(got.AllowedOrigins) != 1 {
+		t.Errorf("MCPHTTP = %+v", got)
+	}
+	if got.TLSCert != "/etc/tldw/cert.pem" || got.TLSKey != "/etc/tldw/key.pem" {
+		t.Errorf("MCPHTTP TLS = %q, %q", got.TLSCert, got.TLSKey)
+	}
+}
+
+func TestMCPHTTPConfigLoadTokensMergesFile(t *testing.T) {
+	tokensFile := filepath.Join(t.TempDir(), "mcp-tokens")
+	if err := os.WriteFile(tokensFile, []byte("# shared machines\ntunnel = secret-2\n\nci=secret-3\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	config := MCPHTTPConfig{Tokens: map[string]string{"laptop": "secret-1"}, TokensFile: tokensFile}
+
+	tokens, err := config.LoadTokens()
+	if err != nil {
+		t.Fatalf("LoadTokens() error = %v", err)
+	}
+	want := map[string]string{"laptop": "secret-1", "tunnel": "secret-2", "ci": "secret-3"}
+	if len(tokens) != len(want) {
+		t.Fatalf("LoadTokens() = %v, want %v", tokens, want)
+	}
+	for name, token := range want {
+		if tokens[name] != token {
+			t.Errorf("token %q = %q, want %q", name, tokens[name], token)
+		}
+	}
+
+	if err := os.WriteFile(tokensFile, []byte("laptop=other\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := config.LoadTokens(); err == nil {
+		t.Error("LoadTokens() accepted a token name defined twice")
+	}
+	if err := os.WriteFile(tokensFile, []byte("just-a-token\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := config.LoadTokens(); err == nil {
+		t.Error("LoadTokens() accepted a line without a name")
+	}
 }
 
 func TestCleanupTempDir(t *testing.T) {
@@ -0,0 +1,207 @@
+// Package feed publishes cached summaries as an Atom feed.
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rtzll/tldw/internal/markdown"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+// ContentType is the media type of an Atom feed.
+const ContentType = "application/atom+xml; charset=utf-8"
+
+// DefaultLimit is how many summaries a feed holds unless asked otherwise.
+const DefaultLimit = 50
+
+// Source lists cached summaries.
+type Source interface {
+	RecentSummaries(query tldw.SummaryQuery) ([]tldw.StoredSummary, error)
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    []atomLink  `xml:"link,omitempty"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published,omitempty"`
+	Link      atomLink    `xml:"link"`
+	Author    *atomAuthor `xml:"author,omitempty"`
+	Category  []atomTerm  `xml:"category,omitempty"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomTerm struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// Write renders summaries, newest first, as an Atom document. query is only
+// used to describe the feed.
+func Write(writer io.Writer, query tldw.SummaryQuery, summaries []tldw.StoredSummary, now time.Time) error {
+	doc := atomFeed{
+		ID:     feedID(query),
+		Title:  feedTitle(query),
+		Author: atomAuthor{Name: "tldw"},
+	}
+	updated := now
+	if len(summaries) > 0 {
+		updated = summaries[0].SummarizedAt
+	}
+	doc.Updated = atomTime(updated)
+
+	for _, summary := range summaries {
+		entry, err := newEntry(summary)
+		if err != nil {
+			return err
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("encoding feed: %w", err)
+	}
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
+
+func newEntry(summary tldw.StoredSummary) (atomEntry, error) {
+	ref, err := tldw.ParseVideoRef(summary.VideoID)
+	if err != nil {
+		return atomEntry{}, fmt.Errorf("summary of %q: %w", summary.VideoID, err)
+	}
+	html, err := markdown.ToHTML(summary.Markdown)
+	if err != nil {
+		return atomEntry{}, fmt.Errorf("rendering summary of %s: %w", summary.VideoID, err)
+	}
+	entry := atomEntry{
+		ID:      "yt:video:" + summary.VideoID,
+		Title:   summary.VideoID,
+		Updated: atomTime(summary.SummarizedAt),
+		Link:    atomLink{Href: ref.URL(), Rel: "alternate"},
+		Content: atomContent{Type: "html", Body: html},
+	}
+	if metadata := summary.Metadata; metadata != nil {
+		if metadata.Title != "" {
+			entry.Title = metadata.Title
+		}
+		if metadata.Channel != "" {
+			entry.Author = &atomAuthor{Name: metadata.Channel, URI: metadata.ChannelURL}
+		}
+		if published, ok := publishedAt(metadata.PublishedAt); ok {
+			entry.Published = atomTime(published)
+		}
+		for _, tag := range metadata.Tags {
+			entry.Category = append(entry.Category, atomTerm{Term: tag})
+		}
+	}
+	return entry, nil
+}
+
+// publishedAt parses yt-dlp's upload date, which is usually a plain date.
+func publishedAt(value string) (time.Time, bool) {
+	for _, layout := range []string{time.DateOnly, "20060102", time.RFC3339} {
+		if parsed, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func feedID(query tldw.SummaryQuery) string {
+	id := "urn:tldw:summaries"
+	if channel := strings.TrimSpace(query.Channel); channel != "" {
+		id += ":channel:" + strings.ToLower(channel)
+	}
+	if tag := strings.TrimSpace(query.Tag); tag != "" {
+		id += ":tag:" + strings.ToLower(tag)
+	}
+	return id
+}
+
+func feedTitle(query tldw.SummaryQuery) string {
+	var filters []string
+	if channel := strings.TrimSpace(query.Channel); channel != "" {
+		filters = append(filters, "channel "+channel)
+	}
+	if tag := strings.TrimSpace(query.Tag); tag != "" {
+		filters = append(filters, "tag "+tag)
+	}
+	if len(filters) == 0 {
+		return "tldw summaries"
+	}
+	return "tldw summaries: " + strings.Join(filters, ", ")
+}
+
+// Handler serves the feed. The channel, tag, and limit query parameters
+// filter it like the corresponding tldw feed flags.
+func Handler(source Source, now func() time.Time) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		params := r.URL.Query()
+		query := tldw.SummaryQuery{Channel: params.Get("channel"), Tag: params.Get("tag"), Limit: DefaultLimit}
+		if value := params.Get("limit"); value != "" {
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit <= 0 {
+				http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			query.Limit = limit
+		}
+		summaries, err := source.RecentSummaries(query)
+		if err != nil {
+			http.Error(w, "listing summaries failed", http.StatusInternalServerError)
+			return
+		}
+		var body bytes.Buffer
+		if err := Write(&body, query, summaries, now()); err != nil {
+			http.Error(w, "rendering feed failed", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", ContentType)
+		_, _ = body.WriteTo(w)
+	})
+}
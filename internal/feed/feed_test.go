@@ -0,0 +1,91 @@
+package feed_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rtzll/tldw/internal/feed"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+type sourceStub struct {
+	queries   []tldw.SummaryQuery
+	summaries []tldw.StoredSummary
+}
+
+func (stub *sourceStub) RecentSummaries(query tldw.SummaryQuery) ([]tldw.StoredSummary, error) {
+	stub.queries = append(stub.queries, query)
+	return stub.summaries, nil
+}
+
+func TestWriteRendersSummariesAsAtomEntries(t *testing.T) {
+	summarizedAt := time.Date(2026, time.July, 3, 12, 0, 0, 0, time.UTC)
+	summaries := []tldw.StoredSummary{{
+		VideoID:      "dQw4w9WgXcQ",
+		Metadata:     &tldw.VideoMetadata{Title: "B-trees & friends", Channel: "CMU Database Group", PublishedAt: "2026-07-01", Tags: []string{"databases"}},
+		Markdown:     "## Key points\n\n- Pages <script>alert(1)</script> split",
+		SummarizedAt: summarizedAt,
+	}}
+
+	var out strings.Builder
+	if err := feed.Write(&out, tldw.SummaryQuery{Channel: "CMU"}, summaries, time.Now()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var doc struct {
+		Title   string `xml:"title"`
+		Updated string `xml:"updated"`
+		Entries []struct {
+			ID        string `xml:"id"`
+			Title     string `xml:"title"`
+			Published string `xml:"published"`
+			Author    string `xml:"author>name"`
+			Link      struct {
+				Href string `xml:"href,attr"`
+			} `xml:"link"`
+			Content string `xml:"content"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal([]byte(out.String()), &doc); err != nil {
+		t.Fatalf("feed is not valid XML: %v\n%s", err, out.String())
+	}
+	if doc.Title != "tldw summaries: channel CMU" || doc.Updated != "2026-07-03T12:00:00Z" || len(doc.Entries) != 1 {
+		t.Fatalf("feed = %+v", doc)
+	}
+	entry := doc.Entries[0]
+	if entry.ID != "yt:video:dQw4w9WgXcQ" || entry.Title != "B-trees & friends" || entry.Author != "CMU Database Group" ||
+		entry.Published != "2026-07-01T00:00:00Z" || entry.Link.Href != "https://www.youtube.com/watch?v=dQw4w9WgXcQ" {
+		t.Fatalf("entry = %+v", entry)
+	}
+	if !strings.Contains(entry.Content, "<h2>Key points</h2>") || strings.Contains(entry.Content, "<script>") {
+		t.Fatalf("entry content = %q, want rendered Markdown without raw HTML", entry.Content)
+	}
+}
+
+func TestHandlerPassesFiltersAndRejectsBadLimits(t *testing.T) {
+	source := &sourceStub{}
+	handler := feed.Handler(source, time.Now)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/feed.xml?channel=cmu&tag=go&limit=5", nil))
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Type") != feed.ContentType {
+		t.Fatalf("GET status = %d, content type %q", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+	if want := (tldw.SummaryQuery{Channel: "cmu", Tag: "go", Limit: 5}); len(source.queries) != 1 || source.queries[0] != want {
+		t.Fatalf("queries = %+v, want %+v", source.queries, want)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/feed.xml?limit=zero", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("bad limit status = %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/feed.xml", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST status = %d, want %d", recorder.Code, http.StatusMethodNotAllowed)
+	}
+}
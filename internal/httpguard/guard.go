@@ -0,0 +1,131 @@
+// Package httpguard protects local HTTP servers from other sites and
+// networks: it checks Host and Origin headers and bearer tokens.
+package httpguard
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// AllowedHosts returns the Host header allowlist, or nil when the server
+// listens on a wildcard address without configured hosts and any Host is
+// accepted.
+func AllowedHosts(listenHost string, configured []string) []string {
+	if IsWildcardHost(listenHost) && len(configured) == 0 {
+		return nil
+	}
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	if !IsWildcardHost(listenHost) {
+		hosts = append(hosts, listenHost)
+	}
+	for _, host := range configured {
+		hosts = append(hosts, strings.ToLower(strings.TrimSpace(host)))
+	}
+	return hosts
+}
+
+// HostAndOrigin rejects requests whose Host is not allowlisted and browser
+// requests from unknown origins, which stops DNS rebinding and cross-site
+// requests. Rejections are logged to logger unless it is nil.
+func HostAndOrigin(hosts, origins []string, logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hosts != nil && !slices.Contains(hosts, hostname(r.Host)) {
+			logRejection(r.Context(), logger, "rejected request with invalid Host", slog.String("host", r.Host))
+			http.Error(w, fmt.Sprintf("Forbidden: invalid Host header %q", r.Host), http.StatusForbidden)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" && !originAllowed(origin, r.Host, origins) {
+			logRejection(r.Context(), logger, "rejected request from invalid Origin", slog.String("origin", origin))
+			http.Error(w, fmt.Sprintf("Forbidden: invalid Origin header %q", origin), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// BearerToken answers 401 unless the request carries one of the tokens,
+// which map a token name to its value. Rejections are logged to logger unless
+// it is nil.
+func BearerToken(tokens map[string]string, logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || TokenName(tokens, strings.TrimSpace(token)) == "" {
+			logRejection(r.Context(), logger, "rejected request without a valid token", slog.String("path", r.URL.Path))
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Unauthorized: missing or invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// TokenName returns the name of the token, or "" when it matches none.
+// Tokens are compared in constant time.
+func TokenName(tokens map[string]string, token string) string {
+	if token == "" {
+		return ""
+	}
+	for name, want := range tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1 {
+			return name
+		}
+	}
+	return ""
+}
+
+// originAllowed accepts configured origins, pages served by this server, and
+// pages served from loopback, such as a local MCP inspector.
+func originAllowed(origin, host string, allowed []string) bool {
+	origin = strings.TrimSuffix(origin, "/")
+	for _, candidate := range allowed {
+		if strings.EqualFold(origin, strings.TrimSuffix(strings.TrimSpace(candidate), "/")) {
+			return true
+		}
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	if strings.EqualFold(parsed.Host, host) {
+		return true
+	}
+	return IsLoopbackHost(parsed.Hostname())
+}
+
+// hostname strips the port and IPv6 brackets from a Host header.
+func hostname(hostport string) string {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	return strings.ToLower(strings.Trim(host, "[]"))
+}
+
+// IsLoopbackHost reports whether host is localhost or a loopback address.
+func IsLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// IsWildcardHost reports whether host listens on every interface, such as
+// 0.0.0.0 or ::.
+func IsWildcardHost(host string) bool {
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsUnspecified()
+}
+
+func logRejection(ctx context.Context, logger *slog.Logger, msg string, attr slog.Attr) {
+	if logger != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, msg, attr)
+	}
+}
@@ -0,0 +1,82 @@
+package httpguard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHostAndOrigin(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := HostAndOrigin(
+		AllowedHosts("127.0.0.1", []string{"tldw.example.com"}),
+		[]string{"https://chat.example.com"},
+		nil,
+		next,
+	)
+
+	tests := []struct {
+		name   string
+		host   string
+		origin string
+		want   int
+	}{
+		{"loopback host", "127.0.0.1:8765", "", http.StatusNoContent},
+		{"localhost", "localhost:8765", "", http.StatusNoContent},
+		{"configured host", "tldw.example.com", "", http.StatusNoContent},
+		{"rebound host", "attacker.example:8765", "", http.StatusForbidden},
+		{"configured origin", "tldw.example.com", "https://chat.example.com", http.StatusNoContent},
+		{"loopback origin", "localhost:8765", "http://localhost:6274", http.StatusNoContent},
+		{"same origin", "tldw.example.com", "http://tldw.example.com", http.StatusNoContent},
+		{"unknown origin", "localhost:8765", "http://attacker.example", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/", nil)
+			request.Host = tt.host
+			if tt.origin != "" {
+				request.Header.Set("Origin", tt.origin)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			if recorder.Code != tt.want {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestWildcardListenAcceptsAnyHostUnlessConfigured(t *testing.T) {
+	if hosts := AllowedHosts("0.0.0.0", nil); hosts != nil {
+		t.Fatalf("AllowedHosts(0.0.0.0) = %v, want no Host check", hosts)
+	}
+	hosts := AllowedHosts("0.0.0.0", []string{"TLDW.example.com"})
+	if len(hosts) == 0 || hosts[len(hosts)-1] != "tldw.example.com" {
+		t.Fatalf("AllowedHosts(0.0.0.0, configured) = %v", hosts)
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	handler := BearerToken(map[string]string{"laptop": "secret-1"}, nil, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for header, want := range map[string]int{
+		"":                http.StatusUnauthorized,
+		"Bearer wrong":    http.StatusUnauthorized,
+		"secret-1":        http.StatusUnauthorized,
+		"Bearer secret-1": http.StatusNoContent,
+	} {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			request.Header.Set("Authorization", header)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != want {
+			t.Errorf("Authorization %q: status = %d, want %d", header, recorder.Code, want)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+// Package markdown renders model-generated Markdown as HTML.
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// renderer drops raw HTML from the source, since the Markdown comes from a
+// model and ends up in feeds and browsers.
+var renderer = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// ToHTML renders GitHub-flavored Markdown as an HTML fragment.
+func ToHTML(source string) (string, error) {
+	var html bytes.Buffer
+	if err := renderer.Convert([]byte(source), &html); err != nil {
+		return "", err
+	}
+	return html.String(), nil
+}
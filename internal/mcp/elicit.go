@@ -0,0 +1,193 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+const (
+	// whisperPricePerMinute is OpenAI's list price for whisper-1 in US dollars.
+	whisperPricePerMinute = 0.006
+	whisperConfirmationID = "confirm_whisper"
+)
+
+var errWhisperDeclined = errors.New("the user declined paid Whisper transcription")
+
+// whisperConfirmationSchema asks for a single yes/no answer.
+var whisperConfirmationSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"confirm": map[string]any{
+			"type":        "boolean",
+			"title":       "Transcribe with Whisper",
+			"description": "Send the audio to OpenAI Whisper, which is billed to the configured API key.",
+		},
+	},
+	"required": []string{"confirm"},
+}
+
+// canConfirm reports whether the calling client supports elicitation, so the
+// server can ask the user directly instead of trusting the model to.
+func canConfirm(request *mcp.CallToolRequest) bool {
+	if request == nil || request.Session == nil {
+		return false
+	}
+	params := request.Session.InitializeParams()
+	return params != nil && params.Capabilities != nil && params.Capabilities.Elicitation != nil
+}
+
+// confirmWhisper asks the user to approve a paid Whisper transcription of ref.
+// The question goes out as an input request: the handler returns the result
+// it gets back, and the SDK retries the call with the user's answer, calling
+// confirmWhisper again. It returns neither a result nor an error once the user
+// agreed, when the client cannot elicit, which leaves consent with the model
+// as before, or when a Whisper transcript is already cached.
+func (s *MCPServer) confirmWhisper(ctx context.Context, request *mcp.CallToolRequest, ref tldw.YouTubeRef) (*mcp.CallToolResult, error) {
+	if !canConfirm(request) || s.whisperCached(ref.ID()) {
+		return nil, nil
+	}
+	return elicitWhisper(ctx, request, ref.ID(), func() (string, error) {
+		metadata, err := s.engine.MetadataFor(ctx, ref)
+		if err != nil {
+			return "", fmt.Errorf("checking video metadata: %w", err)
+		}
+		return whisperConfirmationMessage(ref, metadata), nil
+	})
+}
+
+// playlistWhisperPolicy picks the transcript policy for a playlist summary
+// with allow_whisper. Clients that can elicit are asked once for every video
+// without captions, with their summed duration and cost; the policy falls
+// back to captions only when none needs Whisper or the user declines, so
+// those videos are skipped. Other clients keep consent with the model.
+func (s *MCPServer) playlistWhisperPolicy(ctx context.Context, request *mcp.CallToolRequest, ref tldw.YouTubeRef) (*mcp.CallToolResult, tldw.TranscriptPolicy, error) {
+	if !canConfirm(request) {
+		return nil, tldw.TranscriptPolicyCaptionsThenWhisper, nil
+	}
+	playlist, err := s.engine.Playlist(ctx, ref)
+	if err != nil {
+		return nil, tldw.TranscriptPolicyCaptionsOnly, fmt.Errorf("listing playlist: %w", err)
+	}
+	needsWhisper := false
+	var pending []tldw.PlaylistEntry
+	for _, entry := range playlist.Entries {
+		metadata, err := s.engine.MetadataFor(ctx, entry.Ref)
+		if err == nil && metadata.HasCaptions {
+			continue
+		}
+		needsWhisper = true
+		if s.whisperCached(entry.Ref.ID()) {
+			continue
+		}
+		if err == nil && entry.Duration <= 0 {
+			entry.Duration = metadata.Duration
+		}
+		pending = append(pending, entry)
+	}
+	if !needsWhisper {
+		return nil, tldw.TranscriptPolicyCaptionsOnly, nil
+	}
+	if len(pending) == 0 {
+		return nil, tldw.TranscriptPolicyCaptionsThenWhisper, nil
+	}
+
+	confirmation, err := elicitWhisper(ctx, request, ref.ID(), func() (string, error) {
+		return playlistWhisperConfirmationMessage(playlist.Title, pending), nil
+	})
+	switch {
+	case errors.Is(err, errWhisperDeclined):
+		return nil, tldw.TranscriptPolicyCaptionsOnly, nil
+	case err != nil:
+		return nil, tldw.TranscriptPolicyCaptionsOnly, err
+	case confirmation != nil:
+		return confirmation, tldw.TranscriptPolicyCaptionsOnly, nil
+	}
+	return nil, tldw.TranscriptPolicyCaptionsThenWhisper, nil
+}
+
+// elicitWhisper returns the user's answer to the Whisper confirmation for
+// subject, or the input request that asks for it with message.
+func elicitWhisper(ctx context.Context, request *mcp.CallToolRequest, subject string, message func() (string, error)) (*mcp.CallToolResult, error) {
+	if response, ok := request.Params.InputResponses[whisperConfirmationID]; ok {
+		result, _ := response.(*mcp.ElicitResult)
+		if result == nil || result.Action != "accept" {
+			requestLogger(ctx).Info("Whisper transcription not confirmed", "subject", subject)
+			return nil, errWhisperDeclined
+		}
+		if confirmed, _ := result.Content["confirm"].(bool); !confirmed {
+			requestLogger(ctx).Info("Whisper transcription not confirmed", "subject", subject)
+			return nil, errWhisperDeclined
+		}
+		requestLogger(ctx).Info("Whisper transcription confirmed by the user", "subject", subject)
+		return nil, nil
+	}
+
+	text, err := message()
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.CallToolResult{InputRequests: mcp.InputRequestMap{
+		whisperConfirmationID: &mcp.ElicitParams{
+			Message:         text,
+			RequestedSchema: whisperConfirmationSchema,
+		},
+	}}, nil
+}
+
+func (s *MCPServer) whisperCached(videoID string) bool {
+	s.resourceMu.Lock()
+	library := s.library
+	s.resourceMu.Unlock()
+	if library == nil {
+		return false
+	}
+	transcript, err := library.LoadTranscript(videoID)
+	return err == nil && transcript.Source == tldw.TranscriptSourceWhisper
+}
+
+func whisperConfirmationMessage(ref tldw.YouTubeRef, metadata *tldw.VideoMetadata) string {
+	title := metadata.Title
+	if title == "" {
+		title = ref.ID()
+	}
+	if metadata.Duration <= 0 {
+		return fmt.Sprintf("Transcribe %q with OpenAI Whisper? The duration is unknown, so the cost cannot be estimated ($%.3f per minute).",
+			title, whisperPricePerMinute)
+	}
+	duration := time.Duration(metadata.Duration) * time.Second
+	return fmt.Sprintf("Transcribe %q (%s) with OpenAI Whisper? Estimated cost: %s.", title, duration, formatWhisperCost(metadata.Duration))
+}
+
+func playlistWhisperConfirmationMessage(title string, entries []tldw.PlaylistEntry) string {
+	var seconds float64
+	unknown := 0
+	for _, entry := range entries {
+		if entry.Duration <= 0 {
+			unknown++
+		}
+		seconds += max(entry.Duration, 0)
+	}
+	duration := time.Duration(seconds) * time.Second
+	message := fmt.Sprintf("Transcribe %d videos of %q without captions (%s in total) with OpenAI Whisper? Estimated cost: %s.",
+		len(entries), title, duration, formatWhisperCost(seconds))
+	if unknown > 0 {
+		message += fmt.Sprintf(" %d of them have an unknown duration and are not included ($%.3f per minute).", unknown, whisperPricePerMinute)
+	}
+	return message
+}
+
+func formatWhisperCost(seconds float64) string {
+	if estimateWhisperCost(seconds) < 0.01 {
+		return "less than $0.01"
+	}
+	return fmt.Sprintf("$%.2f", estimateWhisperCost(seconds))
+}
+
+func estimateWhisperCost(seconds float64) float64 {
+	return seconds / 60 * whisperPricePerMinute
+}
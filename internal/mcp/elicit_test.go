@@ -0,0 +1,231 @@
+package mcpserver
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+func elicitingClient(action string, confirm bool, messages *[]string) *mcp.ClientOptions {
+	return &mcp.ClientOptions{
+		ElicitationHandler: func(_ context.Context, request *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			*messages = append(*messages, request.Params.Message)
+			if action != "accept" {
+				return &mcp.ElicitResult{Action: action}, nil
+			}
+			return &mcp.ElicitResult{Action: action, Content: map[string]any{"confirm": confirm}}, nil
+		},
+	}
+}
+
+func TestMCPWhisperAsksForConfirmationWithCost(t *testing.T) {
+	app := &applicationStub{
+		metadata:   &tldw.VideoMetadata{Title: "Long talk", Duration: 3000},
+		transcript: &tldw.Transcript{Source: tldw.TranscriptSourceWhisper, Text: "whisper transcript"},
+	}
+	var messages []string
+	ctx, clientSession := connectTestMCPClientWithOptions(t, NewMCPServer(app), elicitingClient("accept", true, &messages))
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "transcribe_youtube_whisper",
+		Arguments: map[string]any{"url": "https://youtu.be/dQw4w9WgXcQ"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("CallTool() returned tool error: %s", textContent(t, result))
+	}
+	if len(messages) != 1 || !strings.Contains(messages[0], `"Long talk" (50m0s)`) || !strings.Contains(messages[0], "$0.30") {
+		t.Fatalf("confirmation messages = %q, want title, duration, and cost", messages)
+	}
+	if app.transcriptCalls != 1 {
+		t.Fatalf("Transcript() calls = %d, want 1 after confirmation", app.transcriptCalls)
+	}
+}
+
+func TestMCPWhisperStopsWhenUserDeclines(t *testing.T) {
+	for _, tt := range []struct {
+		action  string
+		confirm bool
+	}{{"decline", false}, {"cancel", false}, {"accept", false}} {
+		t.Run(tt.action, func(t *testing.T) {
+			app := &applicationStub{metadata: &tldw.VideoMetadata{Title: "Long talk", Duration: 3000}}
+			var messages []string
+			ctx, clientSession := connectTestMCPClientWithOptions(t, NewMCPServer(app), elicitingClient(tt.action, tt.confirm, &messages))
+
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "transcribe_youtube_whisper",
+				Arguments: map[string]any{"url": "https://youtu.be/dQw4w9WgXcQ"},
+			})
+			if err != nil {
+				t.Fatalf("CallTool() error = %v", err)
+			}
+			if !result.IsError || !strings.Contains(textContent(t, result), "declined") {
+				t.Fatalf("result = %+v, want a declined tool error", result)
+			}
+			if app.transcriptCalls != 0 {
+				t.Fatalf("Transcript() calls = %d, want none without confirmation", app.transcriptCalls)
+			}
+		})
+	}
+}
+
+func TestMCPGetTranscriptFallsBackToConfirmedWhisper(t *testing.T) {
+	app := &applicationStub{
+		metadata:      &tldw.VideoMetadata{Title: "No captions", Duration: 20},
+		transcriptErr: tldw.ErrCaptionsUnavailable,
+		whisper:       &tldw.Transcript{Source: tldw.TranscriptSourceWhisper, Text: "whisper transcript"},
+	}
+	var messages []string
+	ctx, clientSession := connectTestMCPClientWithOptions(t, NewMCPServer(app), elicitingClient("accept", true, &messages))
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "get_youtube_transcript",
+		Arguments: map[string]any{"url": "https://youtu.be/dQw4w9WgXcQ", "allow_whisper": true},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("CallTool() returned tool error: %s", textContent(t, result))
+	}
+	if len(messages) != 1 || !strings.Contains(messages[0], "less than $0.01") {
+		t.Fatalf("confirmation messages = %q", messages)
+	}
+	output := structuredContent[mcpTranscriptOutput](t, result)
+	if output.Source != string(tldw.TranscriptSourceWhisper) || output.Transcript != "whisper transcript" {
+		t.Fatalf("output = %+v, want the Whisper transcript", output)
+	}
+	if app.transcriptCalls != 3 || app.lastRequest.Policy != tldw.TranscriptPolicyWhisperOnly {
+		t.Fatalf("Transcript() calls = %d, last policy = %v", app.transcriptCalls, app.lastRequest.Policy)
+	}
+}
+
+func TestMCPGetTranscriptKeepsCaptionsOnlyWithoutElicitation(t *testing.T) {
+	app := &applicationStub{
+		transcriptErr: tldw.ErrCaptionsUnavailable,
+		whisper:       &tldw.Transcript{Source: tldw.TranscriptSourceWhisper, Text: "whisper transcript"},
+	}
+	ctx, clientSession := connectTestMCPClient(t, NewMCPServer(app))
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "get_youtube_transcript",
+		Arguments: map[string]any{"url": "https://youtu.be/dQw4w9WgXcQ", "allow_whisper": true},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !result.IsError || !strings.Contains(textContent(t, result), "transcribe_youtube_whisper") {
+		t.Fatalf("result = %+v, want the captions error", result)
+	}
+	if app.transcriptCalls != 1 || app.metadataCalls != 0 {
+		t.Fatalf("Transcript() calls = %d, MetadataFor() calls = %d, want captions only", app.transcriptCalls, app.metadataCalls)
+	}
+}
+
+func TestMCPSummarizeFallsBackToConfirmedWhisper(t *testing.T) {
+	app := &applicationStub{
+		metadata:   &tldw.VideoMetadata{Title: "No captions", Duration: 3000},
+		summaryErr: tldw.ErrCaptionsUnavailable,
+		whisper:    &tldw.Transcript{Source: tldw.TranscriptSourceWhisper, Text: "## Whisper summary"},
+	}
+	var messages []string
+	ctx, clientSession := connectTestMCPClientWithOptions(t, NewMCPServer(app), elicitingClient("accept", true, &messages))
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "summarize_youtube_video",
+		Arguments: map[string]any{"url": "https://youtu.be/dQw4w9WgXcQ", "allow_whisper": true},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("CallTool() returned tool error: %s", textContent(t, result))
+	}
+	if len(messages) != 1 || !strings.Contains(messages[0], "$0.30") {
+		t.Fatalf("confirmation messages = %q, want the Whisper cost", messages)
+	}
+	output := structuredContent[mcpSummaryOutput](t, result)
+	if output.TranscriptSource != string(tldw.TranscriptSourceWhisper) {
+		t.Fatalf("output = %+v, want a summary of the Whisper transcript", output)
+	}
+}
+
+func TestMCPSummarizeStopsWhenUserDeclinesWhisper(t *testing.T) {
+	app := &applicationStub{
+		metadata:   &tldw.VideoMetadata{Title: "No captions", Duration: 3000},
+		summaryErr: tldw.ErrCaptionsUnavailable,
+		whisper:    &tldw.Transcript{Source: tldw.TranscriptSourceWhisper, Text: "## Whisper summary"},
+	}
+	var messages []string
+	ctx, clientSession := connectTestMCPClientWithOptions(t, NewMCPServer(app), elicitingClient("decline", false, &messages))
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "summarize_youtube_video",
+		Arguments: map[string]any{"url": "https://youtu.be/dQw4w9WgXcQ", "allow_whisper": true},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !result.IsError || !strings.Contains(textContent(t, result), "declined") {
+		t.Fatalf("result = %+v, want a declined tool error", result)
+	}
+	if app.summaryRequest.Transcript.Policy != tldw.TranscriptPolicyCaptionsOnly {
+		t.Fatalf("SummarizeVideo() policy = %v, want captions only", app.summaryRequest.Transcript.Policy)
+	}
+}
+
+func TestMCPSummarizePlaylistConfirmsWhisperForAllVideosAtOnce(t *testing.T) {
+	first, _ := tldw.ParseVideoRef("dQw4w9WgXcQ")
+	second, _ := tldw.ParseVideoRef("tAP1eZYEuKA")
+	playlist := &tldw.PlaylistInfo{Title: "Talks", Entries: []tldw.PlaylistEntry{
+		{Ref: first, Title: "One", Duration: 600},
+		{Ref: second, Title: "Two", Duration: 1200},
+	}}
+
+	for _, tt := range []struct {
+		name       string
+		action     string
+		hasCaption bool
+		wantAsked  bool
+		wantPolicy tldw.TranscriptPolicy
+	}{
+		{"confirmed", "accept", false, true, tldw.TranscriptPolicyCaptionsThenWhisper},
+		{"declined", "decline", false, true, tldw.TranscriptPolicyCaptionsOnly},
+		{"all captioned", "accept", true, false, tldw.TranscriptPolicyCaptionsOnly},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &applicationStub{
+				metadata:       &tldw.VideoMetadata{HasCaptions: tt.hasCaption},
+				playlist:       playlist,
+				playlistResult: tldw.PlaylistSummaryResult{Title: "Talks", Markdown: "## Talks", Processed: 2, Total: 2},
+			}
+			var messages []string
+			ctx, clientSession := connectTestMCPClientWithOptions(t, NewMCPServer(app), elicitingClient(tt.action, true, &messages))
+
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "summarize_youtube_playlist",
+				Arguments: map[string]any{"url": "https://www.youtube.com/playlist?list=PLSE8ODhjZXjYDBpQnSymaectKjxCy6BYq", "allow_whisper": true},
+			})
+			if err != nil {
+				t.Fatalf("CallTool() error = %v", err)
+			}
+			if result.IsError {
+				t.Fatalf("CallTool() returned tool error: %s", textContent(t, result))
+			}
+			if asked := len(messages) == 1; asked != tt.wantAsked {
+				t.Fatalf("confirmation messages = %q, want asked %t", messages, tt.wantAsked)
+			}
+			if tt.wantAsked && (!strings.Contains(messages[0], "2 videos") || !strings.Contains(messages[0], "30m0s in total") || !strings.Contains(messages[0], "$0.18")) {
+				t.Fatalf("confirmation message = %q, want the summed duration and cost", messages[0])
+			}
+			if app.playlistRequest.Transcript.Policy != tt.wantPolicy {
+				t.Fatalf("CreatePlaylistSummary() policy = %v, want %v", app.playlistRequest.Transcript.Policy, tt.wantPolicy)
+			}
+		})
+	}
+}
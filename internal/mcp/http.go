@@ -0,0 +1,141 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rtzll/tldw/internal/httpguard"
+	"github.com/rtzll/tldw/internal/metrics"
+)
+
+// HTTPOptions secures the HTTP transport.
+type HTTPOptions struct {
+	// Tokens maps a token name, which appears in logs, to its bearer token.
+	// Without tokens the server only listens on loopback hosts.
+	Tokens map[string]string
+	// AllowedHosts extends the Host header allowlist beyond loopback names
+	// and the listen host, e.g. for a tunnel's public hostname.
+	AllowedHosts []string
+	// AllowedOrigins lists browser origins, such as https://chat.example.com,
+	// that may call the server. Requests without an Origin header come from
+	// non-browser clients and are not restricted.
+	AllowedOrigins []string
+	TLSCertFile    string
+	TLSKeyFile     string
+	// Readiness lists the dependencies /readyz verifies.
+	Readiness []ReadinessCheck
+}
+
+// ReadinessCheck verifies one dependency the tools need, such as an external
+// command or the transcript store.
+type ReadinessCheck struct {
+	Name  string
+	Check func(context.Context) error
+}
+
+// SetHTTPOptions configures authentication, Host and Origin checks, and TLS
+// for the HTTP transport.
+func (s *MCPServer) SetHTTPOptions(options HTTPOptions) error {
+	if (options.TLSCertFile == "") != (options.TLSKeyFile == "") {
+		return fmt.Errorf("MCP TLS needs both a certificate and a key file")
+	}
+	for name, token := range options.Tokens {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("MCP token names must not be empty")
+		}
+		if strings.TrimSpace(token) == "" {
+			return fmt.Errorf("MCP token %q is empty", name)
+		}
+	}
+	s.httpOptions = options
+	return nil
+}
+
+// httpHandler wraps the streamable HTTP handler with the configured checks.
+// Authentication runs last so that rejected hosts and origins never learn
+// whether a token was valid.
+func (s *MCPServer) httpHandler(host string) (http.Handler, error) {
+	options := s.httpOptions
+	if len(options.Tokens) == 0 && !httpguard.IsLoopbackHost(host) {
+		return nil, fmt.Errorf("refusing to serve HTTP MCP on %s without tokens; set mcp_tokens or mcp_tokens_file", host)
+	}
+
+	authenticate := func(handler http.Handler) http.Handler { return handler }
+	if len(options.Tokens) > 0 {
+		authenticate = auth.RequireBearerToken(verifyToken(options.Tokens), &auth.RequireBearerTokenOptions{
+			AllowMissingExpiration: true,
+		})
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", authenticate(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		return s.mcpServer
+	}, &mcp.StreamableHTTPOptions{
+		// httpguard.HostAndOrigin replaces the SDK's loopback-only Host check so
+		// that AllowedHosts can admit tunnel and LAN hostnames.
+		DisableLocalhostProtection: true,
+	})))
+	// Health probes carry no data and stay open to supervisors; metrics
+	// describe usage and need a token like the tools.
+	mux.HandleFunc("GET /healthz", serveHealth)
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		serveReadiness(w, r, options.Readiness)
+	})
+	mux.Handle("GET /metrics", authenticate(metrics.Default.Handler()))
+	hosts := httpguard.AllowedHosts(host, options.AllowedHosts)
+	return httpguard.HostAndOrigin(hosts, options.AllowedOrigins, serverLogger(), mux), nil
+}
+
+func serveHealth(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = fmt.Fprintln(w, "ok")
+}
+
+// serveReadiness runs every check and answers 503 if one fails. The response
+// only names the failed checks; their errors go to the MCP log, since probes
+// are unauthenticated.
+func serveReadiness(w http.ResponseWriter, r *http.Request, checks []ReadinessCheck) {
+	var body strings.Builder
+	status := http.StatusOK
+	for _, check := range checks {
+		result := "ok"
+		if err := check.Check(r.Context()); err != nil {
+			serverLogger().Error("readiness check failed", "check", check.Name, "error", err)
+			result = "failed"
+			status = http.StatusServiceUnavailable
+		}
+		fmt.Fprintf(&body, "%s: %s\n", check.Name, result)
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, body.String())
+}
+
+// verifyToken resolves a bearer token to its name, which the tool call log
+// reports as the caller.
+func verifyToken(tokens map[string]string) auth.TokenVerifier {
+	return func(_ context.Context, token string, _ *http.Request) (*auth.TokenInfo, error) {
+		if name := httpguard.TokenName(tokens, token); name != "" {
+			return &auth.TokenInfo{UserID: name}, nil
+		}
+		return nil, auth.ErrInvalidToken
+	}
+}
+
+// listenAndServe serves plain HTTP, or HTTPS when TLS files are configured.
+func (s *MCPServer) listenAndServe(server *http.Server) error {
+	if s.httpOptions.TLSCertFile != "" {
+		err := server.ListenAndServeTLS(s.httpOptions.TLSCertFile, s.httpOptions.TLSKeyFile)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("serving HTTPS: %w", err)
+		}
+		return err
+	}
+	return server.ListenAndServe()
+}
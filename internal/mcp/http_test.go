@@ -0,0 +1,176 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+type bearerTransport struct {
+	token string
+}
+
+func (t bearerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+t.token)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestMCPHTTPRequiresTokensOffLoopback(t *testing.T) {
+	server := NewMCPServer(&applicationStub{})
+	for _, host := range []string{"0.0.0.0", "192.168.1.20", "tldw.example.com"} {
+		if _, err := server.httpHandler(host); err == nil {
+			t.Errorf("httpHandler(%q) succeeded without tokens", host)
+		}
+	}
+	for _, host := range []string{"127.0.0.1", "localhost", "::1"} {
+		if _, err := server.httpHandler(host); err != nil {
+			t.Errorf("httpHandler(%q) error = %v", host, err)
+		}
+	}
+}
+
+func TestMCPHTTPRejectsIncompleteTLSAndEmptyTokens(t *testing.T) {
+	server := NewMCPServer(&applicationStub{})
+	if err := server.SetHTTPOptions(HTTPOptions{TLSCertFile: "cert.pem"}); err == nil {
+		t.Error("SetHTTPOptions() accepted a certificate without a key")
+	}
+	if err := server.SetHTTPOptions(HTTPOptions{Tokens: map[string]string{"laptop": " "}}); err == nil {
+		t.Error("SetHTTPOptions() accepted an empty token")
+	}
+}
+
+func TestMCPHTTPAuthenticatesBearerTokens(t *testing.T) {
+	server := NewMCPServer(&applicationStub{})
+	if err := server.SetHTTPOptions(HTTPOptions{Tokens: map[string]string{"laptop": "secret-1", "tunnel": "secret-2"}}); err != nil {
+		t.Fatalf("SetHTTPOptions() error = %v", err)
+	}
+	handler, err := server.httpHandler("0.0.0.0")
+	if err != nil {
+		t.Fatalf("httpHandler() error = %v", err)
+	}
+	httpServer := httptest.NewServer(handler)
+	t.Cleanup(httpServer.Close)
+
+	for name, header := range map[string]string{"missing": "", "wrong": "Bearer nope"} {
+		request, err := http.NewRequest(http.MethodPost, httpServer.URL, strings.NewReader("{}"))
+		if err != nil {
+			t.Fatalf("NewRequest() error = %v", err)
+		}
+		if header != "" {
+			request.Header.Set("Authorization", header)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatalf("%s token: Do() error = %v", name, err)
+		}
+		_ = response.Body.Close()
+		if response.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s token: status = %d, want 401", name, response.StatusCode)
+		}
+	}
+
+	ctx := t.Context()
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v0.0.1"}, nil)
+	session, err := client.Connect(ctx, &mcp.StreamableClientTransport{
+		Endpoint:             httpServer.URL,
+		HTTPClient:           &http.Client{Transport: bearerTransport{token: "secret-2"}},
+		DisableStandaloneSSE: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Connect() with a valid token error = %v", err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+	if _, err := session.ListTools(ctx, nil); err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+}
+
+func TestMCPHTTPVerifyTokenNamesCaller(t *testing.T) {
+	verify := verifyToken(map[string]string{"laptop": "secret-1", "tunnel": "secret-2"})
+	info, err := verify(t.Context(), "secret-2", nil)
+	if err != nil || info.UserID != "tunnel" {
+		t.Fatalf("verifyToken() = %+v, %v, want the tunnel token", info, err)
+	}
+}
+
+func TestMCPHTTPServesHealthAndReadiness(t *testing.T) {
+	server := NewMCPServer(&applicationStub{})
+	if err := server.SetHTTPOptions(HTTPOptions{
+		Tokens: map[string]string{"laptop": "secret-1"},
+		Readiness: []ReadinessCheck{
+			{Name: "yt-dlp", Check: func(context.Context) error { return nil }},
+			{Name: "store", Check: func(context.Context) error { return errors.New("read-only file system") }},
+		},
+	}); err != nil {
+		t.Fatalf("SetHTTPOptions() error = %v", err)
+	}
+	handler, err := server.httpHandler("127.0.0.1")
+	if err != nil {
+		t.Fatalf("httpHandler() error = %v", err)
+	}
+
+	health := httptest.NewRecorder()
+	handler.ServeHTTP(health, httptest.NewRequest(http.MethodGet, "http://127.0.0.1/healthz", nil))
+	if health.Code != http.StatusOK {
+		t.Errorf("/healthz status = %d, want 200 without a token", health.Code)
+	}
+
+	ready := httptest.NewRecorder()
+	handler.ServeHTTP(ready, httptest.NewRequest(http.MethodGet, "http://127.0.0.1/readyz", nil))
+	if ready.Code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz status = %d, want 503", ready.Code)
+	}
+	if got, want := ready.Body.String(), "yt-dlp: ok\nstore: failed\n"; got != want {
+		t.Errorf("/readyz body = %q, want %q", got, want)
+	}
+}
+
+func TestMCPHTTPMetricsNeedTokenAndCountToolCalls(t *testing.T) {
+	server := NewMCPServer(&applicationStub{metadata: &tldw.VideoMetadata{Title: "Video"}})
+	if err := server.SetHTTPOptions(HTTPOptions{Tokens: map[string]string{"laptop": "secret-1"}}); err != nil {
+		t.Fatalf("SetHTTPOptions() error = %v", err)
+	}
+	handler, err := server.httpHandler("127.0.0.1")
+	if err != nil {
+		t.Fatalf("httpHandler() error = %v", err)
+	}
+
+	ctx, clientSession := connectTestMCPClient(t, server)
+	if _, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "get_youtube_metadata",
+		Arguments: map[string]any{"url": "https://youtu.be/dQw4w9WgXcQ"},
+	}); err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+
+	anonymous := httptest.NewRecorder()
+	handler.ServeHTTP(anonymous, httptest.NewRequest(http.MethodGet, "http://127.0.0.1/metrics", nil))
+	if anonymous.Code != http.StatusUnauthorized {
+		t.Errorf("/metrics without a token status = %d, want 401", anonymous.Code)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "http://127.0.0.1/metrics", nil)
+	request.Header.Set("Authorization", "Bearer secret-1")
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	if response.Code != http.StatusOK {
+		t.Fatalf("/metrics status = %d, want 200", response.Code)
+	}
+	for _, want := range []string{
+		`tldw_mcp_tool_calls_total{tool="get_youtube_metadata",outcome="ok"}`,
+		`tldw_mcp_tool_call_duration_seconds_count{tool="get_youtube_metadata"}`,
+		"# TYPE tldw_ytdlp_invocations_total counter",
+		"# TYPE tldw_summary_tokens_total counter",
+	} {
+		if !strings.Contains(response.Body.String(), want) {
+			t.Errorf("/metrics is missing %q", want)
+		}
+	}
+}
@@ -1,60 +1,379 @@
 package mcpserver
 
 import (
-	"log"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
 	"sync"
+	"time"
 
-	"github.com/adrg/xdg"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rtzll/tldw/internal/metrics"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+// LogFileName is the name of the MCP log in the cache directory.
+const LogFileName = "mcp.log"
+
+// Error classes recorded with failed tool calls. They follow the error codes
+// of the JSON API.
+const (
+	errorClassInvalidRequest        = "invalid_request"
+	errorClassNotFound              = "not_found"
+	errorClassCaptionsUnavailable   = "captions_unavailable"
+	errorClassTimestampsUnavailable = "timestamps_unavailable"
+	errorClassDownloadFailed        = "download_failed"
+	errorClassDeclined              = "declined"
+	errorClassTimeout               = "timeout"
+	errorClassCanceled              = "canceled"
+	errorClassInternal              = "internal"
 )
 
 var (
-	mcpLogger     *log.Logger
+	mcpLogger     *slog.Logger
 	mcpLoggerOnce sync.Once
-	mcpLogEnabled bool
 )
 
-// InitLogging initializes MCP logging.
-func InitLogging(enabled bool) {
+// LogOptions configures the MCP log.
+type LogOptions struct {
+	Enabled bool
+	// Path is the log file; rotated files get the suffixes .1, .2, and so on.
+	Path string
+	// MaxSize is the size in bytes at which the log rotates. Zero never
+	// rotates.
+	MaxSize int64
+	// MaxFiles is the number of rotated files kept next to the log.
+	MaxFiles int
+}
+
+// InitLogging initializes MCP logging. Records are JSON lines written with
+// log/slog; logging stays off if the file cannot be opened.
+func InitLogging(options LogOptions) {
 	mcpLoggerOnce.Do(func() {
-		mcpLogEnabled = enabled
-		if !enabled {
+		if !options.Enabled {
 			return
 		}
-
-		logDir := filepath.Join(xdg.CacheHome, "tldw")
-		if err := os.MkdirAll(logDir, 0o755); err != nil {
-			mcpLogEnabled = false
+		file, err := openRotatingFile(options.Path, options.MaxSize, options.MaxFiles)
+		if err != nil {
 			return
 		}
+		mcpLogger = slog.New(slog.NewJSONHandler(file, nil))
+	})
+}
 
-		logPath := filepath.Join(logDir, "mcp.log")
-		logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
-		if err != nil {
-			mcpLogEnabled = false
-			return
+// serverLogger returns the MCP log for records outside a request, such as
+// transport events. Without a log it discards records.
+func serverLogger() *slog.Logger {
+	if mcpLogger != nil {
+		return mcpLogger
+	}
+	return slog.New(slog.DiscardHandler)
+}
+
+type requestLoggerKey struct{}
+
+// requestLogger returns the logger of the tool call, prompt, or resource read
+// in ctx, whose records carry its request_id and session_id. Outside a
+// request it is serverLogger.
+func requestLogger(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(requestLoggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return serverLogger()
+}
+
+// observeToolCalls counts every tool call by outcome and, when the log is
+// enabled, writes one record per call with its duration. Each call gets its
+// own request ID, the session ID groups the calls of one connection, and over
+// HTTP the record also names the token, since tools can start paid Whisper
+// transcription. Handlers log through requestLogger, so their records share the
+// call's request ID.
+func observeToolCalls(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, request mcp.Request) (mcp.Result, error) {
+		call, ok := request.(*mcp.CallToolRequest)
+		if method != mcpMethodCallTool || !ok {
+			return next(ctx, method, request)
 		}
 
-		mcpLogger = log.New(logFile, "", log.LstdFlags|log.Lmicroseconds)
-	})
+		logger := requestLogger(ctx).With(toolCallContext(call, newRequestID())...)
+		ctx = context.WithValue(ctx, requestLoggerKey{}, logger)
+		start := time.Now()
+		result, err := next(ctx, method, request)
+		duration := time.Since(start)
+		outcome, toolErr := toolCallOutcome(result, err)
+		metrics.ToolCalls.Inc(call.Params.Name, outcome)
+		metrics.ToolCallDuration.Observe(duration.Seconds(), call.Params.Name)
+		level, attrs := toolCallAttrs(call, outcome, toolErr, duration)
+		logger.LogAttrs(ctx, level, "tool call", attrs...)
+		return result, err
+	}
 }
 
-// mcpLogf logs a formatted message if MCP logging is enabled
-func mcpLogf(level, format string, args ...any) {
-	if !mcpLogEnabled || mcpLogger == nil {
-		return
+// toolCallContext returns the attributes every record of a tool call carries.
+func toolCallContext(call *mcp.CallToolRequest, requestID string) []any {
+	return requestContext(call.Session, requestID, slog.String("tool", call.Params.Name))
+}
+
+// logRequests gives prompt and resource handlers a logger whose records
+// carry the request's own request_id, its session_id, and the video ID it
+// refers to, as observeToolCalls does for tool calls.
+func logRequests(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, request mcp.Request) (mcp.Result, error) {
+		var attrs []any
+		switch request := request.(type) {
+		case *mcp.GetPromptRequest:
+			attrs = promptContext(request, newRequestID())
+		case *mcp.ReadResourceRequest:
+			attrs = resourceContext(request, newRequestID())
+		default:
+			return next(ctx, method, request)
+		}
+		ctx = context.WithValue(ctx, requestLoggerKey{}, requestLogger(ctx).With(attrs...))
+		return next(ctx, method, request)
+	}
+}
+
+// promptContext returns the attributes every record of a prompt request
+// carries.
+func promptContext(request *mcp.GetPromptRequest, requestID string) []any {
+	attrs := []any{slog.String("prompt", request.Params.Name)}
+	if ref, err := tldw.ParseVideoRef(request.Params.Arguments["url"]); err == nil {
+		attrs = append(attrs, slog.String("video_id", ref.ID()))
+	}
+	return requestContext(request.Session, requestID, attrs...)
+}
+
+// resourceContext returns the attributes every record of a resource read
+// carries.
+func resourceContext(request *mcp.ReadResourceRequest, requestID string) []any {
+	attrs := []any{slog.String("uri", request.Params.URI)}
+	if videoID, _, ok := parseVideoResourceURI(request.Params.URI); ok {
+		attrs = append(attrs, slog.String("video_id", videoID))
+	}
+	return requestContext(request.Session, requestID, attrs...)
+}
+
+// requestContext adds the request and session IDs to a request's attributes.
+func requestContext(session *mcp.ServerSession, requestID string, attrs ...any) []any {
+	attrs = append(attrs, slog.String("request_id", requestID))
+	if session != nil && session.ID() != "" {
+		attrs = append(attrs, slog.String("session_id", session.ID()))
+	}
+	return attrs
+}
+
+// toolCallOutcome returns ok, error, or input_required, and the error that
+// failed the call, which the SDK turns into a tool result.
+func toolCallOutcome(result mcp.Result, err error) (string, error) {
+	if err != nil {
+		return "error", err
+	}
+	toolResult, ok := result.(*mcp.CallToolResult)
+	switch {
+	case !ok || toolResult == nil:
+		return "ok", nil
+	case toolResult.IsError:
+		return "error", toolResult.GetError()
+	case len(toolResult.InputRequests) > 0:
+		return "input_required", nil
+	default:
+		return "ok", nil
+	}
+}
+
+func toolCallAttrs(call *mcp.CallToolRequest, outcome string, err error, duration time.Duration) (slog.Level, []slog.Attr) {
+	var attrs []slog.Attr
+	if extra := call.GetExtra(); extra != nil && extra.TokenInfo != nil {
+		attrs = append(attrs, slog.String("token", extra.TokenInfo.UserID))
+	}
+	if key, id := referenceArgument(call.Params.Arguments); key != "" {
+		attrs = append(attrs, slog.String(key, id))
 	}
+	attrs = append(attrs,
+		slog.Int64("duration_ms", duration.Milliseconds()),
+		slog.String("outcome", outcome),
+	)
+	if outcome != "error" {
+		return slog.LevelInfo, attrs
+	}
+	if err == nil {
+		return slog.LevelError, append(attrs, slog.String("error_class", errorClassInternal))
+	}
+	return slog.LevelError, append(attrs,
+		slog.String("error_class", errorClass(err)),
+		slog.String("error", err.Error()),
+	)
+}
 
-	mcpLogger.Printf("[MCP] [%s] "+format, append([]any{level}, args...)...)
+// referenceArgument returns the video or playlist ID of a call's url
+// argument.
+func referenceArgument(arguments json.RawMessage) (string, string) {
+	var input struct {
+		URL string `json:"url"`
+	}
+	if json.Unmarshal(arguments, &input) != nil || input.URL == "" {
+		return "", ""
+	}
+	ref, err := tldw.ParseReference(input.URL)
+	if err != nil {
+		return "", ""
+	}
+	if ref.IsPlaylist() {
+		return "playlist_id", ref.ID()
+	}
+	return "video_id", ref.ID()
 }
 
-// MCPLogInfo logs an info message
-func MCPLogInfo(format string, args ...any) {
-	mcpLogf("INFO", format, args...)
+// errorClass maps the engine's sentinel errors to the classes in the log.
+func errorClass(err error) string {
+	var invalid invalidInputError
+	switch {
+	case errors.As(err, &invalid),
+		errors.Is(err, tldw.ErrInvalidTranscriptPolicy),
+		errors.Is(err, tldw.ErrTranscriptWindowInvalid):
+		return errorClassInvalidRequest
+	case errors.Is(err, tldw.ErrStoreNotFound):
+		return errorClassNotFound
+	case errors.Is(err, tldw.ErrCaptionsUnavailable):
+		return errorClassCaptionsUnavailable
+	case errors.Is(err, tldw.ErrTranscriptTimestampsUnavailable):
+		return errorClassTimestampsUnavailable
+	case errors.Is(err, tldw.ErrDownloadFailed):
+		return errorClassDownloadFailed
+	case errors.Is(err, errWhisperDeclined):
+		return errorClassDeclined
+	case errors.Is(err, context.DeadlineExceeded):
+		return errorClassTimeout
+	case errors.Is(err, context.Canceled):
+		return errorClassCanceled
+	default:
+		return errorClassInternal
+	}
+}
+
+// invalidInputError marks tool arguments the client has to fix.
+type invalidInputError struct {
+	err error
+}
+
+func (e invalidInputError) Error() string { return e.err.Error() }
+func (e invalidInputError) Unwrap() error { return e.err }
+
+func newRequestID() string {
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// rotatingFile appends to a log file. When a write would grow the file past
+// maxSize, the file becomes path.1, path.1 becomes path.2, and so on, and the
+// oldest beyond maxFiles is removed.
+type rotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+	file     *os.File
+	size     int64
+}
+
+// renameFile is os.Rename; tests replace it to make rotation fail.
+var renameFile = os.Rename
+
+func openRotatingFile(path string, maxSize int64, maxFiles int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating log directory: %w", err)
+	}
+	file, size, err := openLogFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &rotatingFile{path: path, maxSize: maxSize, maxFiles: max(maxFiles, 0), file: file, size: size}, nil
+}
+
+func openLogFile(path string) (*os.File, int64, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, 0, fmt.Errorf("opening log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, 0, fmt.Errorf("opening log: %w", err)
+	}
+	return file, info.Size(), nil
+}
+
+// Write writes p whole, so a record is never split across two files. When
+// rotation fails, records keep going to the current file, which notes the
+// failure, and rotation is tried again once another maxSize has been written.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			r.size = 0
+			r.writeRotationError(err)
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate moves the log aside and swaps in a new file. The current file stays
+// open until the new one is, so a failure anywhere leaves logging working.
+func (r *rotatingFile) rotate() error {
+	if r.maxFiles == 0 {
+		if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("rotating log: %w", err)
+		}
+	} else {
+		if err := os.Remove(r.rotatedPath(r.maxFiles)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("rotating log: %w", err)
+		}
+		for n := r.maxFiles - 1; n >= 1; n-- {
+			if err := renameFile(r.rotatedPath(n), r.rotatedPath(n+1)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("rotating log: %w", err)
+			}
+		}
+		if err := renameFile(r.path, r.rotatedPath(1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("rotating log: %w", err)
+		}
+	}
+	file, size, err := openLogFile(r.path)
+	if err != nil {
+		return err
+	}
+	_ = r.file.Close()
+	r.file, r.size = file, size
+	return nil
+}
+
+// writeRotationError records a failed rotation in the log itself, in the
+// shape of the JSON records around it.
+func (r *rotatingFile) writeRotationError(rotateErr error) {
+	record, err := json.Marshal(struct {
+		Time  time.Time `json:"time"`
+		Level string    `json:"level"`
+		Msg   string    `json:"msg"`
+		Error string    `json:"error"`
+	}{time.Now(), slog.LevelError.String(), "log rotation failed", rotateErr.Error()})
+	if err != nil {
+		return
+	}
+	n, _ := r.file.Write(append(record, '\n'))
+	r.size += int64(n)
 }
 
-// MCPLogError logs an error message
-func MCPLogError(format string, args ...any) {
-	mcpLogf("ERROR", format, args...)
+func (r *rotatingFile) rotatedPath(n int) string {
+	return r.path + "." + strconv.Itoa(n)
 }
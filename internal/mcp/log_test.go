@@ -0,0 +1,290 @@
+package mcpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+// captureMCPLog sends MCP log records to the returned buffer for the test.
+func captureMCPLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := mcpLogger
+	mcpLogger = slog.New(slog.NewJSONHandler(&buf, nil))
+	t.Cleanup(func() { mcpLogger = previous })
+	return &buf
+}
+
+// logRecords returns every record in the log.
+func logRecords(t *testing.T, log *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(log.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// toolCallRecords returns the tool call records in the log.
+func toolCallRecords(t *testing.T, log *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, record := range logRecords(t, log) {
+		if record["msg"] == "tool call" {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+func TestMCPLogsToolCallOutcome(t *testing.T) {
+	log := captureMCPLog(t)
+	app := &applicationStub{
+		transcript: &tldw.Transcript{Text: "hello"},
+	}
+	server := NewMCPServer(app)
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	if _, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "get_youtube_transcript",
+		Arguments: map[string]any{"url": "https://youtu.be/dQw4w9WgXcQ"},
+	}); err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	app.transcriptErr = tldw.ErrCaptionsUnavailable
+	if _, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "get_youtube_transcript",
+		Arguments: map[string]any{"url": "https://youtu.be/dQw4w9WgXcQ"},
+	}); err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if _, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "summarize_youtube_playlist",
+		Arguments: map[string]any{"url": "not a playlist"},
+	}); err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+
+	records := toolCallRecords(t, log)
+	if len(records) != 3 {
+		t.Fatalf("tool call records = %d, want 3:\n%s", len(records), log)
+	}
+	ok, failed, invalid := records[0], records[1], records[2]
+	if ok["level"] != "INFO" || ok["tool"] != "get_youtube_transcript" || ok["video_id"] != "dQw4w9WgXcQ" || ok["outcome"] != "ok" {
+		t.Errorf("successful call record = %v", ok)
+	}
+	if _, found := ok["error_class"]; found {
+		t.Errorf("successful call record has error_class: %v", ok)
+	}
+	if _, found := ok["duration_ms"]; !found {
+		t.Errorf("successful call record has no duration_ms: %v", ok)
+	}
+	if failed["level"] != "ERROR" || failed["outcome"] != "error" || failed["error_class"] != "captions_unavailable" {
+		t.Errorf("failed call record = %v", failed)
+	}
+	if ok["request_id"] == "" || ok["request_id"] == failed["request_id"] {
+		t.Errorf("request IDs = %v and %v, want distinct IDs", ok["request_id"], failed["request_id"])
+	}
+	if invalid["error_class"] != "invalid_request" || invalid["tool"] != "summarize_youtube_playlist" {
+		t.Errorf("invalid call record = %v", invalid)
+	}
+}
+
+func TestMCPHandlerLogsCarryToolCallRequestID(t *testing.T) {
+	log := captureMCPLog(t)
+	server := NewMCPServer(&applicationStub{transcript: &tldw.Transcript{Text: "hello"}})
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	for range 2 {
+		if _, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "get_youtube_transcript",
+			Arguments: map[string]any{"url": "https://youtu.be/dQw4w9WgXcQ"},
+		}); err != nil {
+			t.Fatalf("CallTool() error = %v", err)
+		}
+	}
+
+	calls := toolCallRecords(t, log)
+	if len(calls) != 2 {
+		t.Fatalf("tool call records = %d, want 2:\n%s", len(calls), log)
+	}
+	handlerRecords := map[any]int{}
+	for _, record := range logRecords(t, log) {
+		if record["msg"] == "tool call" || record["tool"] == nil {
+			continue
+		}
+		if record["tool"] != "get_youtube_transcript" || record["session_id"] != calls[0]["session_id"] {
+			t.Errorf("handler record = %v, want the tool and session of the call", record)
+		}
+		handlerRecords[record["request_id"]]++
+	}
+	for _, call := range calls {
+		if handlerRecords[call["request_id"]] == 0 {
+			t.Errorf("no handler records with request_id %v:\n%s", call["request_id"], log)
+		}
+	}
+}
+
+func TestMCPLogsPlaylistID(t *testing.T) {
+	log := captureMCPLog(t)
+	server := NewMCPServer(&applicationStub{playlist: &tldw.PlaylistInfo{Title: "Course"}})
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	if _, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "get_youtube_playlist",
+		Arguments: map[string]any{"url": "PLSE8ODhjZXjYDBpQnSymaectKjxCy6BYq"},
+	}); err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+
+	records := toolCallRecords(t, log)
+	if len(records) != 1 || records[0]["playlist_id"] != "PLSE8ODhjZXjYDBpQnSymaectKjxCy6BYq" {
+		t.Fatalf("records = %v, want one with the playlist ID", records)
+	}
+}
+
+func TestRotatingFileKeepsMaxFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "mcp.log")
+	file, err := openRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("openRotatingFile() error = %v", err)
+	}
+
+	for _, record := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := file.Write([]byte(record)); err != nil {
+			t.Fatalf("Write(%q) error = %v", record, err)
+		}
+	}
+
+	for name, want := range map[string]string{
+		"mcp.log":   "fourth\n",
+		"mcp.log.1": "third\n",
+		"mcp.log.2": "second\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(filepath.Dir(path), name))
+		if err != nil {
+			t.Fatalf("reading %s: %v", name, err)
+		}
+		if string(data) != want {
+			t.Errorf("%s = %q, want %q", name, data, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("mcp.log.3 exists, want at most 2 rotated files")
+	}
+}
+
+func TestRotatingFileAppendsToExistingLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.log")
+	if err := os.WriteFile(path, []byte("old record\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	file, err := openRotatingFile(path, 16, 1)
+	if err != nil {
+		t.Fatalf("openRotatingFile() error = %v", err)
+	}
+
+	if _, err := file.Write([]byte("new record\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	current, _ := os.ReadFile(path)
+	rotated, _ := os.ReadFile(path + ".1")
+	if string(current) != "new record\n" || string(rotated) != "old record\n" {
+		t.Fatalf("mcp.log = %q, mcp.log.1 = %q, want the existing size counted toward rotation", current, rotated)
+	}
+}
+
+func TestRotatingFileKeepsLoggingWhenRotationFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.log")
+	file, err := openRotatingFile(path, 10, 1)
+	if err != nil {
+		t.Fatalf("openRotatingFile() error = %v", err)
+	}
+	renameFile = func(string, string) error { return os.ErrPermission }
+	t.Cleanup(func() { renameFile = os.Rename })
+
+	for _, record := range []string{"first\n", "second\n", "third\n"} {
+		if _, err := file.Write([]byte(record)); err != nil {
+			t.Fatalf("Write(%q) error = %v", record, err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading log: %v", err)
+	}
+	text := string(data)
+	for _, want := range []string{"first\n", "second\n", "third\n", `"msg":"log rotation failed"`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("log is missing %q:\n%s", want, text)
+		}
+	}
+
+	renameFile = os.Rename
+	if _, err := file.Write([]byte("after the failure, rotation works again\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if current, _ := os.ReadFile(path); string(current) != "after the failure, rotation works again\n" {
+		t.Fatalf("mcp.log = %q, want a rotated log", current)
+	}
+}
+
+func TestMCPLogsPromptAndResourceRequests(t *testing.T) {
+	log := captureMCPLog(t)
+	server := newPromptTestServer(t, &applicationStub{
+		metadata:   &tldw.VideoMetadata{Title: "B-trees"},
+		transcript: &tldw.Transcript{Source: tldw.TranscriptSourceCaptions, Text: "pages split"},
+	})
+	library := &libraryStub{transcripts: map[string]*tldw.Transcript{
+		"dQw4w9WgXcQ": {VideoID: "dQw4w9WgXcQ", Source: tldw.TranscriptSourceCaptions, Text: "pages split"},
+	}}
+	if err := server.ServeLibrary(library); err != nil {
+		t.Fatalf("ServeLibrary() error = %v", err)
+	}
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	if _, err := clientSession.GetPrompt(ctx, &mcp.GetPromptParams{
+		Name:      "tldw_summary",
+		Arguments: map[string]string{"url": "https://youtu.be/dQw4w9WgXcQ"},
+	}); err != nil {
+		t.Fatalf("GetPrompt() error = %v", err)
+	}
+	if _, err := clientSession.ReadResource(ctx, &mcp.ReadResourceParams{URI: "tldw://video/dQw4w9WgXcQ/transcript"}); err != nil {
+		t.Fatalf("ReadResource() error = %v", err)
+	}
+
+	requests := map[string]map[string]any{}
+	for _, record := range logRecords(t, log) {
+		switch {
+		case record["msg"] == "prompt rendered":
+			requests["prompt"] = record
+		case record["msg"] == "reading resource":
+			requests["resource"] = record
+		}
+	}
+	prompt, resource := requests["prompt"], requests["resource"]
+	if prompt == nil || resource == nil {
+		t.Fatalf("no prompt or resource records:\n%s", log)
+	}
+	if prompt["prompt"] != "tldw_summary" || prompt["video_id"] != "dQw4w9WgXcQ" {
+		t.Errorf("prompt record = %v", prompt)
+	}
+	if resource["uri"] != "tldw://video/dQw4w9WgXcQ/transcript" || resource["video_id"] != "dQw4w9WgXcQ" {
+		t.Errorf("resource record = %v", resource)
+	}
+	if prompt["request_id"] == nil || prompt["request_id"] == resource["request_id"] {
+		t.Errorf("request IDs = %v and %v, want distinct IDs", prompt["request_id"], resource["request_id"])
+	}
+}
@@ -0,0 +1,79 @@
+package mcpserver
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+// mcpTranscriptPaging is shared by the transcript tools so long transcripts
+// can be read in pieces that fit the client's context.
+type mcpTranscriptPaging struct {
+	StartSeconds float64 `json:"start_seconds,omitempty" jsonschema:"Only return transcript lines starting at or after this many seconds. Needs caption timestamps."`
+	EndSeconds   float64 `json:"end_seconds,omitempty" jsonschema:"Only return transcript lines starting before this many seconds. Needs caption timestamps."`
+	MaxChars     int     `json:"max_chars,omitempty" jsonschema:"Maximum characters to return per call. Longer transcripts return next_cursor for the following page."`
+	Cursor       string  `json:"cursor,omitempty" jsonschema:"next_cursor from the previous call, to continue with the same arguments"`
+}
+
+// window resolves the paging arguments, including the position stored in the
+// cursor, for the transcript of videoID.
+func (p mcpTranscriptPaging) window(videoID string) (tldw.TranscriptWindow, error) {
+	window := tldw.TranscriptWindow{Start: p.StartSeconds, End: p.EndSeconds, MaxChars: p.MaxChars}
+	if p.Cursor == "" {
+		return window, nil
+	}
+	offset, err := decodeTranscriptCursor(p.Cursor, videoID)
+	if err != nil {
+		return tldw.TranscriptWindow{}, err
+	}
+	window.Offset = offset
+	return window, nil
+}
+
+// Cursors name the video they belong to, so a cursor cannot silently page
+// through a different transcript.
+func encodeTranscriptCursor(videoID string, offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(videoID + ":" + strconv.Itoa(offset)))
+}
+
+func decodeTranscriptCursor(cursor, videoID string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, invalidInputError{fmt.Errorf("invalid cursor")}
+	}
+	id, position, ok := strings.Cut(string(data), ":")
+	offset, err := strconv.Atoi(position)
+	if !ok || err != nil || offset <= 0 {
+		return 0, invalidInputError{fmt.Errorf("invalid cursor")}
+	}
+	if id != videoID {
+		return 0, invalidInputError{fmt.Errorf("cursor belongs to another video")}
+	}
+	return offset, nil
+}
+
+// pageTranscript renders the requested page into the tool output and returns
+// the text for the client, which ends with a hint when more pages follow.
+func pageTranscript(transcript *tldw.Transcript, format tldw.TranscriptRenderFormat, window tldw.TranscriptWindow, videoID string, output *mcpTranscriptOutput) (string, error) {
+	page, err := transcript.Page(format, window)
+	if err != nil {
+		return "", err
+	}
+
+	output.Transcript = page.Text
+	output.TotalChars = page.TotalChars
+	output.StartSeconds = page.Start
+	output.EndSeconds = page.End
+	text := page.Text
+	if page.Next > 0 {
+		output.NextCursor = encodeTranscriptCursor(videoID, page.Next)
+		text += fmt.Sprintf("\n\n[Transcript continues (%d characters in total). Call again with cursor %q for the next part.]", page.TotalChars, output.NextCursor)
+	}
+	if text == "" {
+		text = "No transcript lines in the requested range."
+	}
+	return text, nil
+}
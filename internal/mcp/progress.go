@@ -0,0 +1,34 @@
+package mcpserver
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+// withProgress forwards engine progress as notifications/progress when the
+// client asked for it with a progress token. Progress counts reported steps,
+// since the number of steps depends on captions, Whisper, and chunking.
+func withProgress(ctx context.Context, request *mcp.CallToolRequest) context.Context {
+	if request == nil || request.Session == nil || request.Params == nil {
+		return ctx
+	}
+	token := request.Params.GetProgressToken()
+	if token == nil {
+		return ctx
+	}
+	session := request.Session
+	var step float64
+	return tldw.WithProgress(ctx, func(progress tldw.Progress) {
+		step++
+		err := session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      step,
+			Message:       progress.Message,
+		})
+		if err != nil {
+			requestLogger(ctx).Error("progress notification failed", "progress_token", token, "error", err)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rtzll/tldw/internal"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+// MCPPrompts renders the prompt templates advertised as MCP prompts.
+type MCPPrompts interface {
+	CreatePrompt(transcript string, metadata *tldw.VideoMetadata) (string, error)
+	CreateCustomPrompt(template, transcript string, metadata *tldw.VideoMetadata) (string, error)
+	Templates() ([]internal.PromptTemplate, error)
+}
+
+const (
+	mcpPromptPrefix       = "tldw_"
+	mcpSummaryPromptName  = mcpPromptPrefix + "summary"
+	mcpSummaryDescription = "Summarize a YouTube video with the configured TL;DW prompt. The transcript and metadata are filled in from existing captions, so your own model writes the summary."
+)
+
+type promptRenderer func(transcript string, metadata *tldw.VideoMetadata) (string, error)
+
+// ServePrompts advertises the configured summary prompt as tldw_summary and
+// each template in the prompts directory as tldw_<name>. Every prompt takes a
+// video URL and is rendered with the video's captions and metadata.
+// Templates that fail to load are logged and left out.
+func (s *MCPServer) ServePrompts(prompts MCPPrompts) error {
+	templates, err := prompts.Templates()
+	if err != nil {
+		return err
+	}
+
+	s.mcpServer.AddPrompt(&mcp.Prompt{
+		Name:        mcpSummaryPromptName,
+		Title:       "TL;DW summary",
+		Description: mcpSummaryDescription,
+		Arguments:   mcpPromptArguments(),
+	}, s.handlePrompt(mcpSummaryPromptName, prompts.CreatePrompt))
+
+	served := 1
+	for _, template := range templates {
+		name := mcpPromptPrefix + template.Name
+		if template.Err != nil {
+			serverLogger().Error("prompt template skipped", "prompt", template.Name, "error", template.Err)
+			continue
+		}
+		if name == mcpSummaryPromptName {
+			serverLogger().Warn("prompt template shadowed by the configured summary prompt", "prompt", template.Name)
+			continue
+		}
+		render := func(transcript string, metadata *tldw.VideoMetadata) (string, error) {
+			return prompts.CreateCustomPrompt(template.Template, transcript, metadata)
+		}
+		s.mcpServer.AddPrompt(&mcp.Prompt{
+			Name:        name,
+			Title:       "TL;DW " + template.Name,
+			Description: fmt.Sprintf("Render the %q prompt template with a YouTube video's transcript and metadata from existing captions.", template.Name),
+			Arguments:   mcpPromptArguments(),
+		}, s.handlePrompt(name, render))
+		served++
+	}
+	serverLogger().Info("serving prompts", "prompts", served)
+	return nil
+}
+
+func mcpPromptArguments() []*mcp.PromptArgument {
+	return []*mcp.PromptArgument{{
+		Name:        "url",
+		Description: "YouTube video URL",
+		Required:    true,
+	}}
+}
+
+// handlePrompt fetches free captions only; prompts never start paid Whisper
+// transcription.
+func (s *MCPServer) handlePrompt(name string, render promptRenderer) mcp.PromptHandler {
+	return func(ctx context.Context, request *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		logger := requestLogger(ctx)
+		url := request.Params.Arguments["url"]
+		parsed, err := tldw.ParseVideoRef(url)
+		if err != nil {
+			logger.Error("prompt failed", "error_class", errorClassInvalidRequest, "error", err)
+			return nil, invalidVideoURL(url, err)
+		}
+
+		transcript, err := s.engine.Transcript(ctx, parsed, tldw.TranscriptRequest{Policy: tldw.TranscriptPolicyCaptionsOnly})
+		if err != nil {
+			logger.Error("prompt failed", "error_class", errorClass(err), "error", err)
+			if errors.Is(err, tldw.ErrCaptionsUnavailable) {
+				return nil, fmt.Errorf("no captions available - prompts only use free captions; consider transcribe_youtube_whisper (paid): %w", err)
+			}
+			return nil, fmt.Errorf("getting transcript: %w", err)
+		}
+		plain, err := transcript.Render(tldw.TranscriptRenderFormatPlain)
+		if err != nil {
+			return nil, err
+		}
+		metadata, err := s.engine.MetadataFor(ctx, parsed)
+		if err != nil {
+			logger.Warn("metadata unavailable, rendering the prompt without it", "error", err)
+			metadata = nil
+		}
+		text, err := render(plain, metadata)
+		if err != nil {
+			logger.Error("prompt failed", "error_class", errorClassInternal, "error", err)
+			return nil, err
+		}
+
+		logger.Info("prompt rendered", "characters", len(text))
+
+		subject := parsed.URL()
+		if metadata != nil && metadata.Title != "" {
+			subject = metadata.Title
+		}
+		return &mcp.GetPromptResult{
+			Description: fmt.Sprintf("%s for %s", name, subject),
+			Messages: []*mcp.PromptMessage{{
+				Role:    "user",
+				Content: &mcp.TextContent{Text: text},
+			}},
+		}, nil
+	}
+}
@@ -0,0 +1,156 @@
+package mcpserver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rtzll/tldw/internal"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+type promptsStub struct {
+	templates []internal.PromptTemplate
+}
+
+func (promptsStub) CreatePrompt(transcript string, metadata *tldw.VideoMetadata) (string, error) {
+	return fmt.Sprintf("Summarize %s: %s", metadata.Title, transcript), nil
+}
+
+func (promptsStub) CreateCustomPrompt(template, transcript string, metadata *tldw.VideoMetadata) (string, error) {
+	return fmt.Sprintf("%s %s: %s", template, metadata.Title, transcript), nil
+}
+
+func (stub promptsStub) Templates() ([]internal.PromptTemplate, error) {
+	return stub.templates, nil
+}
+
+func newPromptTestServer(t *testing.T, app *applicationStub) *MCPServer {
+	t.Helper()
+	server := NewMCPServer(app)
+	err := server.ServePrompts(promptsStub{templates: []internal.PromptTemplate{
+		{Name: "bullets", Template: "Bullets for"},
+		{Name: "summary", Template: "Shadowed"},
+	}})
+	if err != nil {
+		t.Fatalf("ServePrompts() error = %v", err)
+	}
+	return server
+}
+
+func TestMCPPromptsListConfiguredAndDirectoryTemplates(t *testing.T) {
+	server := newPromptTestServer(t, &applicationStub{})
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	res, err := clientSession.ListPrompts(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListPrompts() error = %v", err)
+	}
+	names := make(map[string]*mcp.Prompt)
+	for _, prompt := range res.Prompts {
+		names[prompt.Name] = prompt
+	}
+	if len(names) != 2 || names["tldw_summary"] == nil || names["tldw_bullets"] == nil {
+		t.Fatalf("prompts = %v, want tldw_summary and tldw_bullets", names)
+	}
+	for name, prompt := range names {
+		if len(prompt.Arguments) != 1 || prompt.Arguments[0].Name != "url" || !prompt.Arguments[0].Required {
+			t.Errorf("%s arguments = %+v, want one required url", name, prompt.Arguments)
+		}
+	}
+}
+
+func TestMCPPromptRendersTranscriptAndMetadata(t *testing.T) {
+	app := &applicationStub{
+		metadata:   &tldw.VideoMetadata{Title: "B-trees"},
+		transcript: &tldw.Transcript{Source: tldw.TranscriptSourceCaptions, Text: "pages split"},
+	}
+	server := newPromptTestServer(t, app)
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	for name, want := range map[string]string{
+		"tldw_summary": "Summarize B-trees: pages split",
+		"tldw_bullets": "Bullets for B-trees: pages split",
+	} {
+		result, err := clientSession.GetPrompt(ctx, &mcp.GetPromptParams{
+			Name:      name,
+			Arguments: map[string]string{"url": "https://youtu.be/dQw4w9WgXcQ"},
+		})
+		if err != nil {
+			t.Fatalf("GetPrompt(%s) error = %v", name, err)
+		}
+		if len(result.Messages) != 1 || result.Messages[0].Role != "user" {
+			t.Fatalf("GetPrompt(%s) messages = %+v, want one user message", name, result.Messages)
+		}
+		text, ok := result.Messages[0].Content.(*mcp.TextContent)
+		if !ok || text.Text != want {
+			t.Fatalf("GetPrompt(%s) content = %+v, want %q", name, result.Messages[0].Content, want)
+		}
+	}
+	if app.lastRequest.Policy != tldw.TranscriptPolicyCaptionsOnly {
+		t.Fatalf("Transcript() policy = %v, want captions only", app.lastRequest.Policy)
+	}
+}
+
+func TestMCPPromptReportsMissingCaptions(t *testing.T) {
+	app := &applicationStub{transcriptErr: fmt.Errorf("%w for dQw4w9WgXcQ", tldw.ErrCaptionsUnavailable)}
+	server := newPromptTestServer(t, app)
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	_, err := clientSession.GetPrompt(ctx, &mcp.GetPromptParams{
+		Name:      "tldw_summary",
+		Arguments: map[string]string{"url": "https://youtu.be/dQw4w9WgXcQ"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "no captions available") {
+		t.Fatalf("GetPrompt() error = %v, want missing captions", err)
+	}
+}
+
+func TestMCPPromptsSkipBrokenTemplate(t *testing.T) {
+	log := captureMCPLog(t)
+	configDir := t.TempDir()
+	dir := filepath.Join(configDir, internal.PromptsDirName)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("creating prompts directory: %v", err)
+	}
+	for name, content := range map[string]string{
+		"bullets.txt": "Bullets: {{.Transcript}}",
+		"broken.txt":  "{{.Transcript",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+	server := NewMCPServer(&applicationStub{})
+	if err := server.ServePrompts(internal.NewPromptManager(configDir, "Summarize {{.Transcript}}")); err != nil {
+		t.Fatalf("ServePrompts() error = %v", err)
+	}
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	res, err := clientSession.ListPrompts(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListPrompts() error = %v", err)
+	}
+	var names []string
+	for _, prompt := range res.Prompts {
+		names = append(names, prompt.Name)
+	}
+	slices.Sort(names)
+	if want := []string{"tldw_bullets", "tldw_summary"}; !slices.Equal(names, want) {
+		t.Fatalf("prompts = %v, want %v", names, want)
+	}
+
+	var skipped map[string]any
+	for _, record := range logRecords(t, log) {
+		if record["msg"] == "prompt template skipped" {
+			skipped = record
+		}
+	}
+	if skipped == nil || skipped["prompt"] != "broken" || !strings.Contains(fmt.Sprint(skipped["error"]), "parsing prompt template broken") {
+		t.Fatalf("skipped template record = %v, want broken with its parse error:\n%s", skipped, log)
+	}
+}
@@ -0,0 +1,194 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+// MCPLibrary is the read side of the transcript store, served as resources.
+type MCPLibrary interface {
+	LoadTranscript(videoID string) (*tldw.Transcript, error)
+	LoadMetadata(videoID string) (*tldw.VideoMetadata, error)
+	ListEntries() ([]tldw.LibraryEntry, error)
+}
+
+const (
+	mcpLibraryURI            = "tldw://library"
+	mcpVideoURIPrefix        = "tldw://video/"
+	mcpTranscriptURITemplate = mcpVideoURIPrefix + "{id}/transcript"
+	mcpMetadataURITemplate   = mcpVideoURIPrefix + "{id}/metadata"
+
+	mcpResourceKindTranscript = "transcript"
+	mcpResourceKindMetadata   = "metadata"
+)
+
+// ServeLibrary exposes the library as MCP resources: the library listing,
+// templates for any video's transcript and metadata, and one listed resource
+// per cached transcript so clients can attach them without tool calls.
+func (s *MCPServer) ServeLibrary(library MCPLibrary) error {
+	entries, err := library.ListEntries()
+	if err != nil {
+		return fmt.Errorf("listing cached videos: %w", err)
+	}
+
+	s.resourceMu.Lock()
+	s.library = library
+	s.listedTranscripts = make(map[string]bool)
+	s.resourceMu.Unlock()
+
+	s.mcpServer.AddResource(&mcp.Resource{
+		URI:         mcpLibraryURI,
+		Name:        "library",
+		Title:       "TL;DW library",
+		Description: "Cached videos with their metadata and which transcripts and summaries are stored.",
+		MIMEType:    "application/json",
+	}, s.readLibraryResource)
+	s.mcpServer.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: mcpTranscriptURITemplate,
+		Name:        "transcript",
+		Title:       "Cached video transcript",
+		Description: "Plain text transcript of a video that has already been fetched. Does not fetch missing transcripts.",
+		MIMEType:    "text/plain",
+	}, s.readVideoResource)
+	s.mcpServer.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: mcpMetadataURITemplate,
+		Name:        "metadata",
+		Title:       "Cached video metadata",
+		Description: "Metadata of a video that has already been fetched. Does not fetch missing metadata.",
+		MIMEType:    "application/json",
+	}, s.readVideoResource)
+
+	transcripts := 0
+	for _, entry := range entries {
+		if entry.HasTranscript {
+			s.listTranscript(entry.VideoID, entry.Metadata)
+			transcripts++
+		}
+	}
+	serverLogger().Info("serving library resources", "transcripts", transcripts)
+	return nil
+}
+
+// TranscriptSaved lists a newly cached transcript as a resource, which
+// notifies connected clients that the resource list changed. It does nothing
+// until ServeLibrary has been called.
+func (s *MCPServer) TranscriptSaved(videoID string) {
+	s.resourceMu.Lock()
+	library := s.library
+	s.resourceMu.Unlock()
+	if library == nil {
+		return
+	}
+	metadata, err := library.LoadMetadata(videoID)
+	if err != nil {
+		metadata = nil
+	}
+	s.listTranscript(videoID, metadata)
+}
+
+func (s *MCPServer) listTranscript(videoID string, metadata *tldw.VideoMetadata) {
+	s.resourceMu.Lock()
+	defer s.resourceMu.Unlock()
+	if s.listedTranscripts[videoID] {
+		return
+	}
+	s.listedTranscripts[videoID] = true
+
+	title := videoID
+	if metadata != nil && metadata.Title != "" {
+		title = metadata.Title
+	}
+	s.mcpServer.AddResource(&mcp.Resource{
+		URI:      videoResourceURI(videoID, mcpResourceKindTranscript),
+		Name:     videoID + " transcript",
+		Title:    "Transcript: " + title,
+		MIMEType: "text/plain",
+	}, s.readVideoResource)
+}
+
+func videoResourceURI(videoID, kind string) string {
+	return mcpVideoURIPrefix + videoID + "/" + kind
+}
+
+// parseVideoResourceURI splits tldw://video/{id}/{kind} into its parts.
+func parseVideoResourceURI(uri string) (videoID, kind string, ok bool) {
+	rest, found := strings.CutPrefix(uri, mcpVideoURIPrefix)
+	if !found {
+		return "", "", false
+	}
+	videoID, kind, found = strings.Cut(rest, "/")
+	if !found || !tldw.IsValidVideoID(videoID) {
+		return "", "", false
+	}
+	return videoID, kind, true
+}
+
+func (s *MCPServer) readVideoResource(ctx context.Context, request *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := request.Params.URI
+	videoID, kind, ok := parseVideoResourceURI(uri)
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	requestLogger(ctx).Info("reading resource")
+
+	switch kind {
+	case mcpResourceKindTranscript:
+		transcript, err := s.library.LoadTranscript(videoID)
+		if err != nil {
+			return nil, resourceError(ctx, uri, err)
+		}
+		text, err := transcript.Render(tldw.TranscriptRenderFormatPlain)
+		if err != nil {
+			return nil, err
+		}
+		return mcpResourceResult(uri, "text/plain", text), nil
+	case mcpResourceKindMetadata:
+		metadata, err := s.library.LoadMetadata(videoID)
+		if err != nil {
+			return nil, resourceError(ctx, uri, err)
+		}
+		return mcpJSONResourceResult(uri, metadata)
+	default:
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+}
+
+func (s *MCPServer) readLibraryResource(ctx context.Context, request *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	requestLogger(ctx).Info("reading resource")
+	entries, err := s.library.ListEntries()
+	if err != nil {
+		return nil, fmt.Errorf("listing cached videos: %w", err)
+	}
+	if entries == nil {
+		entries = []tldw.LibraryEntry{}
+	}
+	return mcpJSONResourceResult(request.Params.URI, entries)
+}
+
+func resourceError(ctx context.Context, uri string, err error) error {
+	if errors.Is(err, tldw.ErrStoreNotFound) || errors.Is(err, tldw.ErrStoreStale) {
+		return mcp.ResourceNotFoundError(uri)
+	}
+	requestLogger(ctx).Error("reading resource failed", "error", err)
+	return err
+}
+
+func mcpJSONResourceResult(uri string, value any) (*mcp.ReadResourceResult, error) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s: %w", uri, err)
+	}
+	return mcpResourceResult(uri, "application/json", string(data)), nil
+}
+
+func mcpResourceResult(uri, mimeType, text string) *mcp.ReadResourceResult {
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{URI: uri, MIMEType: mimeType, Text: text}},
+	}
+}
@@ -0,0 +1,162 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+type libraryStub struct {
+	transcripts map[string]*tldw.Transcript
+	metadata    map[string]*tldw.VideoMetadata
+}
+
+func (stub *libraryStub) LoadTranscript(videoID string) (*tldw.Transcript, error) {
+	if transcript, ok := stub.transcripts[videoID]; ok {
+		return transcript, nil
+	}
+	return nil, tldw.ErrStoreNotFound
+}
+
+func (stub *libraryStub) LoadMetadata(videoID string) (*tldw.VideoMetadata, error) {
+	if metadata, ok := stub.metadata[videoID]; ok {
+		return metadata, nil
+	}
+	return nil, tldw.ErrStoreNotFound
+}
+
+func (stub *libraryStub) ListEntries() ([]tldw.LibraryEntry, error) {
+	var entries []tldw.LibraryEntry
+	for videoID := range stub.transcripts {
+		entries = append(entries, tldw.LibraryEntry{VideoID: videoID, Metadata: stub.metadata[videoID], HasTranscript: true})
+	}
+	return entries, nil
+}
+
+func newLibraryTestServer(t *testing.T) (*MCPServer, *libraryStub) {
+	t.Helper()
+	library := &libraryStub{
+		transcripts: map[string]*tldw.Transcript{
+			"dQw4w9WgXcQ": {VideoID: "dQw4w9WgXcQ", Source: tldw.TranscriptSourceCaptions, Text: "never gonna give you up"},
+		},
+		metadata: map[string]*tldw.VideoMetadata{
+			"dQw4w9WgXcQ": {Title: "Rick Astley", Channel: "Rick Astley"},
+		},
+	}
+	server := NewMCPServer(&applicationStub{})
+	if err := server.ServeLibrary(library); err != nil {
+		t.Fatalf("ServeLibrary() error = %v", err)
+	}
+	return server, library
+}
+
+func TestMCPResourcesListCachedTranscriptsAndTemplates(t *testing.T) {
+	server, _ := newLibraryTestServer(t)
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	resources, err := clientSession.ListResources(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListResources() error = %v", err)
+	}
+	var uris []string
+	for _, resource := range resources.Resources {
+		uris = append(uris, resource.URI)
+	}
+	slices.Sort(uris)
+	if want := []string{"tldw://library", "tldw://video/dQw4w9WgXcQ/transcript"}; !slices.Equal(uris, want) {
+		t.Fatalf("resource URIs = %v, want %v", uris, want)
+	}
+
+	templates, err := clientSession.ListResourceTemplates(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListResourceTemplates() error = %v", err)
+	}
+	var patterns []string
+	for _, template := range templates.ResourceTemplates {
+		patterns = append(patterns, template.URITemplate)
+	}
+	slices.Sort(patterns)
+	if want := []string{"tldw://video/{id}/metadata", "tldw://video/{id}/transcript"}; !slices.Equal(patterns, want) {
+		t.Fatalf("resource templates = %v, want %v", patterns, want)
+	}
+}
+
+func TestMCPResourcesReadTranscriptMetadataAndLibrary(t *testing.T) {
+	server, _ := newLibraryTestServer(t)
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	read := func(uri string) *mcp.ResourceContents {
+		t.Helper()
+		result, err := clientSession.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+		if err != nil {
+			t.Fatalf("ReadResource(%s) error = %v", uri, err)
+		}
+		if len(result.Contents) != 1 {
+			t.Fatalf("ReadResource(%s) returned %d contents, want 1", uri, len(result.Contents))
+		}
+		return result.Contents[0]
+	}
+
+	if got := read("tldw://video/dQw4w9WgXcQ/transcript"); got.Text != "never gonna give you up" || got.MIMEType != "text/plain" {
+		t.Fatalf("transcript resource = %+v", got)
+	}
+
+	var metadata tldw.VideoMetadata
+	if err := json.Unmarshal([]byte(read("tldw://video/dQw4w9WgXcQ/metadata").Text), &metadata); err != nil {
+		t.Fatalf("metadata resource is not JSON: %v", err)
+	}
+	if metadata.Title != "Rick Astley" {
+		t.Fatalf("metadata resource title = %q", metadata.Title)
+	}
+
+	var entries []tldw.LibraryEntry
+	if err := json.Unmarshal([]byte(read("tldw://library").Text), &entries); err != nil {
+		t.Fatalf("library resource is not JSON: %v", err)
+	}
+	if len(entries) != 1 || entries[0].VideoID != "dQw4w9WgXcQ" {
+		t.Fatalf("library resource entries = %+v", entries)
+	}
+
+	for _, uri := range []string{"tldw://video/tAP1eZYEuKA/transcript", "tldw://video/../metadata"} {
+		if _, err := clientSession.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri}); err == nil {
+			t.Fatalf("ReadResource(%s) succeeded, want not found", uri)
+		}
+	}
+}
+
+func TestMCPResourcesNotifyWhenTranscriptIsSaved(t *testing.T) {
+	server, library := newLibraryTestServer(t)
+	changed := make(chan struct{}, 1)
+	ctx, clientSession := connectTestMCPClientWithOptions(t, server, &mcp.ClientOptions{
+		ResourceListChangedHandler: func(context.Context, *mcp.ResourceListChangedRequest) {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		},
+	})
+
+	library.transcripts["tAP1eZYEuKA"] = &tldw.Transcript{VideoID: "tAP1eZYEuKA", Text: "new"}
+	server.TranscriptSaved("tAP1eZYEuKA")
+
+	select {
+	case <-changed:
+	case <-time.After(3 * time.Second):
+		t.Fatal("no resource list changed notification after a transcript was saved")
+	}
+	resources, err := clientSession.ListResources(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListResources() error = %v", err)
+	}
+	if !slices.ContainsFunc(resources.Resources, func(resource *mcp.Resource) bool {
+		return strings.HasPrefix(resource.URI, "tldw://video/tAP1eZYEuKA/")
+	}) {
+		t.Fatal("saved transcript is not listed as a resource")
+	}
+}
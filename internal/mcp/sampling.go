@@ -0,0 +1,142 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+const (
+	// samplingModel is reported as the summary model until the client names
+	// the model that wrote the summary.
+	samplingModel     = "client model"
+	samplingRequestID = "summary"
+	samplingMaxTokens = 4096
+)
+
+// SamplingAI is an AIAdapter for servers without an OpenAI API key. Summaries
+// requested by a tool call are written by the calling client's own model
+// through MCP sampling, when the client supports it; everything else,
+// including Whisper transcription, goes to the wrapped adapter.
+type SamplingAI struct {
+	tldw.AIAdapter
+}
+
+// NewSamplingAI wraps fallback, which handles transcription and summaries for
+// clients without sampling.
+func NewSamplingAI(fallback tldw.AIAdapter) *SamplingAI {
+	return &SamplingAI{AIAdapter: fallback}
+}
+
+// samplingRequiredError stops a summary until the client has answered the
+// sampling request. The tool handler returns the request to the client, and
+// the SDK calls the tool again with the answer in its input responses.
+type samplingRequiredError struct {
+	params *mcp.CreateMessageParams
+}
+
+func (e *samplingRequiredError) Error() string {
+	return "waiting for the client to write the summary"
+}
+
+type samplingKey struct{}
+
+// withSampling lets summaries made during this tool call use the client's
+// model, if the client supports sampling.
+func withSampling(ctx context.Context, request *mcp.CallToolRequest) context.Context {
+	if request == nil || request.Session == nil || request.Params == nil {
+		return ctx
+	}
+	params := request.Session.InitializeParams()
+	if params == nil || params.Capabilities == nil || params.Capabilities.Sampling == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, samplingKey{}, request)
+}
+
+// Summary returns the client's answer to the sampling request for prompt, or
+// asks for one with a samplingRequiredError. Outside a tool call from a
+// client with sampling it uses the wrapped adapter.
+func (ai *SamplingAI) Summary(ctx context.Context, model, prompt string) (string, error) {
+	request, ok := ctx.Value(samplingKey{}).(*mcp.CallToolRequest)
+	if !ok {
+		if model == samplingModel {
+			model = ""
+		}
+		return ai.AIAdapter.Summary(ctx, model, prompt)
+	}
+	if response, ok := request.Params.InputResponses[samplingRequestID]; ok {
+		text, _, err := sampledSummary(response)
+		return text, err
+	}
+	return "", &samplingRequiredError{params: &mcp.CreateMessageParams{
+		Messages: []*mcp.SamplingMessage{{
+			Role:    "user",
+			Content: &mcp.TextContent{Text: prompt},
+		}},
+		SystemPrompt:   "You summarize YouTube videos for TL;DW. Follow the instructions in the message and answer in Markdown.",
+		IncludeContext: "none",
+		MaxTokens:      samplingMaxTokens,
+	}}
+}
+
+// SummaryModel names the client's model, since the wrapped adapter has no
+// key to summarize with.
+func (ai *SamplingAI) SummaryModel() string {
+	return samplingModel
+}
+
+// sampledSummary extracts the summary text and model from a sampling answer.
+func sampledSummary(response mcp.InputResponse) (string, string, error) {
+	var content []mcp.Content
+	var model string
+	switch result := response.(type) {
+	case *mcp.CreateMessageWithToolsResult:
+		content, model = result.Content, result.Model
+	case *mcp.CreateMessageResult:
+		content, model = []mcp.Content{result.Content}, result.Model
+	default:
+		return "", "", fmt.Errorf("unexpected answer to the summary request: %T", response)
+	}
+	var sb strings.Builder
+	for _, block := range content {
+		if text, ok := block.(*mcp.TextContent); ok {
+			sb.WriteString(text.Text)
+		}
+	}
+	if strings.TrimSpace(sb.String()) == "" {
+		return "", "", fmt.Errorf("the client returned an empty summary")
+	}
+	return sb.String(), model, nil
+}
+
+// samplingResult turns a summary stopped by samplingRequiredError into the
+// input request for the client. It returns nil for other errors.
+func samplingResult(err error) *mcp.CallToolResult {
+	var required *samplingRequiredError
+	if !errors.As(err, &required) {
+		return nil
+	}
+	return &mcp.CallToolResult{InputRequests: mcp.InputRequestMap{samplingRequestID: required.params}}
+}
+
+// sampledModel names the client model that answered this call's sampling
+// request, if there was one.
+func sampledModel(request *mcp.CallToolRequest) (string, bool) {
+	if request == nil || request.Params == nil {
+		return "", false
+	}
+	response, ok := request.Params.InputResponses[samplingRequestID]
+	if !ok {
+		return "", false
+	}
+	_, model, err := sampledSummary(response)
+	if err != nil || model == "" {
+		return samplingModel, true
+	}
+	return model, true
+}
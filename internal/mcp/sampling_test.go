@@ -0,0 +1,98 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type fallbackAI struct {
+	model   string
+	summary string
+	err     error
+}
+
+func (ai *fallbackAI) Transcribe(context.Context, string) (string, error) {
+	return "", errors.New("not used")
+}
+
+func (ai *fallbackAI) Summary(_ context.Context, model, _ string) (string, error) {
+	ai.model = model
+	return ai.summary, ai.err
+}
+
+func (ai *fallbackAI) SummaryModel() string { return "gpt-4o-mini" }
+
+func TestMCPSummarizeSamplesClientModelWithoutAPIKey(t *testing.T) {
+	fallback := &fallbackAI{err: errors.New("OpenAI API key is required")}
+	app := &applicationStub{ai: NewSamplingAI(fallback)}
+	var prompts []string
+	ctx, clientSession := connectTestMCPClientWithOptions(t, NewMCPServer(app), &mcp.ClientOptions{
+		CreateMessageHandler: func(_ context.Context, request *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+			prompts = append(prompts, request.Params.Messages[0].Content.(*mcp.TextContent).Text)
+			return &mcp.CreateMessageResult{
+				Role:    "assistant",
+				Model:   "claude-test",
+				Content: &mcp.TextContent{Text: "## Client summary"},
+			}, nil
+		},
+	})
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "summarize_youtube_video",
+		Arguments: map[string]any{"url": "https://youtu.be/dQw4w9WgXcQ", "prompt": "{{.Transcript}}"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("CallTool() returned tool error: %s", textContent(t, result))
+	}
+	if len(prompts) != 1 || prompts[0] != "Summarize: {{.Transcript}}" {
+		t.Fatalf("sampled prompts = %q, want the rendered summary prompt once", prompts)
+	}
+	output := structuredContent[mcpSummaryOutput](t, result)
+	if output.Summary != "## Client summary" || output.Model != "claude-test" {
+		t.Fatalf("output = %+v, want the client's summary and model", output)
+	}
+	if app.summaryCalls != 2 {
+		t.Fatalf("SummarizeVideo() calls = %d, want a retry with the sampled answer", app.summaryCalls)
+	}
+	if fallback.model != "" {
+		t.Fatalf("fallback adapter called with model %q", fallback.model)
+	}
+}
+
+func TestMCPSummarizeUsesFallbackWithoutSampling(t *testing.T) {
+	fallback := &fallbackAI{err: errors.New("OpenAI API key is required")}
+	app := &applicationStub{ai: NewSamplingAI(fallback)}
+	ctx, clientSession := connectTestMCPClient(t, NewMCPServer(app))
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "summarize_youtube_video",
+		Arguments: map[string]any{"url": "https://youtu.be/dQw4w9WgXcQ"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !result.IsError || !strings.Contains(textContent(t, result), "OpenAI API key is required") {
+		t.Fatalf("result = %+v, want the fallback adapter's error", result)
+	}
+}
+
+func TestSamplingAIOutsideToolCallsUsesConfiguredModel(t *testing.T) {
+	fallback := &fallbackAI{summary: "summary"}
+	ai := NewSamplingAI(fallback)
+	if _, err := ai.Summary(context.Background(), ai.SummaryModel(), "prompt"); err != nil {
+		t.Fatalf("Summary() error = %v", err)
+	}
+	if fallback.model != "" {
+		t.Fatalf("fallback model = %q, want the adapter's default", fallback.model)
+	}
+	if _, err := ai.Summary(context.Background(), "gpt-4o", "prompt"); err != nil || fallback.model != "gpt-4o" {
+		t.Fatalf("Summary(gpt-4o) = %v, fallback model %q", err, fallback.model)
+	}
+}
@@ -0,0 +1,249 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+const (
+	mcpSearchTranscriptsDescription = "Search the transcripts of videos the user has already watched with TL;DW (FREE, local library only). Returns matching videos with timestamped snippets, most matches first. Use from/to and channel to narrow the search, and get_youtube_transcript with start_seconds to read around a hit."
+	mcpGetWatchStatsDescription     = "Report how many videos the user has watched with TL;DW and their total runtime (FREE, local library only), for a period or a from/to range, optionally grouped by day, week, or month."
+
+	mcpDefaultSearchLimit    = 10
+	mcpDefaultSearchSnippets = 3
+)
+
+type mcpSearchTranscriptsInput struct {
+	Query   string `json:"query" jsonschema:"Words or phrase to find, matched case-insensitively"`
+	Channel string `json:"channel,omitempty" jsonschema:"Only search videos from channels whose name contains this text"`
+	From    string `json:"from,omitempty" jsonschema:"Only search videos first watched at or after this date (YYYY-MM-DD) or RFC 3339 time"`
+	To      string `json:"to,omitempty" jsonschema:"Only search videos first watched before this date (YYYY-MM-DD) or RFC 3339 time"`
+	Limit   int    `json:"limit,omitempty" jsonschema:"Maximum number of videos to return (default 10)"`
+}
+
+type mcpSearchHitOutput struct {
+	StartSeconds float64 `json:"start_seconds,omitempty" jsonschema:"Caption start time of the hit in seconds, when timestamps are available"`
+	Text         string  `json:"text" jsonschema:"Transcript text around the hit"`
+}
+
+type mcpSearchResultOutput struct {
+	VideoID   string               `json:"video_id" jsonschema:"YouTube video ID"`
+	URL       string               `json:"url" jsonschema:"YouTube video URL"`
+	Title     string               `json:"title,omitempty" jsonschema:"YouTube video title"`
+	Channel   string               `json:"channel,omitempty" jsonschema:"YouTube channel name"`
+	WatchedAt string               `json:"watched_at" jsonschema:"When the video was first fetched, in RFC 3339"`
+	Matches   int                  `json:"matches" jsonschema:"Number of hits in the transcript"`
+	Hits      []mcpSearchHitOutput `json:"hits" jsonschema:"The first hits in transcript order"`
+}
+
+type mcpSearchTranscriptsOutput struct {
+	Query   string                  `json:"query" jsonschema:"Searched text"`
+	Results []mcpSearchResultOutput `json:"results" jsonschema:"Matching videos, most matches first"`
+}
+
+type mcpWatchStatsInput struct {
+	Period  string `json:"period,omitempty" jsonschema:"Calendar period: today, week, month, or all (default all). Ignored when from or to is set."`
+	From    string `json:"from,omitempty" jsonschema:"Start of the range (inclusive) as a date (YYYY-MM-DD) or RFC 3339 time"`
+	To      string `json:"to,omitempty" jsonschema:"End of the range (exclusive) as a date (YYYY-MM-DD) or RFC 3339 time"`
+	GroupBy string `json:"group_by,omitempty" jsonschema:"Group results by day, week, or month"`
+}
+
+type mcpWatchStatsOutput struct {
+	Period          string             `json:"period" jsonschema:"Reported period"`
+	From            string             `json:"from,omitempty" jsonschema:"Start of the range in RFC 3339, absent when open"`
+	To              string             `json:"to,omitempty" jsonschema:"End of the range in RFC 3339, absent when open"`
+	VideoCount      int                `json:"video_count" jsonschema:"Number of unique videos"`
+	DurationSeconds float64            `json:"duration_seconds" jsonschema:"Total video runtime in seconds"`
+	Groups          []tldw.StatsBucket `json:"groups,omitempty" jsonschema:"Per-group counts when group_by is set"`
+}
+
+// mcpLibraryToolAnnotations marks tools that only read the local library.
+func mcpLibraryToolAnnotations() *mcp.ToolAnnotations {
+	annotations := mcpToolAnnotations(true)
+	openWorld := false
+	annotations.OpenWorldHint = &openWorld
+	return annotations
+}
+
+// parseMCPTime accepts a date in local time or an RFC 3339 time; empty
+// values leave the bound open.
+func parseMCPTime(value, name string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return parsed, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a date (YYYY-MM-DD) or RFC 3339 time, got %q", name, value)
+	}
+	return parsed, nil
+}
+
+// handleSearchTranscripts implements the search_transcripts tool
+func (s *MCPServer) handleSearchTranscripts(ctx context.Context, _ *mcp.CallToolRequest, input mcpSearchTranscriptsInput) (*mcp.CallToolResult, mcpSearchTranscriptsOutput, error) {
+	var zero mcpSearchTranscriptsOutput
+	from, err := parseMCPTime(input.From, "from")
+	if err != nil {
+		return nil, zero, err
+	}
+	to, err := parseMCPTime(input.To, "to")
+	if err != nil {
+		return nil, zero, err
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = mcpDefaultSearchLimit
+	}
+	logger := requestLogger(ctx)
+	logger.Info("started", "query", input.Query)
+
+	results, err := s.engine.SearchTranscripts(tldw.TranscriptSearchQuery{
+		Text: input.Query, Channel: input.Channel, From: from, To: to,
+		Limit: limit, SnippetsPerVideo: mcpDefaultSearchSnippets,
+	})
+	if err != nil {
+		logger.Error("failed", "error", err)
+		return nil, zero, fmt.Errorf("searching transcripts: %w", err)
+	}
+	logger.Info("succeeded", "videos", len(results))
+
+	output := mcpSearchTranscriptsOutput{
+		Query:   input.Query,
+		Results: make([]mcpSearchResultOutput, 0, len(results)),
+	}
+	var buf strings.Builder
+	if len(results) == 0 {
+		fmt.Fprintf(&buf, "No cached transcripts mention %q.\n", input.Query)
+	}
+	for i, result := range results {
+		url := result.VideoID
+		if ref, err := tldw.ParseVideoRef(result.VideoID); err == nil {
+			url = ref.URL()
+		}
+		item := mcpSearchResultOutput{
+			VideoID:   result.VideoID,
+			URL:       url,
+			Title:     result.Title,
+			Channel:   result.Channel,
+			WatchedAt: result.FirstSeenAt.Format(time.RFC3339),
+			Matches:   result.Matches,
+			Hits:      make([]mcpSearchHitOutput, 0, len(result.Snippets)),
+		}
+		title := result.Title
+		if title == "" {
+			title = result.VideoID
+		}
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "%d. %s", i+1, title)
+		if result.Channel != "" {
+			fmt.Fprintf(&buf, " — %s", result.Channel)
+		}
+		fmt.Fprintf(&buf, " (%s, watched %s, %d %s)\n", url, result.FirstSeenAt.Local().Format(time.DateOnly),
+			result.Matches, pluralMatches(result.Matches))
+		for _, snippet := range result.Snippets {
+			item.Hits = append(item.Hits, mcpSearchHitOutput{StartSeconds: snippet.Start, Text: snippet.Text})
+			if snippet.Timed {
+				fmt.Fprintf(&buf, "   [%s] %s\n", formatSearchTimestamp(snippet.Start), snippet.Text)
+			} else {
+				fmt.Fprintf(&buf, "   %s\n", snippet.Text)
+			}
+		}
+		output.Results = append(output.Results, item)
+	}
+	return mcpTextResult(buf.String()), output, nil
+}
+
+// handleGetWatchStats implements the get_watch_stats tool
+func (s *MCPServer) handleGetWatchStats(ctx context.Context, _ *mcp.CallToolRequest, input mcpWatchStatsInput) (*mcp.CallToolResult, mcpWatchStatsOutput, error) {
+	var zero mcpWatchStatsOutput
+	group, err := tldw.ParseStatsGroup(input.GroupBy)
+	if err != nil {
+		return nil, zero, err
+	}
+	now := time.Now()
+	period := input.Period
+	if period == "" {
+		period = "all"
+	}
+	resolved, err := tldw.ResolveStatsPeriod(period, now)
+	if err != nil {
+		return nil, zero, err
+	}
+	if strings.TrimSpace(input.From) != "" || strings.TrimSpace(input.To) != "" {
+		resolved = tldw.StatsPeriod{Name: "range", Label: "custom range"}
+		if resolved.From, err = parseMCPTime(input.From, "from"); err != nil {
+			return nil, zero, err
+		}
+		if resolved.To, err = parseMCPTime(input.To, "to"); err != nil {
+			return nil, zero, err
+		}
+	}
+	logger := requestLogger(ctx)
+	logger.Info("started", "period", resolved.Name, "group_by", string(group))
+
+	report, err := s.engine.Stats(tldw.StatsQuery{
+		From: resolved.From, To: resolved.To, GroupBy: group, Location: now.Location(),
+	})
+	if err != nil {
+		logger.Error("failed", "error", err)
+		return nil, zero, fmt.Errorf("calculating stats: %w", err)
+	}
+
+	output := mcpWatchStatsOutput{
+		Period:          resolved.Name,
+		VideoCount:      report.VideoCount,
+		DurationSeconds: report.DurationSeconds,
+		Groups:          report.Groups,
+	}
+	if !resolved.From.IsZero() {
+		output.From = resolved.From.Format(time.RFC3339)
+	}
+	if !resolved.To.IsZero() {
+		output.To = resolved.To.Format(time.RFC3339)
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "Watch stats — %s\n", resolved.Label)
+	if output.From != "" || output.To != "" {
+		fmt.Fprintf(&buf, "Range: %s to %s\n", valueOrOpen(output.From), valueOrOpen(output.To))
+	}
+	fmt.Fprintf(&buf, "Videos: %d\n", report.VideoCount)
+	fmt.Fprintf(&buf, "Runtime: %s\n", (time.Duration(report.DurationSeconds) * time.Second).String())
+	for _, bucket := range report.Groups {
+		fmt.Fprintf(&buf, "%s: %d videos, %s\n", bucket.Label, bucket.VideoCount,
+			(time.Duration(bucket.DurationSeconds) * time.Second).String())
+	}
+	return mcpTextResult(buf.String()), output, nil
+}
+
+func pluralMatches(count int) string {
+	if count == 1 {
+		return "match"
+	}
+	return "matches"
+}
+
+func valueOrOpen(value string) string {
+	if value == "" {
+		return "open"
+	}
+	return value
+}
+
+func formatSearchTimestamp(seconds float64) string {
+	total := int(seconds)
+	if total >= 3600 {
+		return fmt.Sprintf("%02d:%02d:%02d", total/3600, total%3600/60, total%60)
+	}
+	return fmt.Sprintf("%02d:%02d", total/60, total%60)
+}
@@ -0,0 +1,122 @@
+package mcpserver
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+func TestMCPSearchTranscriptsReturnsTimestampedHits(t *testing.T) {
+	watched := time.Date(2026, time.September, 14, 18, 0, 0, 0, time.UTC)
+	app := &applicationStub{searchResults: []tldw.TranscriptSearchResult{{
+		VideoID: "dQw4w9WgXcQ", Title: "Postgres internals", Channel: "CMU Database Group",
+		FirstSeenAt: watched, Matches: 4,
+		Snippets: []tldw.TranscriptSnippet{{Start: 3725, Timed: true, Text: "vacuum in postgres"}},
+	}}}
+	server := NewMCPServer(app)
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "search_transcripts",
+		Arguments: map[string]any{"query": "postgres", "channel": "CMU", "from": "2026-09-01", "to": "2026-10-01T00:00:00Z"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("search_transcripts failed: %s", textContent(t, result))
+	}
+
+	query := app.searchQuery
+	wantFrom := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.Local)
+	if query.Text != "postgres" || query.Channel != "CMU" || !query.From.Equal(wantFrom) ||
+		!query.To.Equal(time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("search query = %+v", query)
+	}
+	if query.Limit != mcpDefaultSearchLimit || query.SnippetsPerVideo != mcpDefaultSearchSnippets {
+		t.Fatalf("search limits = %d/%d, want defaults", query.Limit, query.SnippetsPerVideo)
+	}
+	if text := textContent(t, result); !strings.Contains(text, "Postgres internals — CMU Database Group") ||
+		!strings.Contains(text, "[01:02:05] vacuum in postgres") {
+		t.Fatalf("search text = %q", text)
+	}
+
+	output := structuredContent[mcpSearchTranscriptsOutput](t, result)
+	if len(output.Results) != 1 {
+		t.Fatalf("results = %+v", output.Results)
+	}
+	got := output.Results[0]
+	if got.URL != "https://www.youtube.com/watch?v=dQw4w9WgXcQ" || got.Matches != 4 ||
+		len(got.Hits) != 1 || got.Hits[0].StartSeconds != 3725 {
+		t.Fatalf("result = %+v", got)
+	}
+}
+
+func TestMCPSearchTranscriptsRejectsInvalidDates(t *testing.T) {
+	app := &applicationStub{}
+	server := NewMCPServer(app)
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "search_transcripts",
+		Arguments: map[string]any{"query": "postgres", "from": "last month"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !result.IsError || !strings.Contains(textContent(t, result), "YYYY-MM-DD") {
+		t.Fatalf("search_transcripts accepted an invalid date: %+v", result)
+	}
+}
+
+func TestMCPGetWatchStatsUsesRangeAndGroup(t *testing.T) {
+	app := &applicationStub{statsReport: tldw.StatsReport{
+		VideoCount: 3, DurationSeconds: 5400,
+		Groups: []tldw.StatsBucket{{Label: "2026-09-01", VideoCount: 3, DurationSeconds: 5400}},
+	}}
+	server := NewMCPServer(app)
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "get_watch_stats",
+		Arguments: map[string]any{"period": "week", "from": "2026-09-01", "to": "2026-10-01", "group_by": "month"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("get_watch_stats failed: %s", textContent(t, result))
+	}
+
+	query := app.statsQuery
+	if !query.From.Equal(time.Date(2026, time.September, 1, 0, 0, 0, 0, time.Local)) ||
+		!query.To.Equal(time.Date(2026, time.October, 1, 0, 0, 0, 0, time.Local)) || query.GroupBy != tldw.StatsGroupMonth {
+		t.Fatalf("stats query = %+v", query)
+	}
+	output := structuredContent[mcpWatchStatsOutput](t, result)
+	if output.Period != "range" || output.VideoCount != 3 || output.DurationSeconds != 5400 || len(output.Groups) != 1 {
+		t.Fatalf("stats output = %+v", output)
+	}
+	if text := textContent(t, result); !strings.Contains(text, "Videos: 3") || !strings.Contains(text, "Runtime: 1h30m0s") {
+		t.Fatalf("stats text = %q", text)
+	}
+}
+
+func TestMCPGetWatchStatsRejectsUnknownPeriod(t *testing.T) {
+	server := NewMCPServer(&applicationStub{})
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "get_watch_stats",
+		Arguments: map[string]any{"period": "decade"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !result.IsError || !strings.Contains(textContent(t, result), "unsupported period") {
+		t.Fatalf("get_watch_stats accepted an unknown period: %+v", result)
+	}
+}
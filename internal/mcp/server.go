@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rtzll/tldw/internal"
 	"github.com/rtzll/tldw/internal/tldw"
 )
 
@@ -21,20 +22,33 @@ type MCPServer struct {
 	mcpServer          *mcp.Server
 	stdioToolMu        sync.Mutex
 	stdioSerializeOnce sync.Once
+	httpOptions        HTTPOptions
+
+	resourceMu        sync.Mutex
+	library           MCPLibrary
+	listedTranscripts map[string]bool
 }
 
 type MCPApplication interface {
 	MetadataFor(context.Context, tldw.YouTubeRef) (*tldw.VideoMetadata, error)
 	Transcript(context.Context, tldw.YouTubeRef, tldw.TranscriptRequest) (*tldw.Transcript, error)
+	SummarizeVideo(context.Context, tldw.YouTubeRef, tldw.SummaryRequest) (tldw.Summary, error)
+	Playlist(context.Context, tldw.YouTubeRef) (*tldw.PlaylistInfo, error)
+	CreatePlaylistSummary(context.Context, tldw.YouTubeRef, tldw.PlaylistSummaryRequest) (tldw.PlaylistSummaryResult, error)
+	SearchTranscripts(tldw.TranscriptSearchQuery) ([]tldw.TranscriptSearchResult, error)
+	Stats(tldw.StatsQuery) (tldw.StatsReport, error)
 }
 
 const (
 	mcpServerVersion  = "1.0.0"
 	mcpMethodCallTool = "tools/call"
 
-	mcpGetMetadataDescription   = "Extract video metadata including caption availability. Check 'Has Captions' field to determine which transcript tool to use: if true, use get_youtube_transcript (free); if false, consider transcribe_youtube_whisper (paid)."
-	mcpGetTranscriptDescription = "Get existing YouTube captions/transcript (FREE). Only works if the video has captions - check metadata first. Fails if no captions available."
-	mcpWhisperDescription       = "Create transcript using OpenAI Whisper API (PAID). Requires OPENAI_API_KEY environment variable to be set. Use only when videos have no captions and user explicitly agrees to incur costs. Always ask user for confirmation before calling this tool."
+	mcpGetMetadataDescription       = "Extract video metadata including caption availability. Check 'Has Captions' field to determine which transcript tool to use: if true, use get_youtube_transcript (free); if false, consider transcribe_youtube_whisper (paid)."
+	mcpGetTranscriptDescription     = "Get existing YouTube captions/transcript (FREE). Only works if the video has captions - check metadata first. Fails if no captions available."
+	mcpWhisperDescription           = "Create transcript using OpenAI Whisper API (PAID). Requires OPENAI_API_KEY environment variable to be set. Use only when videos have no captions and user explicitly agrees to incur costs. Always ask user for confirmation before calling this tool."
+	mcpGetPlaylistDescription       = "List the videos in a YouTube playlist with their IDs, titles, and durations (FREE). Use the returned video URLs with the video tools, or summarize_youtube_playlist to summarize the whole playlist at once."
+	mcpSummarizePlaylistDescription = "Summarize all videos of a YouTube playlist together with OpenAI (PAID), for example a course or conference track. Requires OPENAI_API_KEY environment variable to be set. Videos without captions are skipped unless allow_whisper is set, which transcribes them with Whisper at additional cost. Costs grow with playlist length; always ask user for confirmation before calling this tool. Without OPENAI_API_KEY, clients that support sampling write the summary with their own model."
+	mcpSummarizeDescription         = "Summarize a YouTube video with OpenAI (PAID) and return the summary as Markdown, without loading the transcript into your context. Requires OPENAI_API_KEY environment variable to be set. Uses existing captions; set allow_whisper to transcribe videos without captions with Whisper at additional cost. Always ask user for confirmation before calling this tool. Without OPENAI_API_KEY, clients that support sampling write the summary with their own model."
 )
 
 type mcpGetMetadataInput struct {
@@ -44,11 +58,30 @@ type mcpGetMetadataInput struct {
 type mcpGetTranscriptInput struct {
 	URL               string `json:"url" jsonschema:"YouTube video URL"`
 	IncludeTimestamps bool   `json:"include_timestamps,omitempty" jsonschema:"When true, return transcript lines with timestamps if caption timing data is available."`
+	AllowWhisper      bool   `json:"allow_whisper,omitempty" jsonschema:"When true and the video has no captions, ask the user to confirm a paid Whisper transcription instead of failing. Only used by clients that support confirmation prompts (elicitation)."`
+	mcpTranscriptPaging
 }
 
 type mcpWhisperInput struct {
 	URL               string `json:"url" jsonschema:"YouTube video URL"`
 	IncludeTimestamps bool   `json:"include_timestamps,omitempty" jsonschema:"Reserved for future use. Timestamped Whisper transcripts are not supported yet."`
+	mcpTranscriptPaging
+}
+
+type mcpSummarizeInput struct {
+	URL          string `json:"url" jsonschema:"YouTube video URL"`
+	Prompt       string `json:"prompt,omitempty" jsonschema:"Prompt template to use instead of the configured one. {{.Transcript}}, {{.Title}}, {{.Channel}}, and {{.Description}} are replaced with the video's transcript and metadata."`
+	Model        string `json:"model,omitempty" jsonschema:"OpenAI model to use instead of the configured one"`
+	AllowWhisper bool   `json:"allow_whisper,omitempty" jsonschema:"When true, transcribe the audio with Whisper (additional cost) if the video has no captions."`
+}
+
+type mcpPlaylistInput struct {
+	URL string `json:"url" jsonschema:"YouTube playlist URL or ID"`
+}
+
+type mcpSummarizePlaylistInput struct {
+	URL          string `json:"url" jsonschema:"YouTube playlist URL or ID"`
+	AllowWhisper bool   `json:"allow_whisper,omitempty" jsonschema:"When true, transcribe videos without captions with Whisper (additional cost) instead of skipping them."`
 }
 
 type mcpChapterOutput struct {
@@ -72,15 +105,48 @@ type mcpMetadataOutput struct {
 }
 
 type mcpTranscriptOutput struct {
-	URL               string `json:"url" jsonschema:"Requested YouTube video URL"`
-	Transcript        string `json:"transcript" jsonschema:"Transcript text"`
-	Source            string `json:"source" jsonschema:"Transcript source"`
-	IncludeTimestamps bool   `json:"include_timestamps" jsonschema:"Whether timestamps were requested in the transcript text"`
+	URL               string  `json:"url" jsonschema:"Requested YouTube video URL"`
+	Transcript        string  `json:"transcript" jsonschema:"Transcript text"`
+	Source            string  `json:"source" jsonschema:"Transcript source"`
+	IncludeTimestamps bool    `json:"include_timestamps" jsonschema:"Whether timestamps were requested in the transcript text"`
+	NextCursor        string  `json:"next_cursor,omitempty" jsonschema:"Pass as cursor to get the next part; absent on the last part"`
+	TotalChars        int     `json:"total_chars" jsonschema:"Length of the whole transcript in characters"`
+	StartSeconds      float64 `json:"start_seconds,omitempty" jsonschema:"Start time in seconds of the first returned line, when timestamps are available"`
+	EndSeconds        float64 `json:"end_seconds,omitempty" jsonschema:"End time in seconds of the last returned line, when timestamps are available"`
+}
+
+type mcpSummaryOutput struct {
+	URL              string `json:"url" jsonschema:"Requested YouTube video URL"`
+	Summary          string `json:"summary" jsonschema:"Summary in Markdown"`
+	Model            string `json:"model" jsonschema:"OpenAI model that generated the summary"`
+	TranscriptSource string `json:"transcript_source" jsonschema:"Source of the summarized transcript"`
+}
+
+type mcpPlaylistEntryOutput struct {
+	VideoID         string  `json:"video_id" jsonschema:"YouTube video ID"`
+	URL             string  `json:"url" jsonschema:"YouTube video URL"`
+	Title           string  `json:"title,omitempty" jsonschema:"YouTube video title"`
+	DurationSeconds float64 `json:"duration_seconds,omitempty" jsonschema:"Duration in seconds"`
+}
+
+type mcpPlaylistOutput struct {
+	URL     string                   `json:"url" jsonschema:"Requested YouTube playlist URL"`
+	Title   string                   `json:"title" jsonschema:"YouTube playlist title"`
+	Entries []mcpPlaylistEntryOutput `json:"entries" jsonschema:"Playlist videos in playlist order"`
+}
+
+type mcpPlaylistSummaryOutput struct {
+	URL       string   `json:"url" jsonschema:"Requested YouTube playlist URL"`
+	Title     string   `json:"title" jsonschema:"YouTube playlist title"`
+	Summary   string   `json:"summary" jsonschema:"Summary in Markdown"`
+	Processed int      `json:"processed" jsonschema:"Number of videos included in the summary"`
+	Total     int      `json:"total" jsonschema:"Number of videos in the playlist"`
+	Skipped   []string `json:"skipped" jsonschema:"Videos left out of the summary and why"`
 }
 
 // NewMCPServer creates a new MCP server instance
 func NewMCPServer(engine MCPApplication) *MCPServer {
-	MCPLogInfo("Initializing MCP server (tldw-server v%s)", mcpServerVersion)
+	serverLogger().Info("initializing MCP server", "version", mcpServerVersion)
 
 	mcpServer := mcp.NewServer(&mcp.Implementation{
 		Name:    "tldw-server",
@@ -96,8 +162,9 @@ func NewMCPServer(engine MCPApplication) *MCPServer {
 		mcpServer: mcpServer,
 	}
 
+	mcpServer.AddReceivingMiddleware(observeToolCalls, logRequests)
 	s.registerTools()
-	MCPLogInfo("MCP server initialized with %d tools", 3)
+	serverLogger().Info("MCP server initialized", "tools", 8)
 	return s
 }
 
@@ -136,6 +203,41 @@ func (s *MCPServer) registerTools() {
 		Description: mcpWhisperDescription,
 		Annotations: mcpToolAnnotations(false),
 	}, s.handleWhisperTranscribe)
+
+	// summarize_youtube_video tool (paid - generates a summary using AI)
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "summarize_youtube_video",
+		Description: mcpSummarizeDescription,
+		Annotations: mcpToolAnnotations(false),
+	}, s.handleSummarizeVideo)
+
+	// get_youtube_playlist tool (free - lists playlist videos)
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "get_youtube_playlist",
+		Description: mcpGetPlaylistDescription,
+		Annotations: mcpToolAnnotations(true),
+	}, s.handleGetPlaylist)
+
+	// summarize_youtube_playlist tool (paid - summarizes a whole playlist using AI)
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "summarize_youtube_playlist",
+		Description: mcpSummarizePlaylistDescription,
+		Annotations: mcpToolAnnotations(false),
+	}, s.handleSummarizePlaylist)
+
+	// search_transcripts tool (free - searches cached transcripts)
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "search_transcripts",
+		Description: mcpSearchTranscriptsDescription,
+		Annotations: mcpLibraryToolAnnotations(),
+	}, s.handleSearchTranscripts)
+
+	// get_watch_stats tool (free - reports on cached videos)
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "get_watch_stats",
+		Description: mcpGetWatchStatsDescription,
+		Annotations: mcpLibraryToolAnnotations(),
+	}, s.handleGetWatchStats)
 }
 
 func mcpToolAnnotations(readOnly bool) *mcp.ToolAnnotations {
@@ -148,6 +250,26 @@ func mcpToolAnnotations(readOnly bool) *mcp.ToolAnnotations {
 	}
 }
 
+// invalidVideoURL explains a rejected video URL, pointing playlist URLs to
+// the playlist tools.
+func invalidVideoURL(url string, err error) error {
+	if ref, refErr := tldw.ParseReference(url); refErr == nil && ref.IsPlaylist() {
+		return invalidInputError{fmt.Errorf("%s is a playlist - use get_youtube_playlist or summarize_youtube_playlist: %w", ref.URL(), err)}
+	}
+	return invalidInputError{fmt.Errorf("invalid YouTube video URL: %w", err)}
+}
+
+func parsePlaylistRef(url string) (tldw.YouTubeRef, error) {
+	ref, err := tldw.ParseReference(url)
+	if err != nil {
+		return tldw.YouTubeRef{}, invalidInputError{fmt.Errorf("invalid YouTube playlist URL: %w", err)}
+	}
+	if !ref.IsPlaylist() {
+		return tldw.YouTubeRef{}, invalidInputError{fmt.Errorf("%s is not a playlist URL", url)}
+	}
+	return ref, nil
+}
+
 func mcpTextResult(text string) *mcp.CallToolResult {
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{&mcp.TextContent{Text: text}},
@@ -157,24 +279,25 @@ func mcpTextResult(text string) *mcp.CallToolResult {
 // handleGetMetadata implements the get_youtube_metadata tool
 func (s *MCPServer) handleGetMetadata(ctx context.Context, _ *mcp.CallToolRequest, input mcpGetMetadataInput) (*mcp.CallToolResult, mcpMetadataOutput, error) {
 	var zero mcpMetadataOutput
+	logger := requestLogger(ctx)
 	url := input.URL
 	parsed, err := tldw.ParseVideoRef(url)
 	if err != nil {
-		MCPLogError("Tool: get_youtube_metadata - invalid URL: %v", err)
-		return nil, zero, fmt.Errorf("invalid YouTube video URL: %w", err)
+		logger.Error("invalid URL", "error", err)
+		return nil, zero, invalidVideoURL(input.URL, err)
 	}
 	url = parsed.URL()
-	MCPLogInfo("Tool: get_youtube_metadata - URL: %s", url)
+	logger.Info("started", "url", url)
 
 	// Get metadata from YouTube
 	metadata, err := s.engine.MetadataFor(ctx, parsed)
 	if err != nil {
-		MCPLogError("Tool: get_youtube_metadata failed - %v", err)
+		logger.Error("failed", "error", err)
 		return nil, zero, fmt.Errorf("metadata error: %w", err)
 	}
 
-	MCPLogInfo("Tool: get_youtube_metadata succeeded - Title: %s, Duration: %.0fs, HasCaptions: %t",
-		metadata.Title, metadata.Duration, metadata.HasCaptions)
+	logger.Info("succeeded", "title", metadata.Title, "duration_seconds", metadata.Duration,
+		"has_captions", metadata.HasCaptions)
 
 	output := mcpMetadataOutput{
 		Title:            metadata.Title,
@@ -222,88 +345,301 @@ func (s *MCPServer) handleGetMetadata(ctx context.Context, _ *mcp.CallToolReques
 }
 
 // handleGetTranscript implements the get_youtube_transcript tool (free captions only)
-func (s *MCPServer) handleGetTranscript(ctx context.Context, _ *mcp.CallToolRequest, input mcpGetTranscriptInput) (*mcp.CallToolResult, mcpTranscriptOutput, error) {
+func (s *MCPServer) handleGetTranscript(ctx context.Context, request *mcp.CallToolRequest, input mcpGetTranscriptInput) (*mcp.CallToolResult, mcpTranscriptOutput, error) {
 	var zero mcpTranscriptOutput
+	logger := requestLogger(ctx)
 	url := input.URL
 	parsed, err := tldw.ParseVideoRef(url)
 	if err != nil {
-		MCPLogError("Tool: get_youtube_transcript - invalid URL: %v", err)
-		return nil, zero, fmt.Errorf("invalid YouTube video URL: %w", err)
+		logger.Error("invalid URL", "error", err)
+		return nil, zero, invalidVideoURL(input.URL, err)
 	}
 	url = parsed.URL()
-	MCPLogInfo("Tool: get_youtube_transcript - URL: %s", url)
+	logger.Info("started", "url", url)
 	includeTimestamps := input.IncludeTimestamps
 
 	format := tldw.TranscriptRenderFormatPlain
 	if includeTimestamps {
 		format = tldw.TranscriptRenderFormatTimestamps
 	}
+	window, err := input.window(parsed.ID())
+	if err != nil {
+		logger.Error("invalid window", "error", err)
+		return nil, zero, err
+	}
 
-	structured, err := s.engine.Transcript(ctx, parsed, tldw.TranscriptRequest{
+	source := tldw.TranscriptSourceCaptions
+	structured, err := s.engine.Transcript(withProgress(ctx, request), parsed, tldw.TranscriptRequest{
 		Policy:            tldw.TranscriptPolicyCaptionsOnly,
-		RequireTimestamps: includeTimestamps,
+		RequireTimestamps: includeTimestamps || window.IsTimed(),
 	})
+	if errors.Is(err, tldw.ErrCaptionsUnavailable) && input.AllowWhisper && canConfirm(request) {
+		var confirmation *mcp.CallToolResult
+		if confirmation, err = s.confirmWhisper(ctx, request, parsed); confirmation != nil {
+			logger.Info("no captions, asking to confirm Whisper")
+			return confirmation, zero, nil
+		}
+		if err == nil {
+			source = tldw.TranscriptSourceWhisper
+			structured, err = s.engine.Transcript(withProgress(ctx, request), parsed, tldw.TranscriptRequest{Policy: tldw.TranscriptPolicyWhisperOnly})
+		}
+	}
 	if err != nil {
-		MCPLogError("Tool: get_youtube_transcript failed - %v", err)
+		logger.Error("failed", "error", err)
 		if errors.Is(err, tldw.ErrCaptionsUnavailable) || errors.Is(err, tldw.ErrTranscriptTimestampsUnavailable) {
 			return nil, zero, fmt.Errorf("no captions available - use get_youtube_metadata to check caption availability, or consider transcribe_youtube_whisper (paid): %w", err)
 		}
+		if errors.Is(err, errWhisperDeclined) {
+			return nil, zero, fmt.Errorf("no captions available and %w", err)
+		}
 		return nil, zero, fmt.Errorf("getting transcript: %w", err)
 	}
-	transcript, err := structured.Render(format)
-	if err != nil {
-		return nil, zero, err
-	}
-
-	MCPLogInfo("Tool: get_youtube_transcript succeeded - transcript length: %d characters", len(transcript))
-
 	output := mcpTranscriptOutput{
 		URL:               url,
-		Transcript:        transcript,
-		Source:            string(tldw.TranscriptSourceCaptions),
+		Source:            string(source),
 		IncludeTimestamps: includeTimestamps,
 	}
+	text, err := pageTranscript(structured, format, window, parsed.ID(), &output)
+	if err != nil {
+		return nil, zero, err
+	}
 
-	return mcpTextResult(transcript), output, nil
+	logger.Info("succeeded", "returned_chars", len(output.Transcript), "total_chars", output.TotalChars)
+
+	return mcpTextResult(text), output, nil
 }
 
 // handleWhisperTranscribe implements the transcribe_youtube_whisper tool (paid Whisper transcription)
-func (s *MCPServer) handleWhisperTranscribe(ctx context.Context, _ *mcp.CallToolRequest, input mcpWhisperInput) (*mcp.CallToolResult, mcpTranscriptOutput, error) {
+func (s *MCPServer) handleWhisperTranscribe(ctx context.Context, request *mcp.CallToolRequest, input mcpWhisperInput) (*mcp.CallToolResult, mcpTranscriptOutput, error) {
 	var zero mcpTranscriptOutput
+	logger := requestLogger(ctx)
 	url := input.URL
 	parsed, err := tldw.ParseVideoRef(url)
 	if err != nil {
-		MCPLogError("Tool: transcribe_youtube_whisper - invalid URL: %v", err)
-		return nil, zero, fmt.Errorf("invalid YouTube video URL: %w", err)
+		logger.Error("invalid URL", "error", err)
+		return nil, zero, invalidVideoURL(input.URL, err)
 	}
 	url = parsed.URL()
-	MCPLogInfo("Tool: transcribe_youtube_whisper - URL: %s (PAID OPERATION)", url)
+	logger.Info("started", "url", url, "paid", true)
 	if input.IncludeTimestamps {
 		err := fmt.Errorf("timestamped Whisper transcripts are not supported yet")
-		MCPLogError("Tool: transcribe_youtube_whisper failed - %v", err)
+		logger.Error("failed", "error", err)
 		return nil, zero, err
 	}
-
-	structured, err := s.engine.Transcript(ctx, parsed, tldw.TranscriptRequest{Policy: tldw.TranscriptPolicyWhisperOnly})
-	if err != nil {
-		MCPLogError("Tool: transcribe_youtube_whisper - transcription failed: %v", err)
-		return nil, zero, fmt.Errorf("failed to transcribe audio with Whisper: %w", err)
+	window, err := input.window(parsed.ID())
+	if err == nil && window.IsTimed() {
+		err = fmt.Errorf("start_seconds and end_seconds need timestamps, which Whisper transcripts do not have yet; use max_chars to read the transcript in parts")
 	}
-	transcript, err := structured.Render(tldw.TranscriptRenderFormatPlain)
 	if err != nil {
+		logger.Error("failed", "error", err)
 		return nil, zero, err
 	}
 
-	MCPLogInfo("Tool: transcribe_youtube_whisper succeeded - transcript length: %d characters", len(transcript))
+	confirmation, err := s.confirmWhisper(ctx, request, parsed)
+	if err != nil {
+		logger.Error("confirming Whisper failed", "error", err)
+		return nil, zero, err
+	}
+	if confirmation != nil {
+		logger.Info("asking the user to confirm")
+		return confirmation, zero, nil
+	}
 
+	structured, err := s.engine.Transcript(withProgress(ctx, request), parsed, tldw.TranscriptRequest{Policy: tldw.TranscriptPolicyWhisperOnly})
+	if err != nil {
+		logger.Error("transcription failed", "error", err)
+		return nil, zero, fmt.Errorf("failed to transcribe audio with Whisper: %w", err)
+	}
 	output := mcpTranscriptOutput{
 		URL:               url,
-		Transcript:        transcript,
 		Source:            string(tldw.TranscriptSourceWhisper),
 		IncludeTimestamps: false,
 	}
+	text, err := pageTranscript(structured, tldw.TranscriptRenderFormatPlain, window, parsed.ID(), &output)
+	if err != nil {
+		return nil, zero, err
+	}
 
-	return mcpTextResult(transcript), output, nil
+	logger.Info("succeeded", "returned_chars", len(output.Transcript), "total_chars", output.TotalChars)
+
+	return mcpTextResult(text), output, nil
+}
+
+// handleSummarizeVideo implements the summarize_youtube_video tool (paid summary)
+func (s *MCPServer) handleSummarizeVideo(ctx context.Context, request *mcp.CallToolRequest, input mcpSummarizeInput) (*mcp.CallToolResult, mcpSummaryOutput, error) {
+	var zero mcpSummaryOutput
+	logger := requestLogger(ctx)
+	parsed, err := tldw.ParseVideoRef(input.URL)
+	if err != nil {
+		logger.Error("invalid URL", "error", err)
+		return nil, zero, invalidVideoURL(input.URL, err)
+	}
+	url := parsed.URL()
+	model := strings.TrimSpace(input.Model)
+	if model != "" {
+		// Reject a bad model before any paid transcription starts.
+		if err := internal.ValidateModel(model); err != nil {
+			logger.Error("invalid model", "error", err)
+			return nil, zero, err
+		}
+	}
+	logger.Info("started", "url", url, "allow_whisper", input.AllowWhisper, "paid", true)
+
+	// Clients that can elicit get captions first and confirm Whisper with the
+	// user like get_youtube_transcript; others leave consent with the model.
+	policy := tldw.TranscriptPolicyCaptionsOnly
+	if input.AllowWhisper && !canConfirm(request) {
+		policy = tldw.TranscriptPolicyCaptionsThenWhisper
+	}
+	summarize := func(policy tldw.TranscriptPolicy) (tldw.Summary, error) {
+		return s.engine.SummarizeVideo(withSampling(withProgress(ctx, request), request), parsed, tldw.SummaryRequest{
+			Transcript: tldw.TranscriptRequest{Policy: policy},
+			Prompt:     input.Prompt,
+			Model:      model,
+		})
+	}
+	summary, err := summarize(policy)
+	if errors.Is(err, tldw.ErrCaptionsUnavailable) && input.AllowWhisper && canConfirm(request) {
+		var confirmation *mcp.CallToolResult
+		if confirmation, err = s.confirmWhisper(ctx, request, parsed); confirmation != nil {
+			logger.Info("no captions, asking to confirm Whisper")
+			return confirmation, zero, nil
+		}
+		if err == nil {
+			summary, err = summarize(tldw.TranscriptPolicyCaptionsThenWhisper)
+		}
+	}
+	if sampling := samplingResult(err); sampling != nil {
+		logger.Info("asking the client's model for the summary")
+		return sampling, zero, nil
+	}
+	if err != nil {
+		logger.Error("failed", "error", err)
+		if errors.Is(err, tldw.ErrCaptionsUnavailable) {
+			return nil, zero, fmt.Errorf("no captions available - call again with allow_whisper after the user agrees to Whisper costs: %w", err)
+		}
+		if errors.Is(err, errWhisperDeclined) {
+			return nil, zero, fmt.Errorf("no captions available and %w", err)
+		}
+		return nil, zero, fmt.Errorf("summarizing video: %w", err)
+	}
+	if sampled, ok := sampledModel(request); ok {
+		summary.Model = sampled
+	}
+
+	logger.Info("succeeded", "model", summary.Model, "source", string(summary.TranscriptSource),
+		"summary_chars", len(summary.Markdown))
+
+	output := mcpSummaryOutput{
+		URL:              url,
+		Summary:          summary.Markdown,
+		Model:            summary.Model,
+		TranscriptSource: string(summary.TranscriptSource),
+	}
+	return mcpTextResult(summary.Markdown), output, nil
+}
+
+// handleGetPlaylist implements the get_youtube_playlist tool
+func (s *MCPServer) handleGetPlaylist(ctx context.Context, _ *mcp.CallToolRequest, input mcpPlaylistInput) (*mcp.CallToolResult, mcpPlaylistOutput, error) {
+	var zero mcpPlaylistOutput
+	logger := requestLogger(ctx)
+	parsed, err := parsePlaylistRef(input.URL)
+	if err != nil {
+		logger.Error("invalid URL", "error", err)
+		return nil, zero, err
+	}
+	logger.Info("started", "url", parsed.URL())
+
+	playlist, err := s.engine.Playlist(ctx, parsed)
+	if err != nil {
+		logger.Error("failed", "error", err)
+		return nil, zero, fmt.Errorf("playlist error: %w", err)
+	}
+
+	logger.Info("succeeded", "title", playlist.Title, "videos", len(playlist.Entries))
+
+	output := mcpPlaylistOutput{
+		URL:     parsed.URL(),
+		Title:   playlist.Title,
+		Entries: make([]mcpPlaylistEntryOutput, 0, len(playlist.Entries)),
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "Playlist: %s\n", playlist.Title)
+	fmt.Fprintf(&buf, "Videos: %d\n", len(playlist.Entries))
+	for i, entry := range playlist.Entries {
+		output.Entries = append(output.Entries, mcpPlaylistEntryOutput{
+			VideoID:         entry.Ref.ID(),
+			URL:             entry.Ref.URL(),
+			Title:           entry.Title,
+			DurationSeconds: entry.Duration,
+		})
+		title := entry.Title
+		if title == "" {
+			title = entry.Ref.ID()
+		}
+		fmt.Fprintf(&buf, "%d. %s (%s, %.0f seconds)\n", i+1, title, entry.Ref.URL(), entry.Duration)
+	}
+
+	return mcpTextResult(buf.String()), output, nil
+}
+
+// handleSummarizePlaylist implements the summarize_youtube_playlist tool (paid summary)
+func (s *MCPServer) handleSummarizePlaylist(ctx context.Context, request *mcp.CallToolRequest, input mcpSummarizePlaylistInput) (*mcp.CallToolResult, mcpPlaylistSummaryOutput, error) {
+	var zero mcpPlaylistSummaryOutput
+	logger := requestLogger(ctx)
+	parsed, err := parsePlaylistRef(input.URL)
+	if err != nil {
+		logger.Error("invalid URL", "error", err)
+		return nil, zero, err
+	}
+	logger.Info("started", "url", parsed.URL(), "allow_whisper", input.AllowWhisper, "paid", true)
+
+	policy := tldw.TranscriptPolicyCaptionsOnly
+	if input.AllowWhisper {
+		var confirmation *mcp.CallToolResult
+		confirmation, policy, err = s.playlistWhisperPolicy(ctx, request, parsed)
+		if err != nil {
+			logger.Error("failed", "error", err)
+			return nil, zero, err
+		}
+		if confirmation != nil {
+			logger.Info("asking to confirm Whisper for videos without captions")
+			return confirmation, zero, nil
+		}
+	}
+	result, err := s.engine.CreatePlaylistSummary(withSampling(withProgress(ctx, request), request), parsed, tldw.PlaylistSummaryRequest{
+		Transcript: tldw.TranscriptRequest{Policy: policy},
+	})
+	if sampling := samplingResult(err); sampling != nil {
+		logger.Info("asking the client's model for the summary")
+		return sampling, zero, nil
+	}
+	if err != nil {
+		logger.Error("failed", "error", err)
+		return nil, zero, fmt.Errorf("summarizing playlist: %w", err)
+	}
+
+	logger.Info("succeeded", "processed", result.Processed, "total", result.Total,
+		"summary_chars", len(result.Markdown))
+
+	skipped := result.Skipped
+	if skipped == nil {
+		skipped = []string{}
+	}
+	output := mcpPlaylistSummaryOutput{
+		URL:       parsed.URL(),
+		Title:     result.Title,
+		Summary:   result.Markdown,
+		Processed: result.Processed,
+		Total:     result.Total,
+		Skipped:   skipped,
+	}
+
+	text := result.Markdown
+	if len(skipped) > 0 {
+		text += fmt.Sprintf("\n\nSkipped %d of %d videos:\n- %s\n", len(skipped), result.Total, strings.Join(skipped, "\n- "))
+	}
+	return mcpTextResult(text), output, nil
 }
 
 // Start starts the MCP server using the specified transport
@@ -315,22 +651,28 @@ func (s *MCPServer) Start(ctx context.Context, transport, host string, port int)
 		}
 
 		addr := net.JoinHostPort(host, strconv.Itoa(port))
-		MCPLogInfo("Starting MCP server with HTTP transport on %s", addr)
+		logger := serverLogger().With("transport", transport)
+		logger.Info("starting MCP server", "addr", addr)
 		if ctx.Err() != nil {
-			MCPLogError("Context cancelled before HTTP server start")
+			logger.Error("context canceled before the HTTP server started")
 			return ctx.Err()
 		}
 
-		handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
-			return s.mcpServer
-		}, nil)
+		handler, err := s.httpHandler(host)
+		if err != nil {
+			logger.Error("HTTP server refused to start", "error", err)
+			return err
+		}
+		if len(s.httpOptions.Tokens) == 0 {
+			logger.Info("no tokens configured; accepting unauthenticated loopback clients")
+		}
 		httpServer := &http.Server{
 			Addr:    addr,
 			Handler: handler,
 		}
 		errCh := make(chan error, 1)
 		go func() {
-			errCh <- httpServer.ListenAndServe()
+			errCh <- s.listenAndServe(httpServer)
 		}()
 
 		select {
@@ -339,12 +681,12 @@ func (s *MCPServer) Start(ctx context.Context, transport, host string, port int)
 			defer cancel()
 			if err := httpServer.Shutdown(shutdownCtx); err != nil {
 				if closeErr := httpServer.Close(); closeErr != nil {
-					MCPLogError("HTTP server forced close failed: %v", closeErr)
+					logger.Error("HTTP server forced close failed", "error", closeErr)
 					return closeErr
 				}
 			}
 			if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
-				MCPLogError("HTTP server failed: %v", err)
+				logger.Error("HTTP server failed", "error", err)
 				return err
 			}
 			return ctx.Err()
@@ -352,17 +694,18 @@ func (s *MCPServer) Start(ctx context.Context, transport, host string, port int)
 			if errors.Is(err, http.ErrServerClosed) {
 				return nil
 			}
-			MCPLogError("HTTP server failed to start: %v", err)
+			logger.Error("HTTP server failed to start", "error", err)
 			return err
 		}
 	case "stdio":
-		MCPLogInfo("Starting MCP server with stdio transport")
+		logger := serverLogger().With("transport", transport)
+		logger.Info("starting MCP server")
 		s.stdioSerializeOnce.Do(func() {
 			s.mcpServer.AddReceivingMiddleware(s.serializeStdioToolCalls)
 		})
 		err := s.mcpServer.Run(ctx, &mcp.StdioTransport{})
 		if err != nil {
-			MCPLogError("Stdio server failed: %v", err)
+			logger.Error("stdio server failed", "error", err)
 		}
 		return err
 	default:
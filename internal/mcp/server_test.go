@@ -22,9 +22,23 @@ type applicationStub struct {
 	metadataErr     error
 	transcript      *tldw.Transcript
 	transcriptErr   error
+	whisper         *tldw.Transcript
+	ai              tldw.AIAdapter
 	metadataCalls   int
 	transcriptCalls int
 	lastRequest     tldw.TranscriptRequest
+	summary         tldw.Summary
+	summaryErr      error
+	summaryCalls    int
+	summaryRequest  tldw.SummaryRequest
+	playlist        *tldw.PlaylistInfo
+	playlistResult  tldw.PlaylistSummaryResult
+	playlistRequest tldw.PlaylistSummaryRequest
+	progress        []tldw.Progress
+	searchResults   []tldw.TranscriptSearchResult
+	searchQuery     tldw.TranscriptSearchQuery
+	statsReport     tldw.StatsReport
+	statsQuery      tldw.StatsQuery
 }
 
 func (stub *applicationStub) MetadataFor(context.Context, tldw.YouTubeRef) (*tldw.VideoMetadata, error) {
@@ -35,9 +49,50 @@ func (stub *applicationStub) MetadataFor(context.Context, tldw.YouTubeRef) (*tld
 func (stub *applicationStub) Transcript(_ context.Context, _ tldw.YouTubeRef, request tldw.TranscriptRequest) (*tldw.Transcript, error) {
 	stub.transcriptCalls++
 	stub.lastRequest = request
+	if stub.whisper != nil && request.Policy == tldw.TranscriptPolicyWhisperOnly {
+		return stub.whisper, nil
+	}
 	return stub.transcript, stub.transcriptErr
 }
 
+func (stub *applicationStub) SummarizeVideo(ctx context.Context, _ tldw.YouTubeRef, request tldw.SummaryRequest) (tldw.Summary, error) {
+	stub.summaryCalls++
+	for _, progress := range stub.progress {
+		tldw.ReportProgress(ctx, progress)
+	}
+	stub.summaryRequest = request
+	if stub.ai != nil {
+		markdown, err := stub.ai.Summary(ctx, request.Model, "Summarize: "+request.Prompt)
+		if err != nil {
+			return tldw.Summary{}, fmt.Errorf("generating summary: %w", err)
+		}
+		return tldw.Summary{Markdown: markdown, Model: stub.ai.SummaryModel()}, nil
+	}
+	if stub.whisper != nil && request.Transcript.Policy == tldw.TranscriptPolicyCaptionsThenWhisper {
+		return tldw.Summary{Markdown: stub.whisper.Text, TranscriptSource: tldw.TranscriptSourceWhisper}, nil
+	}
+	return stub.summary, stub.summaryErr
+}
+
+func (stub *applicationStub) Playlist(context.Context, tldw.YouTubeRef) (*tldw.PlaylistInfo, error) {
+	return stub.playlist, nil
+}
+
+func (stub *applicationStub) CreatePlaylistSummary(_ context.Context, _ tldw.YouTubeRef, request tldw.PlaylistSummaryRequest) (tldw.PlaylistSummaryResult, error) {
+	stub.playlistRequest = request
+	return stub.playlistResult, nil
+}
+
+func (stub *applicationStub) SearchTranscripts(query tldw.TranscriptSearchQuery) ([]tldw.TranscriptSearchResult, error) {
+	stub.searchQuery = query
+	return stub.searchResults, nil
+}
+
+func (stub *applicationStub) Stats(query tldw.StatsQuery) (tldw.StatsReport, error) {
+	stub.statsQuery = query
+	return stub.statsReport, nil
+}
+
 func TestMCPToolsDeclareSchemasDescriptionsAndAnnotations(t *testing.T) {
 	server := NewMCPServer(&applicationStub{})
 	ctx, clientSession := connectTestMCPClient(t, server)
@@ -46,8 +101,8 @@ func TestMCPToolsDeclareSchemasDescriptionsAndAnnotations(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ListTools() error = %v", err)
 	}
-	if len(res.Tools) != 3 {
-		t.Fatalf("tool count = %d, want 3", len(res.Tools))
+	if len(res.Tools) != 8 {
+		t.Fatalf("tool count = %d, want 8", len(res.Tools))
 	}
 
 	tools := make(map[string]*mcp.Tool)
@@ -61,6 +116,7 @@ func TestMCPToolsDeclareSchemasDescriptionsAndAnnotations(t *testing.T) {
 		requiredInput []string
 		outputFields  []string
 		readOnly      bool
+		localOnly     bool
 	}{
 		"get_youtube_metadata": {
 			description: "Extract video metadata including caption availability. Check 'Has Captions' field to determine which transcript tool to use: if true, use get_youtube_transcript (free); if false, consider transcribe_youtube_whisper (paid).",
@@ -83,6 +139,11 @@ func TestMCPToolsDeclareSchemasDescriptionsAndAnnotations(t *testing.T) {
 			inputFields: map[string]string{
 				"url":                "YouTube video URL",
 				"include_timestamps": "When true, return transcript lines with timestamps if caption timing data is available.",
+				"allow_whisper":      "When true and the video has no captions, ask the user to confirm a paid Whisper transcription instead of failing. Only used by clients that support confirmation prompts (elicitation).",
+				"start_seconds":      "Only return transcript lines starting at or after this many seconds. Needs caption timestamps.",
+				"end_seconds":        "Only return transcript lines starting before this many seconds. Needs caption timestamps.",
+				"max_chars":          "Maximum characters to return per call. Longer transcripts return next_cursor for the following page.",
+				"cursor":             "next_cursor from the previous call, to continue with the same arguments",
 			},
 			requiredInput: []string{"url"},
 			outputFields: []string{
@@ -90,6 +151,10 @@ func TestMCPToolsDeclareSchemasDescriptionsAndAnnotations(t *testing.T) {
 				"transcript",
 				"source",
 				"include_timestamps",
+				"next_cursor",
+				"total_chars",
+				"start_seconds",
+				"end_seconds",
 			},
 			readOnly: true,
 		},
@@ -98,6 +163,8 @@ func TestMCPToolsDeclareSchemasDescriptionsAndAnnotations(t *testing.T) {
 			inputFields: map[string]string{
 				"url":                "YouTube video URL",
 				"include_timestamps": "Reserved for future use. Timestamped Whisper transcripts are not supported yet.",
+				"max_chars":          "Maximum characters to return per call. Longer transcripts return next_cursor for the following page.",
+				"cursor":             "next_cursor from the previous call, to continue with the same arguments",
 			},
 			requiredInput: []string{"url"},
 			outputFields: []string{
@@ -105,9 +172,72 @@ func TestMCPToolsDeclareSchemasDescriptionsAndAnnotations(t *testing.T) {
 				"transcript",
 				"source",
 				"include_timestamps",
+				"next_cursor",
+				"total_chars",
+				"start_seconds",
+				"end_seconds",
 			},
 			readOnly: false,
 		},
+		"summarize_youtube_video": {
+			description: "Summarize a YouTube video with OpenAI (PAID) and return the summary as Markdown, without loading the transcript into your context. Requires OPENAI_API_KEY environment variable to be set. Uses existing captions; set allow_whisper to transcribe videos without captions with Whisper at additional cost. Always ask user for confirmation before calling this tool. Without OPENAI_API_KEY, clients that support sampling write the summary with their own model.",
+			inputFields: map[string]string{
+				"url":           "YouTube video URL",
+				"model":         "OpenAI model to use instead of the configured one",
+				"allow_whisper": "When true, transcribe the audio with Whisper (additional cost) if the video has no captions.",
+			},
+			requiredInput: []string{"url"},
+			outputFields: []string{
+				"url",
+				"summary",
+				"model",
+				"transcript_source",
+			},
+			readOnly: false,
+		},
+		"get_youtube_playlist": {
+			description: "List the videos in a YouTube playlist with their IDs, titles, and durations (FREE). Use the returned video URLs with the video tools, or summarize_youtube_playlist to summarize the whole playlist at once.",
+			inputFields: map[string]string{
+				"url": "YouTube playlist URL or ID",
+			},
+			requiredInput: []string{"url"},
+			outputFields:  []string{"url", "title", "entries"},
+			readOnly:      true,
+		},
+		"summarize_youtube_playlist": {
+			description: "Summarize all videos of a YouTube playlist together with OpenAI (PAID), for example a course or conference track. Requires OPENAI_API_KEY environment variable to be set. Videos without captions are skipped unless allow_whisper is set, which transcribes them with Whisper at additional cost. Costs grow with playlist length; always ask user for confirmation before calling this tool. Without OPENAI_API_KEY, clients that support sampling write the summary with their own model.",
+			inputFields: map[string]string{
+				"url":           "YouTube playlist URL or ID",
+				"allow_whisper": "When true, transcribe videos without captions with Whisper (additional cost) instead of skipping them.",
+			},
+			requiredInput: []string{"url"},
+			outputFields:  []string{"url", "title", "summary", "processed", "total", "skipped"},
+			readOnly:      false,
+		},
+		"search_transcripts": {
+			description: mcpSearchTranscriptsDescription,
+			inputFields: map[string]string{
+				"query":   "Words or phrase to find, matched case-insensitively",
+				"channel": "Only search videos from channels whose name contains this text",
+				"from":    "Only search videos first watched at or after this date (YYYY-MM-DD) or RFC 3339 time",
+				"to":      "Only search videos first watched before this date (YYYY-MM-DD) or RFC 3339 time",
+				"limit":   "Maximum number of videos to return (default 10)",
+			},
+			requiredInput: []string{"query"},
+			outputFields:  []string{"query", "results"},
+			readOnly:      true,
+			localOnly:     true,
+		},
+		"get_watch_stats": {
+			description: mcpGetWatchStatsDescription,
+			inputFields: map[string]string{
+				"period":   "Calendar period: today, week, month, or all (default all). Ignored when from or to is set.",
+				"group_by": "Group results by day, week, or month",
+			},
+			outputFields: []string{"period", "video_count", "duration_seconds", "groups"},
+			readOnly:     true,
+			localOnly:    true,
+		},
 	}
 
 	for name, wantTool := range want {
@@ -159,24 +289,109 @@ func TestMCPToolsDeclareSchemasDescriptionsAndAnnotations(t *testing.T) {
 		if tool.Annotations.DestructiveHint == nil || *tool.Annotations.DestructiveHint {
 			t.Errorf("%s destructiveHint is not false", name)
 		}
-		if tool.Annotations.OpenWorldHint == nil || !*tool.Annotations.OpenWorldHint {
-			t.Errorf("%s openWorldHint is not true", name)
+		if tool.Annotations.OpenWorldHint == nil || *tool.Annotations.OpenWorldHint == wantTool.localOnly {
+			t.Errorf("%s openWorldHint = %v, want %t", name, tool.Annotations.OpenWorldHint, !wantTool.localOnly)
 		}
 	}
 }
 
-func TestMCPToolDescriptionsDoNotAdvertisePlaylists(t *testing.T) {
-	server := NewMCPServer(&applicationStub{})
+func TestMCPVideoToolsPointPlaylistURLsToPlaylistTools(t *testing.T) {
+	app := &applicationStub{}
+	server := NewMCPServer(app)
 	ctx, clientSession := connectTestMCPClient(t, server)
 
-	res, err := clientSession.ListTools(ctx, nil)
+	for _, tool := range []string{"get_youtube_metadata", "get_youtube_transcript", "transcribe_youtube_whisper", "summarize_youtube_video"} {
+		result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+			Name:      tool,
+			Arguments: map[string]any{"url": "https://www.youtube.com/playlist?list=PLSE8ODhjZXjYDBpQnSymaectKjxCy6BYq"},
+		})
+		if err != nil {
+			t.Fatalf("CallTool(%s) error = %v", tool, err)
+		}
+		if !result.IsError || !strings.Contains(textContent(t, result), "get_youtube_playlist") {
+			t.Fatalf("%s result = %q, want error pointing to the playlist tools", tool, textContent(t, result))
+		}
+	}
+	if app.metadataCalls+app.transcriptCalls+app.summaryCalls != 0 {
+		t.Fatal("video tools called the application with a playlist URL")
+	}
+}
+
+func TestMCPGetPlaylistReturnsEntries(t *testing.T) {
+	first, err := tldw.ParseVideoRef("dQw4w9WgXcQ")
 	if err != nil {
-		t.Fatalf("ListTools() error = %v", err)
+		t.Fatalf("ParseVideoRef() error = %v", err)
 	}
-	for _, tool := range res.Tools {
-		if strings.Contains(strings.ToLower(tool.Description), "playlist") {
-			t.Fatalf("tool %q description advertises playlist support: %q", tool.Name, tool.Description)
-		}
+	second, err := tldw.ParseVideoRef("tAP1eZYEuKA")
+	if err != nil {
+		t.Fatalf("ParseVideoRef() error = %v", err)
+	}
+	app := &applicationStub{playlist: &tldw.PlaylistInfo{
+		Title:  "Database Systems",
+		Videos: []tldw.YouTubeRef{first, second},
+		Entries: []tldw.PlaylistEntry{
+			{Ref: first, Title: "Relational Model", Duration: 4200},
+			{Ref: second, Title: "Storage"},
+		},
+	}}
+	server := NewMCPServer(app)
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "get_youtube_playlist",
+		Arguments: map[string]any{"url": "PLSE8ODhjZXjYDBpQnSymaectKjxCy6BYq"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("CallTool() returned tool error: %s", textContent(t, result))
+	}
+	if got := textContent(t, result); !strings.Contains(got, "1. Relational Model (https://www.youtube.com/watch?v=dQw4w9WgXcQ, 4200 seconds)") {
+		t.Fatalf("text content = %q, want numbered entries", got)
+	}
+
+	output := structuredContent[mcpPlaylistOutput](t, result)
+	if output.URL != "https://www.youtube.com/playlist?list=PLSE8ODhjZXjYDBpQnSymaectKjxCy6BYq" || output.Title != "Database Systems" {
+		t.Errorf("structured playlist = %q %q", output.URL, output.Title)
+	}
+	want := []mcpPlaylistEntryOutput{
+		{VideoID: "dQw4w9WgXcQ", URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", Title: "Relational Model", DurationSeconds: 4200},
+		{VideoID: "tAP1eZYEuKA", URL: "https://www.youtube.com/watch?v=tAP1eZYEuKA", Title: "Storage"},
+	}
+	if len(output.Entries) != len(want) || output.Entries[0] != want[0] || output.Entries[1] != want[1] {
+		t.Fatalf("structured entries = %+v, want %+v", output.Entries, want)
+	}
+}
+
+func TestMCPSummarizePlaylistReportsProcessedAndSkippedVideos(t *testing.T) {
+	app := &applicationStub{playlistResult: tldw.PlaylistSummaryResult{
+		Title: "Database Systems", Markdown: "## Course", Processed: 1, Total: 2,
+		Skipped: []string{"Video 2: Storage (transcript error)"},
+	}}
+	server := NewMCPServer(app)
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "summarize_youtube_playlist",
+		Arguments: map[string]any{"url": "https://www.youtube.com/playlist?list=PLSE8ODhjZXjYDBpQnSymaectKjxCy6BYq"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("CallTool() returned tool error: %s", textContent(t, result))
+	}
+	if got := textContent(t, result); !strings.HasPrefix(got, "## Course") || !strings.Contains(got, "Video 2: Storage") {
+		t.Fatalf("text content = %q, want summary followed by skipped videos", got)
+	}
+
+	output := structuredContent[mcpPlaylistSummaryOutput](t, result)
+	if output.Summary != "## Course" || output.Processed != 1 || output.Total != 2 || len(output.Skipped) != 1 {
+		t.Fatalf("structured output = %+v", output)
+	}
+	if app.playlistRequest.Transcript.Policy != tldw.TranscriptPolicyCaptionsOnly || app.playlistRequest.ConfirmWhisper != nil {
+		t.Fatalf("CreatePlaylistSummary() request = %+v, want captions only", app.playlistRequest)
 	}
 }
 
@@ -333,6 +548,99 @@ func TestMCPWhisperReturnsTextAndStructuredContent(t *testing.T) {
 	}
 }
 
+func TestMCPGetTranscriptPagesWithCursor(t *testing.T) {
+	app := &applicationStub{transcript: &tldw.Transcript{
+		VideoID: "dQw4w9WgXcQ",
+		Source:  tldw.TranscriptSourceCaptions,
+		Segments: []tldw.TranscriptSegment{
+			{Start: 0, End: 5, Text: "intro"},
+			{Start: 5, End: 10, Text: "first topic"},
+			{Start: 10, End: 15, Text: "second topic"},
+		},
+	}}
+	server := NewMCPServer(app)
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	call := func(arguments map[string]any) mcpTranscriptOutput {
+		t.Helper()
+		arguments["url"] = "https://youtu.be/dQw4w9WgXcQ"
+		result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{Name: "get_youtube_transcript", Arguments: arguments})
+		if err != nil {
+			t.Fatalf("CallTool() error = %v", err)
+		}
+		if result.IsError {
+			t.Fatalf("CallTool() returned tool error: %s", textContent(t, result))
+		}
+		return structuredContent[mcpTranscriptOutput](t, result)
+	}
+
+	first := call(map[string]any{"max_chars": 20})
+	if first.Transcript != "intro\nfirst topic" || first.NextCursor == "" || first.TotalChars != 30 {
+		t.Fatalf("first page = %+v", first)
+	}
+	if first.StartSeconds != 0 || first.EndSeconds != 10 {
+		t.Fatalf("first page covers %v-%v, want 0-10", first.StartSeconds, first.EndSeconds)
+	}
+	second := call(map[string]any{"max_chars": 20, "cursor": first.NextCursor})
+	if second.Transcript != "second topic" || second.NextCursor != "" || second.StartSeconds != 10 || second.EndSeconds != 15 {
+		t.Fatalf("second page = %+v", second)
+	}
+
+	window := call(map[string]any{"start_seconds": 5, "end_seconds": 10, "include_timestamps": true})
+	if window.Transcript != "[00:05] first topic" {
+		t.Fatalf("time window = %q", window.Transcript)
+	}
+	if !app.lastRequest.RequireTimestamps {
+		t.Fatal("time window did not require timestamped captions")
+	}
+}
+
+func TestMCPGetTranscriptRejectsCursorForAnotherVideo(t *testing.T) {
+	app := &applicationStub{transcript: &tldw.Transcript{Source: tldw.TranscriptSourceCaptions, Text: "words"}}
+	server := NewMCPServer(app)
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name: "get_youtube_transcript",
+		Arguments: map[string]any{
+			"url":    "https://youtu.be/dQw4w9WgXcQ",
+			"cursor": encodeTranscriptCursor("jNQXAC9IVRw", 3),
+		},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !result.IsError || !strings.Contains(textContent(t, result), "another video") {
+		t.Fatalf("CallTool() = %+v, want cursor error", result)
+	}
+	if app.transcriptCalls != 0 {
+		t.Fatalf("Transcript() calls = %d, want cursor rejected first", app.transcriptCalls)
+	}
+}
+
+func TestMCPWhisperRejectsTimeWindowBeforeDownload(t *testing.T) {
+	app := &applicationStub{}
+	server := NewMCPServer(app)
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name: "transcribe_youtube_whisper",
+		Arguments: map[string]any{
+			"url":           "https://youtu.be/dQw4w9WgXcQ",
+			"start_seconds": 60,
+		},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("CallTool() succeeded, want time window error")
+	}
+	if app.transcriptCalls != 0 {
+		t.Fatalf("Transcript() calls = %d, want rejection before paid work", app.transcriptCalls)
+	}
+}
+
 func TestMCPWhisperRejectsTimestampsBeforeDownload(t *testing.T) {
 	app := &applicationStub{}
 
@@ -360,6 +668,154 @@ func TestMCPWhisperRejectsTimestampsBeforeDownload(t *testing.T) {
 	}
 }
 
+func TestMCPSummarizeReturnsMarkdownAndStructuredContent(t *testing.T) {
+	app := &applicationStub{summary: tldw.Summary{
+		Markdown: "## Summary", Model: "gpt-5-mini", TranscriptSource: tldw.TranscriptSourceWhisper,
+	}}
+
+	server := NewMCPServer(app)
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name: "summarize_youtube_video",
+		Arguments: map[string]any{
+			"url":           "https://youtu.be/dQw4w9WgXcQ",
+			"prompt":        "Three bullets: {{.Transcript}}",
+			"model":         "gpt-5-mini",
+			"allow_whisper": true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("CallTool() returned tool error: %s", textContent(t, result))
+	}
+	if got := textContent(t, result); got != "## Summary" {
+		t.Fatalf("text content = %q, want the summary Markdown", got)
+	}
+
+	output := structuredContent[mcpSummaryOutput](t, result)
+	want := mcpSummaryOutput{
+		URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", Summary: "## Summary",
+		Model: "gpt-5-mini", TranscriptSource: string(tldw.TranscriptSourceWhisper),
+	}
+	if output != want {
+		t.Errorf("structured output = %+v, want %+v", output, want)
+	}
+	wantRequest := tldw.SummaryRequest{
+		Transcript: tldw.TranscriptRequest{Policy: tldw.TranscriptPolicyCaptionsThenWhisper},
+		Prompt:     "Three bullets: {{.Transcript}}",
+		Model:      "gpt-5-mini",
+	}
+	if app.summaryRequest != wantRequest {
+		t.Fatalf("SummarizeVideo() request = %+v, want %+v", app.summaryRequest, wantRequest)
+	}
+}
+
+func TestMCPSummarizeForwardsProgressWithToken(t *testing.T) {
+	app := &applicationStub{
+		summary: tldw.Summary{Markdown: "## Summary"},
+		progress: []tldw.Progress{
+			{Stage: tldw.ProgressStageTranscribing, Message: "Transcribed chunk 1 of 2", Current: 1, Total: 2},
+			{Stage: tldw.ProgressStageSummarizing, Message: "Summarizing with gpt-5-mini"},
+		},
+	}
+	notifications := make(chan *mcp.ProgressNotificationParams, len(app.progress))
+	server := NewMCPServer(app)
+	ctx, clientSession := connectTestMCPClientWithOptions(t, server, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, request *mcp.ProgressNotificationClientRequest) {
+			notifications <- request.Params
+		},
+	})
+
+	params := &mcp.CallToolParams{
+		Name:      "summarize_youtube_video",
+		Arguments: map[string]any{"url": "https://youtu.be/dQw4w9WgXcQ"},
+	}
+	params.SetProgressToken("summary-1")
+	if _, err := clientSession.CallTool(ctx, params); err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+
+	for i, want := range app.progress {
+		select {
+		case got := <-notifications:
+			if got.ProgressToken != "summary-1" || got.Progress != float64(i+1) || got.Message != want.Message {
+				t.Fatalf("progress notification %d = %+v, want token summary-1, progress %d, message %q", i, got, i+1, want.Message)
+			}
+		case <-ctx.Done():
+			t.Fatalf("waiting for progress notification %d: %v", i, ctx.Err())
+		}
+	}
+}
+
+func TestMCPSummarizeSkipsProgressWithoutToken(t *testing.T) {
+	app := &applicationStub{
+		summary:  tldw.Summary{Markdown: "## Summary"},
+		progress: []tldw.Progress{{Stage: tldw.ProgressStageSummarizing, Message: "Summarizing"}},
+	}
+	notified := make(chan struct{}, 1)
+	server := NewMCPServer(app)
+	ctx, clientSession := connectTestMCPClientWithOptions(t, server, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(context.Context, *mcp.ProgressNotificationClientRequest) {
+			notified <- struct{}{}
+		},
+	})
+
+	if _, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "summarize_youtube_video",
+		Arguments: map[string]any{"url": "https://youtu.be/dQw4w9WgXcQ"},
+	}); err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	select {
+	case <-notified:
+		t.Fatal("progress notification sent without a progress token")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMCPSummarizeUsesCaptionsOnlyUnlessWhisperIsAllowed(t *testing.T) {
+	app := &applicationStub{summaryErr: fmt.Errorf("%w for dQw4w9WgXcQ", tldw.ErrCaptionsUnavailable)}
+	server := NewMCPServer(app)
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "summarize_youtube_video",
+		Arguments: map[string]any{"url": "https://youtu.be/dQw4w9WgXcQ"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !result.IsError || !strings.Contains(textContent(t, result), "allow_whisper") {
+		t.Fatalf("CallTool() result = %+v, want error suggesting allow_whisper", result)
+	}
+	if app.summaryRequest.Transcript.Policy != tldw.TranscriptPolicyCaptionsOnly {
+		t.Fatalf("SummarizeVideo() policy = %v, want captions only", app.summaryRequest.Transcript.Policy)
+	}
+}
+
+func TestMCPSummarizeRejectsUnknownModelBeforeSummarizing(t *testing.T) {
+	app := &applicationStub{}
+	server := NewMCPServer(app)
+	ctx, clientSession := connectTestMCPClient(t, server)
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "summarize_youtube_video",
+		Arguments: map[string]any{"url": "https://youtu.be/dQw4w9WgXcQ", "model": "Not A Model"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("CallTool() succeeded with an invalid model")
+	}
+	if app.summaryCalls != 0 {
+		t.Fatalf("SummarizeVideo() was called %d times, want 0", app.summaryCalls)
+	}
+}
+
 func TestMCPTranscriptDoesNotMislabelApplicationFailures(t *testing.T) {
 	app := &applicationStub{transcriptErr: context.Canceled}
 	server := NewMCPServer(app)
@@ -423,8 +879,8 @@ func TestMCPHTTPTransportServesTools(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ListTools() error = %v", err)
 	}
-	if len(res.Tools) != 3 {
-		t.Fatalf("tool count over HTTP = %d, want 3", len(res.Tools))
+	if len(res.Tools) != 8 {
+		t.Fatalf("tool count over HTTP = %d, want 8", len(res.Tools))
 	}
 }
 
@@ -494,6 +950,11 @@ func unusedTCPPort(t *testing.T) int {
 
 func connectTestMCPClient(t *testing.T, server *MCPServer) (context.Context, *mcp.ClientSession) {
 	t.Helper()
+	return connectTestMCPClientWithOptions(t, server, nil)
+}
+
+func connectTestMCPClientWithOptions(t *testing.T, server *MCPServer, options *mcp.ClientOptions) (context.Context, *mcp.ClientSession) {
+	t.Helper()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	t.Cleanup(cancel)
@@ -505,7 +966,7 @@ func connectTestMCPClient(t *testing.T, server *MCPServer) (context.Context, *mc
 	}
 	t.Cleanup(func() { _ = serverSession.Close() })
 
-	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v0.0.1"}, nil)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v0.0.1"}, options)
 	clientSession, err := client.Connect(ctx, clientTransport, nil)
 	if err != nil {
 		t.Fatalf("client Connect() error = %v", err)
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistryWritesPrometheusText(t *testing.T) {
+	registry := NewRegistry()
+	calls := registry.Counter("test_calls_total", "Calls by tool.", "tool")
+	registry.Counter("test_minutes_total", "Minutes.")
+	duration := registry.Histogram("test_duration_seconds", "Call duration.", []float64{1, 0.5}, "tool")
+
+	calls.Inc("search")
+	calls.Add(2, `say "hi"`)
+	calls.Add(-1, "search")
+	duration.Observe(0.25, "search")
+	duration.Observe(0.75, "search")
+	duration.Observe(3, "search")
+
+	var out strings.Builder
+	if err := registry.WriteText(&out); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	want := `# HELP test_calls_total Calls by tool.
+# TYPE test_calls_total counter
+test_calls_total{tool="say \"hi\""} 2
+test_calls_total{tool="search"} 1
+# HELP test_minutes_total Minutes.
+# TYPE test_minutes_total counter
+test_minutes_total 0
+# HELP test_duration_seconds Call duration.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{tool="search",le="0.5"} 1
+test_duration_seconds_bucket{tool="search",le="1"} 2
+test_duration_seconds_bucket{tool="search",le="+Inf"} 3
+test_duration_seconds_sum{tool="search"} 4
+test_duration_seconds_count{tool="search"} 3
+`
+	if out.String() != want {
+		t.Fatalf("WriteText() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestRegistryRejectsDuplicateNames(t *testing.T) {
+	registry := NewRegistry()
+	registry.Counter("test_total", "Test.")
+	defer func() {
+		if recover() == nil {
+			t.Fatal("registering a name twice did not panic")
+		}
+	}()
+	registry.Counter("test_total", "Test.")
+}
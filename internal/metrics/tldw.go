@@ -0,0 +1,42 @@
+package metrics
+
+// Default is the registry served at /metrics by the MCP HTTP transport.
+var Default = NewRegistry()
+
+// durationBuckets spans quick cache reads to long Whisper transcriptions, in
+// seconds.
+var durationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600}
+
+var (
+	ToolCalls = Default.Counter("tldw_mcp_tool_calls_total",
+		"MCP tool calls by tool and outcome (ok, error, or input_required).", "tool", "outcome")
+	ToolCallDuration = Default.Histogram("tldw_mcp_tool_call_duration_seconds",
+		"Duration of MCP tool calls.", durationBuckets, "tool")
+	YtDLPInvocations = Default.Counter("tldw_ytdlp_invocations_total",
+		"yt-dlp runs by operation and outcome (ok or error).", "operation", "outcome")
+	YtDLPDuration = Default.Histogram("tldw_ytdlp_duration_seconds",
+		"Duration of yt-dlp runs.", durationBuckets, "operation")
+	CacheLookups = Default.Counter("tldw_cache_lookups_total",
+		"Transcript, metadata, and audio cache lookups by result (hit or miss).", "cache", "result")
+	WhisperMinutes = Default.Counter("tldw_whisper_audio_minutes_total",
+		"Minutes of audio transcribed with Whisper.")
+	SummaryTokens = Default.Counter("tldw_summary_tokens_total",
+		"OpenAI tokens used for summaries by model and kind (prompt or completion).", "model", "kind")
+)
+
+// CacheLookup counts a lookup in the named cache.
+func CacheLookup(cache string, hit bool) {
+	if hit {
+		CacheLookups.Inc(cache, "hit")
+		return
+	}
+	CacheLookups.Inc(cache, "miss")
+}
+
+// Outcome labels a finished operation.
+func Outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
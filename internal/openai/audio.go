@@ -8,8 +8,10 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/rtzll/tldw/internal/process"
+	"github.com/rtzll/tldw/internal/tldw"
 )
 
 // Audio handles audio file operations using FFmpeg
@@ -66,8 +68,10 @@ func (a *Audio) Split(ctx context.Context, audioFile string, numChunks int) ([]s
 		start := i * chunkDuration
 		output := filepath.Join(a.tempDir, fmt.Sprintf("%s_chunk_%d.mp3", filepath.Base(audioFile), i))
 
-		if err := a.Chunk(ctx, audioFile, start, chunkDuration, output); err != nil {
-			cleanupFiles(chunks...)
+		report := reportSplit(ctx, float64(start), duration)
+		if err := a.chunk(ctx, audioFile, start, chunkDuration, output, report); err != nil {
+			// ffmpeg may have left a partial chunk behind, e.g. when cancelled.
+			cleanupFiles(append(chunks, output)...)
 			return nil, fmt.Errorf("creating chunk %d: %w", i, err)
 		}
 		chunks = append(chunks, output)
@@ -82,10 +86,33 @@ func cleanupFiles(paths ...string) {
 	}
 }
 
+// reportSplit reports a chunk's ffmpeg progress as progress through the whole
+// file, since chunks are cut one after another.
+func reportSplit(ctx context.Context, start, total float64) func(process.Progress) {
+	return func(progress process.Progress) {
+		if total <= 0 {
+			return
+		}
+		done := min(start+float64(progress.Current)/1e6, total)
+		tldw.ReportProgress(ctx, tldw.Progress{
+			Stage:   tldw.ProgressStageAudio,
+			Message: fmt.Sprintf("Splitting audio (%d%%)", int(100*done/total)),
+			Current: int(done),
+			Total:   int(total),
+		})
+	}
+}
+
 // Chunk extracts a segment from an audio file
 func (a *Audio) Chunk(ctx context.Context, audioFile string, start, duration int, output string) error {
-	cmdOutput, err := a.cmdRunner.Run(ctx, "ffmpeg",
+	return a.chunk(ctx, audioFile, start, duration, output, nil)
+}
+
+func (a *Audio) chunk(ctx context.Context, audioFile string, start, duration int, output string, report func(process.Progress)) error {
+	parse := process.FFmpegProgressParser(time.Duration(duration) * time.Second)
+	cmdOutput, err := process.Stream(ctx, a.cmdRunner, parse, report, "ffmpeg",
 		"-v", "quiet",
+		"-nostats", "-progress", "pipe:1", // Report progress on stdout
 		"-i", audioFile,
 		"-ss", strconv.Itoa(start),
 		"-t", strconv.Itoa(duration),
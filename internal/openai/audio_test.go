@@ -3,6 +3,8 @@ package openai
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -104,3 +106,41 @@ func TestAudioSplitDurationError(t *testing.T) {
 		t.Error("Audio.Split() expected error when duration fails")
 	}
 }
+
+// failingChunkRunner writes part of every chunk and fails the second one, like
+// ffmpeg killed mid-write.
+type failingChunkRunner struct{ chunks int }
+
+func (r *failingChunkRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	if name == "ffprobe" {
+		return []byte("90.0\n"), nil
+	}
+	r.chunks++
+	if err := os.WriteFile(args[len(args)-1], []byte("partial"), 0o644); err != nil {
+		return nil, err
+	}
+	if r.chunks == 2 {
+		return nil, context.Canceled
+	}
+	return nil, nil
+}
+
+func TestAudioSplitRemovesChunksOnFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	a := NewAudio(&failingChunkRunner{}, tmpDir, false)
+
+	if _, err := a.Split(context.Background(), "input.mp3", 3); err == nil {
+		t.Fatal("Audio.Split() expected error when a chunk fails")
+	}
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("reading temp dir: %v", err)
+	}
+	var left []string
+	for _, entry := range entries {
+		left = append(left, entry.Name())
+	}
+	if len(left) != 0 {
+		t.Fatalf("Audio.Split() left %s behind", strings.Join(left, ", "))
+	}
+}
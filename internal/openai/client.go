@@ -10,6 +10,7 @@ import (
 
 	openaisdk "github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/option"
+	"github.com/rtzll/tldw/internal/metrics"
 	"github.com/rtzll/tldw/internal/tldw"
 )
 
@@ -54,6 +55,8 @@ func (c *sdkClient) CreateChatCompletion(ctx context.Context, model, prompt stri
 	if err != nil {
 		return "", err
 	}
+	metrics.SummaryTokens.Add(float64(resp.Usage.PromptTokens), model, "prompt")
+	metrics.SummaryTokens.Add(float64(resp.Usage.CompletionTokens), model, "completion")
 	if len(resp.Choices) == 0 {
 		return "", fmt.Errorf("no response choices from OpenAI")
 	}
@@ -183,6 +186,9 @@ func (ai *AI) Transcribe(ctx context.Context, audioFile string) (string, error)
 	if err != nil {
 		return "", fmt.Errorf("transcribing audio: %w", err)
 	}
+	if seconds, err := ai.audio.Duration(ctx, audioFile); err == nil {
+		metrics.WhisperMinutes.Add(seconds / 60)
+	}
 	return transcript, nil
 }
 
@@ -216,13 +222,28 @@ func (ai *AI) processAudioChunks(ctx context.Context, chunks []string) (string,
 		if ai.verbose && !ai.quiet {
 			ai.log.Printf("Transcribed chunk %d/%d\n", i+1, numChunks)
 		}
+		tldw.ReportProgress(ctx, tldw.Progress{
+			Stage:   tldw.ProgressStageTranscribing,
+			Message: fmt.Sprintf("Transcribed chunk %d of %d", i+1, numChunks),
+			Current: i + 1,
+			Total:   numChunks,
+		})
 	}
 
 	return sb.String(), nil
 }
 
-// Summary creates an AI summary using a prepared prompt
-func (ai *AI) Summary(ctx context.Context, prompt string) (string, error) {
+// SummaryModel returns the configured summary model.
+func (ai *AI) SummaryModel() string {
+	return ai.model
+}
+
+// Summary creates an AI summary using a prepared prompt. An empty model
+// selects the configured one.
+func (ai *AI) Summary(ctx context.Context, model, prompt string) (string, error) {
+	if strings.TrimSpace(model) == "" {
+		model = ai.model
+	}
 	if err := ai.ensureClient(); err != nil {
 		return "", err
 	}
@@ -233,7 +254,7 @@ func (ai *AI) Summary(ctx context.Context, prompt string) (string, error) {
 		defer cancel()
 	}
 
-	content, err := ai.client.CreateChatCompletion(ctx, ai.model, prompt)
+	content, err := ai.client.CreateChatCompletion(ctx, model, prompt)
 	if err != nil {
 		return "", fmt.Errorf("creating chat completion: %w", err)
 	}
@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
+
+	"github.com/rtzll/tldw/internal/tldw"
 )
 
 const WhisperLimit int64 = 25 << 20
@@ -18,6 +21,7 @@ type mockOpenAIClient struct {
 	err            error
 	checkContext   bool
 	transcriptions int
+	models         []string
 }
 
 func TestNewAIRejectsInvalidConfiguration(t *testing.T) {
@@ -52,6 +56,7 @@ func (m *mockOpenAIClient) CreateTranscription(ctx context.Context, file *os.Fil
 }
 
 func (m *mockOpenAIClient) CreateChatCompletion(ctx context.Context, model, prompt string) (string, error) {
+	m.models = append(m.models, model)
 	if m.checkContext && ctx.Err() != nil {
 		return "", ctx.Err()
 	}
@@ -65,7 +70,7 @@ func TestAIWithKeyRequiresKeyWhenUsed(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewAIWithKey() error = %v", err)
 	}
-	if _, err := ai.Summary(context.Background(), "prompt"); err == nil {
+	if _, err := ai.Summary(context.Background(), "", "prompt"); err == nil {
 		t.Fatal("Summary() succeeded without an API key")
 	}
 }
@@ -106,6 +111,36 @@ func TestAITranscribeSplitsLargeAudio(t *testing.T) {
 	}
 }
 
+func TestAITranscribeReportsChunkProgress(t *testing.T) {
+	tempDir := t.TempDir()
+	input := filepath.Join(tempDir, "audio.mp3")
+	if err := os.WriteFile(input, []byte("four"), 0o644); err != nil {
+		t.Fatalf("writing audio input: %v", err)
+	}
+	ai, err := NewAIWithKey("test-key", NewAudio(chunkingRunner{}, tempDir, false), Config{
+		Model: "gpt-5.4-mini", WhisperLimit: 2,
+	})
+	if err != nil {
+		t.Fatalf("NewAIWithKey() error = %v", err)
+	}
+	ai.client = &mockOpenAIClient{transcription: "chunk transcript"}
+
+	var events []tldw.Progress
+	ctx := tldw.WithProgress(context.Background(), func(progress tldw.Progress) {
+		events = append(events, progress)
+	})
+	if _, err := ai.Transcribe(ctx, input); err != nil {
+		t.Fatalf("Transcribe() error = %v", err)
+	}
+	want := []tldw.Progress{
+		{Stage: tldw.ProgressStageTranscribing, Message: "Transcribed chunk 1 of 2", Current: 1, Total: 2},
+		{Stage: tldw.ProgressStageTranscribing, Message: "Transcribed chunk 2 of 2", Current: 2, Total: 2},
+	}
+	if !slices.Equal(events, want) {
+		t.Fatalf("progress = %+v, want %+v", events, want)
+	}
+}
+
 func TestAITranscribeReturnsClientError(t *testing.T) {
 	input := filepath.Join(t.TempDir(), "audio.mp3")
 	if err := os.WriteFile(input, []byte("audio"), 0o644); err != nil {
@@ -134,13 +169,19 @@ func TestAISummary(t *testing.T) {
 	}
 	ai.client = client
 
-	got, err := ai.Summary(context.Background(), "prompt")
+	got, err := ai.Summary(context.Background(), "", "prompt")
 	if err != nil {
 		t.Fatalf("Summary() error = %v", err)
 	}
 	if got != "A summary" {
 		t.Errorf("Summary() = %q, want %q", got, "A summary")
 	}
+	if _, err := ai.Summary(context.Background(), "gpt-5-mini", "prompt"); err != nil {
+		t.Fatalf("Summary() error = %v", err)
+	}
+	if want := []string{"gpt-5.4-mini", "gpt-5-mini"}; !slices.Equal(client.models, want) {
+		t.Errorf("Summary() models = %v, want %v", client.models, want)
+	}
 }
 
 func TestAITranscribePreservesCallerAudioFile(t *testing.T) {
@@ -178,7 +219,7 @@ func TestAISummaryError(t *testing.T) {
 	}
 	ai.client = client
 
-	_, err = ai.Summary(context.Background(), "prompt")
+	_, err = ai.Summary(context.Background(), "", "prompt")
 	if err == nil {
 		t.Error("Summary() expected error")
 	}
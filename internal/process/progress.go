@@ -0,0 +1,188 @@
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProgressKind says what a Progress event counts.
+type ProgressKind int
+
+const (
+	// ProgressDownload counts downloaded bytes.
+	ProgressDownload ProgressKind = iota
+	// ProgressMedia counts microseconds of processed media, as ffmpeg does.
+	ProgressMedia
+)
+
+// Progress is one update parsed from a running command's output.
+type Progress struct {
+	Kind ProgressKind
+	// Current is how far the command got. Total is zero when unknown.
+	Current int64
+	Total   int64
+	// Done marks the command's final update.
+	Done bool
+}
+
+// Percent returns the whole percentage of Total that is done, or zero when
+// Total is unknown.
+func (p Progress) Percent() int {
+	if p.Total <= 0 {
+		return 0
+	}
+	return int(min(100, max(0, p.Current*100/p.Total)))
+}
+
+// ProgressParser reads one line of a command's stdout. ok reports whether the
+// line was progress output; other lines are kept in the command's output.
+type ProgressParser func(line string) (progress Progress, ok bool)
+
+// StreamRunner runs a command while reporting the progress it prints, instead
+// of staying silent until the command exits.
+type StreamRunner interface {
+	Stream(ctx context.Context, parse ProgressParser, report func(Progress), name string, args ...string) ([]byte, error)
+}
+
+// Stream runs the command through runner's Stream method when it has one.
+// Other runners, such as test fakes, run the command with Run and report the
+// progress found in its output afterwards. Either way the returned output
+// excludes progress lines.
+func Stream(ctx context.Context, runner Runner, parse ProgressParser, report func(Progress), name string, args ...string) ([]byte, error) {
+	if streamer, ok := runner.(StreamRunner); ok {
+		return streamer.Stream(ctx, parse, report, name, args...)
+	}
+	output, err := runner.Run(ctx, name, args...)
+	return scanProgress(bytes.NewReader(output), parse, report), err
+}
+
+// Stream executes a command like Run, parsing its stdout line by line as it
+// is written. report is called when progress reaches another whole percent,
+// when the total changes, and on the final update.
+func (*CommandRunner) Stream(ctx context.Context, parse ProgressParser, report func(Progress), name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	killProcessGroupOnCancel(cmd)
+	cmd.WaitDelay = cancelWaitDelay
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, commandError(ctx, name, args, "", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, commandError(ctx, name, args, stderr.String(), err)
+	}
+	output := scanProgress(stdout, parse, report)
+	if err := cmd.Wait(); err != nil {
+		return output, commandError(ctx, name, args, stderr.String(), err)
+	}
+	return output, nil
+}
+
+// scanProgress reports the progress lines read from r and returns the rest.
+func scanProgress(r io.Reader, parse ProgressParser, report func(Progress)) []byte {
+	var output bytes.Buffer
+	var last Progress
+	reported := false
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if progress, ok := parse(strings.TrimRight(line, "\r\n")); ok {
+				if report != nil && (!reported || progressChanged(last, progress)) {
+					report(progress)
+					last, reported = progress, true
+				}
+			} else {
+				output.WriteString(line)
+			}
+		}
+		if err != nil {
+			return output.Bytes()
+		}
+	}
+}
+
+// progressChanged limits reports to whole percentages, so that listeners such
+// as MCP progress notifications are not flooded by fast downloads.
+func progressChanged(last, next Progress) bool {
+	if next.Done != last.Done || next.Kind != last.Kind {
+		return true
+	}
+	if next.Total > 0 && last.Total > 0 {
+		return next.Percent() != last.Percent()
+	}
+	return next != last
+}
+
+// ytdlpProgressPrefix marks the lines printed by YtDLPProgressTemplate.
+const ytdlpProgressPrefix = "[tldw-progress]"
+
+// YtDLPProgressTemplate makes yt-dlp print one line per download update that
+// ParseYtDLPProgress understands. Pass it with --newline:
+//
+//	yt-dlp --newline --progress-template <YtDLPProgressTemplate> ...
+const YtDLPProgressTemplate = "download:" + ytdlpProgressPrefix +
+	" %(progress.status)s %(progress.downloaded_bytes)s %(progress.total_bytes)s %(progress.total_bytes_estimate)s"
+
+// ParseYtDLPProgress parses the lines printed by YtDLPProgressTemplate.
+// yt-dlp prints NA for unknown fields; without an exact size the total falls
+// back to yt-dlp's estimate.
+func ParseYtDLPProgress(line string) (Progress, bool) {
+	fields := strings.Fields(line)
+	if len(fields) != 5 || fields[0] != ytdlpProgressPrefix {
+		return Progress{}, false
+	}
+	total := parseProgressNumber(fields[3])
+	if total == 0 {
+		total = parseProgressNumber(fields[4])
+	}
+	return Progress{
+		Kind:    ProgressDownload,
+		Current: parseProgressNumber(fields[2]),
+		Total:   total,
+		Done:    fields[1] == "finished",
+	}, true
+}
+
+// FFmpegProgressParser parses the key=value lines ffmpeg writes with
+// -progress pipe:1 for an output of the given duration. The parser keeps the
+// latest position, so it must not be shared between commands.
+func FFmpegProgressParser(duration time.Duration) ProgressParser {
+	progress := Progress{Kind: ProgressMedia, Total: duration.Microseconds()}
+	return func(line string) (Progress, bool) {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return Progress{}, false
+		}
+		switch key {
+		case "out_time_us":
+			if current := parseProgressNumber(value); current > 0 {
+				progress.Current = current
+			}
+		case "progress":
+			if value == "end" {
+				progress.Done = true
+				progress.Current = max(progress.Current, progress.Total)
+			}
+		}
+		return progress, true
+	}
+}
+
+// parseProgressNumber parses an integer or float field, returning zero for
+// unknown values such as NA and N/A.
+func parseProgressNumber(value string) int64 {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || !(number >= 0) || math.IsInf(number, 1) {
+		return 0
+	}
+	return int64(number)
+}
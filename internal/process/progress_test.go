@@ -0,0 +1,112 @@
+package process_test
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	processadapter "github.com/rtzll/tldw/internal/process"
+)
+
+func TestParseYtDLPProgress(t *testing.T) {
+	tests := map[string]struct {
+		want processadapter.Progress
+		ok   bool
+	}{
+		"[tldw-progress] downloading 1024 4096 NA":      {processadapter.Progress{Current: 1024, Total: 4096}, true},
+		"[tldw-progress] downloading 1024 NA 8192.5":    {processadapter.Progress{Current: 1024, Total: 8192}, true},
+		"[tldw-progress] finished 4096 4096 NA":         {processadapter.Progress{Current: 4096, Total: 4096, Done: true}, true},
+		"[download] Destination: dQw4w9WgXcQ.webm":      {},
+		"[tldw-progress] downloading 1024 4096 NA more": {},
+	}
+	for line, tt := range tests {
+		got, ok := processadapter.ParseYtDLPProgress(line)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseYtDLPProgress(%q) = %+v, %v, want %+v, %v", line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFFmpegProgressParserTracksPosition(t *testing.T) {
+	parse := processadapter.FFmpegProgressParser(10 * time.Second)
+	var last processadapter.Progress
+	for _, line := range []string{"out_time_us=N/A", "out_time_us=2500000", "speed=40x", "progress=continue", "progress=end"} {
+		progress, ok := parse(line)
+		if !ok {
+			t.Fatalf("parse(%q) ok = false, want ffmpeg progress", line)
+		}
+		if line == "progress=continue" && progress.Percent() != 25 {
+			t.Fatalf("Percent() = %d after 2.5s of 10s, want 25", progress.Percent())
+		}
+		last = progress
+	}
+	if !last.Done || last.Percent() != 100 {
+		t.Fatalf("final progress = %+v, want done at 100%%", last)
+	}
+	if _, ok := parse("Input #0, mp3, from 'audio.mp3':"); ok {
+		t.Fatal("parse() accepted a line that is not progress")
+	}
+}
+
+// outputRunner returns fixed output, like a runner that cannot stream.
+type outputRunner struct{ output string }
+
+func (r outputRunner) Run(context.Context, string, ...string) ([]byte, error) {
+	return []byte(r.output), nil
+}
+
+func TestStreamReportsWholePercentagesAndKeepsOtherOutput(t *testing.T) {
+	runner := outputRunner{output: "[youtube] Extracting URL\n" +
+		"[tldw-progress] downloading 0 1000 NA\n" +
+		"[tldw-progress] downloading 3 1000 NA\n" +
+		"[tldw-progress] downloading 12 1000 NA\n" +
+		"[tldw-progress] downloading 500 1000 NA\n" +
+		"[tldw-progress] finished 1000 1000 NA\n" +
+		"[ExtractAudio] Destination: audio.mp3\n"}
+
+	var percents []int
+	output, err := processadapter.Stream(context.Background(), runner, processadapter.ParseYtDLPProgress,
+		func(progress processadapter.Progress) { percents = append(percents, progress.Percent()) }, "yt-dlp")
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if want := []int{0, 1, 50, 100}; !slices.Equal(percents, want) {
+		t.Fatalf("reported percents = %v, want %v", percents, want)
+	}
+	if want := "[youtube] Extracting URL\n[ExtractAudio] Destination: audio.mp3\n"; string(output) != want {
+		t.Fatalf("Stream() output = %q, want %q", output, want)
+	}
+}
+
+func TestCommandRunnerStreamsProgress(t *testing.T) {
+	t.Setenv("GO_WANT_PROCESS_HELPER", "1")
+
+	var reported []processadapter.Progress
+	output, err := (&processadapter.CommandRunner{}).Stream(context.Background(), processadapter.ParseYtDLPProgress,
+		func(progress processadapter.Progress) { reported = append(reported, progress) },
+		os.Args[0], "-test.run=TestProcessHelper", "--", "progress")
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if string(output) != "[download] Destination: audio.webm\n" {
+		t.Fatalf("Stream() output = %q, want only the non-progress line", output)
+	}
+	if len(reported) != 5 || !reported[len(reported)-1].Done {
+		t.Fatalf("reported = %+v, want four downloads and the finished update", reported)
+	}
+}
+
+func TestRetryRunnerStreamsThroughNextRunner(t *testing.T) {
+	runner := processadapter.NewRetryRunner(outputRunner{output: "[tldw-progress] finished 10 10 NA\n"}, fastRetryPolicy())
+
+	var reported []processadapter.Progress
+	if _, err := runner.Stream(context.Background(), processadapter.ParseYtDLPProgress,
+		func(progress processadapter.Progress) { reported = append(reported, progress) }, "yt-dlp"); err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if len(reported) != 1 || !reported[0].Done {
+		t.Fatalf("reported = %+v, want the finished update", reported)
+	}
+}
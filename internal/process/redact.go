@@ -0,0 +1,57 @@
+package process
+
+import (
+	"errors"
+	"strings"
+)
+
+// Redacted replaces secret values in command arguments, output, and errors.
+const Redacted = "[REDACTED]"
+
+// Redactor hides secrets, such as a proxy password, that were passed to a
+// command and may be echoed back in its output.
+type Redactor struct {
+	secrets []string
+}
+
+// NewRedactor returns a Redactor for the non-empty secrets.
+func NewRedactor(secrets ...string) Redactor {
+	var kept []string
+	for _, secret := range secrets {
+		if secret != "" {
+			kept = append(kept, secret)
+		}
+	}
+	return Redactor{secrets: kept}
+}
+
+// String returns text with every secret replaced.
+func (r Redactor) String(text string) string {
+	for _, secret := range r.secrets {
+		text = strings.ReplaceAll(text, secret, Redacted)
+	}
+	return text
+}
+
+// Output returns command output with every secret replaced.
+func (r Redactor) Output(output []byte) []byte {
+	if len(r.secrets) == 0 {
+		return output
+	}
+	return []byte(r.String(string(output)))
+}
+
+// Error redacts the arguments and stderr of the CommandError in err's chain.
+// The CommandError is updated in place, so wrapping errors report the
+// redacted values too.
+func (r Redactor) Error(err error) error {
+	var commandErr *CommandError
+	if len(r.secrets) == 0 || !errors.As(err, &commandErr) {
+		return err
+	}
+	for i, arg := range commandErr.Args {
+		commandErr.Args[i] = r.String(arg)
+	}
+	commandErr.Stderr = r.String(commandErr.Stderr)
+	return err
+}
@@ -0,0 +1,230 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FailureClass says whether a failed command is worth running again.
+type FailureClass int
+
+const (
+	// FailurePermanent failures, such as a private video or a missing
+	// executable, fail the same way when run again.
+	FailurePermanent FailureClass = iota
+	// FailureTransient failures come from the network or an overloaded
+	// server and usually pass on a later attempt.
+	FailureTransient
+	// FailureRateLimited failures were refused by the host for sending too
+	// many requests; they back off longer than transient failures.
+	FailureRateLimited
+)
+
+func (c FailureClass) String() string {
+	switch c {
+	case FailureTransient:
+		return "transient"
+	case FailureRateLimited:
+		return "rate limited"
+	default:
+		return "permanent"
+	}
+}
+
+// ErrRateLimited and ErrTransient mark the errors RetryRunner returns once it
+// gives up on a command that kept failing for that reason.
+var (
+	ErrRateLimited = errors.New("rate limited")
+	ErrTransient   = errors.New("transient failure")
+)
+
+var (
+	rateLimitMarkers = []string{"http error 429", "too many requests", "rate-limit", "rate limit"}
+	transientMarkers = []string{
+		"timed out", "timeout", "connection reset", "connection refused", "connection aborted",
+		"network is unreachable", "temporary failure in name resolution", "remote end closed connection",
+		"incompleteread", "http error 500", "http error 502", "http error 503", "http error 504",
+	}
+)
+
+// Classify reads the failure class from a command's error and stderr.
+// Cancellation is permanent, so a cancelled request is never run again.
+func Classify(err error) FailureClass {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return FailurePermanent
+	}
+	message := strings.ToLower(err.Error())
+	for _, marker := range rateLimitMarkers {
+		if strings.Contains(message, marker) {
+			return FailureRateLimited
+		}
+	}
+	for _, marker := range transientMarkers {
+		if strings.Contains(message, marker) {
+			return FailureTransient
+		}
+	}
+	return FailurePermanent
+}
+
+// RetryPolicy configures RetryRunner.
+type RetryPolicy struct {
+	// MaxAttempts bounds the runs of one command, including the first. One
+	// or less never retries.
+	MaxAttempts int
+	// BaseDelay is the back-off before the first retry of a transient
+	// failure; it doubles with each further retry.
+	BaseDelay time.Duration
+	// RateLimitDelay replaces BaseDelay after a rate-limited failure.
+	RateLimitDelay time.Duration
+	// MaxDelay caps every back-off.
+	MaxDelay time.Duration
+	// MaxPerHost bounds how many commands for the same host run at once.
+	// Commands without a URL argument, such as ffmpeg, are not limited.
+	// Zero means no limit.
+	MaxPerHost int
+}
+
+// Delay returns the back-off before retry number attempt (starting at 1)
+// after a failure of class. The delay doubles per attempt up to MaxDelay, and
+// a random half of it is dropped so that concurrent retries spread out.
+func (p RetryPolicy) Delay(class FailureClass, attempt int) time.Duration {
+	delay := p.BaseDelay
+	if class == FailureRateLimited && p.RateLimitDelay > 0 {
+		delay = p.RateLimitDelay
+	}
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// RetryRunner runs commands through another Runner, running failed commands
+// again by policy. All commands run through one RetryRunner share its
+// per-host concurrency limit.
+type RetryRunner struct {
+	next   Runner
+	policy RetryPolicy
+
+	mu    sync.Mutex
+	hosts map[string]chan struct{}
+}
+
+// NewRetryRunner wraps next with policy.
+func NewRetryRunner(next Runner, policy RetryPolicy) *RetryRunner {
+	return &RetryRunner{next: next, policy: policy, hosts: make(map[string]chan struct{})}
+}
+
+// Run runs the command until it succeeds, fails permanently, or runs out of
+// attempts. After retrying, the error wraps ErrRateLimited or ErrTransient
+// along with the last failure.
+func (r *RetryRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return r.retry(ctx, args, func() ([]byte, error) {
+		return r.next.Run(ctx, name, args...)
+	})
+}
+
+// Stream streams the command's progress like Run retries it. Retried
+// attempts report their progress from the start again.
+func (r *RetryRunner) Stream(ctx context.Context, parse ProgressParser, report func(Progress), name string, args ...string) ([]byte, error) {
+	return r.retry(ctx, args, func() ([]byte, error) {
+		return Stream(ctx, r.next, parse, report, name, args...)
+	})
+}
+
+func (r *RetryRunner) retry(ctx context.Context, args []string, run func() ([]byte, error)) ([]byte, error) {
+	attempts := max(r.policy.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		output, err := r.runLimited(ctx, args, run)
+		if err == nil {
+			return output, nil
+		}
+		class := Classify(err)
+		if class == FailurePermanent || ctx.Err() != nil {
+			return output, err
+		}
+		if attempt >= attempts {
+			return output, retryError(class, attempt, err)
+		}
+		if waitErr := sleep(ctx, r.policy.Delay(class, attempt)); waitErr != nil {
+			return output, errors.Join(err, waitErr)
+		}
+	}
+}
+
+func retryError(class FailureClass, attempts int, err error) error {
+	marker := ErrTransient
+	if class == FailureRateLimited {
+		marker = ErrRateLimited
+	}
+	if attempts == 1 {
+		return fmt.Errorf("%w: %w", marker, err)
+	}
+	return fmt.Errorf("%w after %d attempts: %w", marker, attempts, err)
+}
+
+// runLimited holds a slot for the command's host while it runs, but not
+// while it backs off.
+func (r *RetryRunner) runLimited(ctx context.Context, args []string, run func() ([]byte, error)) ([]byte, error) {
+	slots := r.hostSlots(commandHost(args))
+	if slots != nil {
+		select {
+		case slots <- struct{}{}:
+			defer func() { <-slots }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return run()
+}
+
+func (r *RetryRunner) hostSlots(host string) chan struct{} {
+	if host == "" || r.policy.MaxPerHost <= 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	slots, ok := r.hosts[host]
+	if !ok {
+		slots = make(chan struct{}, r.policy.MaxPerHost)
+		r.hosts[host] = slots
+	}
+	return slots
+}
+
+// commandHost returns the host of the first http or https URL argument,
+// without a leading www.
+func commandHost(args []string) string {
+	for _, arg := range args {
+		parsed, err := url.Parse(arg)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			continue
+		}
+		return strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	}
+	return ""
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,171 @@
+package process_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	processadapter "github.com/rtzll/tldw/internal/process"
+)
+
+// scriptedRunner fails with the scripted stderr lines in order, then
+// succeeds.
+type scriptedRunner struct {
+	mu       sync.Mutex
+	failures []string
+	calls    int
+}
+
+func (r *scriptedRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	if len(r.failures) == 0 {
+		return []byte("done"), nil
+	}
+	stderr := r.failures[0]
+	r.failures = r.failures[1:]
+	return nil, &processadapter.CommandError{Name: name, Args: args, Stderr: stderr, Err: errors.New("exit status 1")}
+}
+
+func fastRetryPolicy() processadapter.RetryPolicy {
+	return processadapter.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, RateLimitDelay: 2 * time.Millisecond, MaxDelay: 5 * time.Millisecond}
+}
+
+func TestClassifyReadsCommandErrors(t *testing.T) {
+	tests := map[string]processadapter.FailureClass{
+		"ERROR: unable to download video data: HTTP Error 429: Too Many Requests":   processadapter.FailureRateLimited,
+		"ERROR: Unable to download webpage: The read operation timed out":           processadapter.FailureTransient,
+		"ERROR: unable to download video data: HTTP Error 503: Service Unavailable": processadapter.FailureTransient,
+		"ERROR: [youtube] dQw4w9WgXcQ: Private video":                               processadapter.FailurePermanent,
+	}
+	for stderr, want := range tests {
+		err := &processadapter.CommandError{Name: "yt-dlp", Stderr: stderr, Err: errors.New("exit status 1")}
+		if got := processadapter.Classify(err); got != want {
+			t.Errorf("Classify(%q) = %v, want %v", stderr, got, want)
+		}
+	}
+	if got := processadapter.Classify(errors.Join(errors.New("timed out"), context.Canceled)); got != processadapter.FailurePermanent {
+		t.Errorf("Classify(cancelled) = %v, want permanent", got)
+	}
+}
+
+func TestRetryRunnerRetriesTransientFailures(t *testing.T) {
+	next := &scriptedRunner{failures: []string{"Connection reset by peer", "HTTP Error 429: Too Many Requests"}}
+	runner := processadapter.NewRetryRunner(next, fastRetryPolicy())
+
+	output, err := runner.Run(context.Background(), "yt-dlp", "https://www.youtube.com/watch?v=dQw4w9WgXcQ")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if string(output) != "done" || next.calls != 3 {
+		t.Fatalf("Run() = %q after %d calls, want done after 3", output, next.calls)
+	}
+}
+
+func TestRetryRunnerGivesUpAfterMaxAttempts(t *testing.T) {
+	next := &scriptedRunner{failures: []string{"HTTP Error 429", "HTTP Error 429", "HTTP Error 429", "HTTP Error 429"}}
+	runner := processadapter.NewRetryRunner(next, fastRetryPolicy())
+
+	_, err := runner.Run(context.Background(), "yt-dlp", "https://www.youtube.com/watch?v=dQw4w9WgXcQ")
+	if !errors.Is(err, processadapter.ErrRateLimited) {
+		t.Fatalf("Run() error = %v, want ErrRateLimited", err)
+	}
+	var commandErr *processadapter.CommandError
+	if !errors.As(err, &commandErr) {
+		t.Fatalf("Run() error = %v, want the last CommandError", err)
+	}
+	if next.calls != 3 {
+		t.Fatalf("calls = %d, want 3", next.calls)
+	}
+}
+
+func TestRetryRunnerReturnsPermanentFailuresAtOnce(t *testing.T) {
+	next := &scriptedRunner{failures: []string{"Private video"}}
+	runner := processadapter.NewRetryRunner(next, fastRetryPolicy())
+
+	_, err := runner.Run(context.Background(), "yt-dlp", "https://www.youtube.com/watch?v=dQw4w9WgXcQ")
+	if err == nil || errors.Is(err, processadapter.ErrTransient) || next.calls != 1 {
+		t.Fatalf("Run() error = %v after %d calls, want the failure after 1 call", err, next.calls)
+	}
+}
+
+func TestRetryRunnerStopsBackingOffWhenCancelled(t *testing.T) {
+	next := &scriptedRunner{failures: []string{"timed out", "timed out"}}
+	policy := fastRetryPolicy()
+	policy.BaseDelay = time.Hour
+	policy.MaxDelay = time.Hour
+	runner := processadapter.NewRetryRunner(next, policy)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := runner.Run(ctx, "yt-dlp", "https://www.youtube.com/watch?v=dQw4w9WgXcQ")
+	if !errors.Is(err, context.DeadlineExceeded) || next.calls != 1 {
+		t.Fatalf("Run() error = %v after %d calls, want the deadline after 1 call", err, next.calls)
+	}
+}
+
+func TestRetryPolicyDelayDoublesWithJitterUpToMax(t *testing.T) {
+	policy := processadapter.RetryPolicy{BaseDelay: time.Second, RateLimitDelay: 10 * time.Second, MaxDelay: 30 * time.Second}
+	tests := []struct {
+		class   processadapter.FailureClass
+		attempt int
+		full    time.Duration
+	}{
+		{processadapter.FailureTransient, 1, time.Second},
+		{processadapter.FailureTransient, 3, 4 * time.Second},
+		{processadapter.FailureRateLimited, 1, 10 * time.Second},
+		{processadapter.FailureRateLimited, 3, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			got := policy.Delay(tt.class, tt.attempt)
+			if got < tt.full/2 || got > tt.full {
+				t.Fatalf("Delay(%v, %d) = %v, want between %v and %v", tt.class, tt.attempt, got, tt.full/2, tt.full)
+			}
+		}
+	}
+}
+
+// concurrencyRunner records how many commands run at once.
+type concurrencyRunner struct {
+	mu      sync.Mutex
+	running int
+	peak    int
+}
+
+func (r *concurrencyRunner) Run(context.Context, string, ...string) ([]byte, error) {
+	r.mu.Lock()
+	r.running++
+	r.peak = max(r.peak, r.running)
+	r.mu.Unlock()
+	time.Sleep(10 * time.Millisecond)
+	r.mu.Lock()
+	r.running--
+	r.mu.Unlock()
+	return nil, nil
+}
+
+func TestRetryRunnerLimitsConcurrencyPerHost(t *testing.T) {
+	for name, tt := range map[string]struct {
+		args []string
+		want int
+	}{
+		"same host":   {args: []string{"-q", "https://www.youtube.com/watch?v=dQw4w9WgXcQ"}, want: 2},
+		"without URL": {args: []string{"-i", "audio.mp3"}, want: 6},
+	} {
+		next := &concurrencyRunner{}
+		runner := processadapter.NewRetryRunner(next, processadapter.RetryPolicy{MaxPerHost: 2})
+
+		var wg sync.WaitGroup
+		for range 6 {
+			wg.Go(func() { _, _ = runner.Run(context.Background(), "cmd", tt.args...) })
+		}
+		wg.Wait()
+		if next.peak > tt.want || (tt.want == 2 && next.peak != 2) {
+			t.Errorf("%s: peak concurrency = %d, want %d", name, next.peak, tt.want)
+		}
+	}
+}
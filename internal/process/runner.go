@@ -8,8 +8,13 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+	"time"
 )
 
+// cancelWaitDelay bounds how long Run waits for output pipes after the
+// process is killed, in case a descendant outlived the process group kill.
+const cancelWaitDelay = 5 * time.Second
+
 type Runner interface {
 	Run(ctx context.Context, name string, args ...string) ([]byte, error)
 }
@@ -35,8 +40,12 @@ func (err *CommandError) Error() string {
 
 func (err *CommandError) Unwrap() error { return err.Err }
 
+// Run executes a command and returns its stdout. Cancelling ctx kills the
+// command together with any children it started, such as yt-dlp's ffmpeg.
 func (*CommandRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
 	cmd := exec.CommandContext(ctx, name, args...)
+	killProcessGroupOnCancel(cmd)
+	cmd.WaitDelay = cancelWaitDelay
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package process
+
+import "os/exec"
+
+// killProcessGroupOnCancel keeps exec's default of killing only the direct
+// child where process groups are unavailable.
+func killProcessGroupOnCancel(*exec.Cmd) {}
@@ -61,9 +61,25 @@ func TestProcessHelper(t *testing.T) {
 		case "fail":
 			fmt.Fprintln(os.Stderr, "deliberate failure")
 			os.Exit(3)
+		case "progress":
+			fmt.Println("[download] Destination: audio.webm")
+			for _, downloaded := range []int{0, 250, 500, 1000} {
+				fmt.Printf("[tldw-progress] downloading %d 1000 NA\n", downloaded)
+			}
+			fmt.Println("[tldw-progress] finished 1000 1000 NA")
+			os.Exit(0)
 		case "block":
 			time.Sleep(5 * time.Second)
 			os.Exit(0)
+		case "spawn":
+			// Like yt-dlp starting ffmpeg: the child shares our stdout.
+			child := exec.Command(os.Args[0], "-test.run=TestProcessHelper", "--", "block")
+			child.Stdout = os.Stdout
+			if err := child.Start(); err != nil {
+				os.Exit(4)
+			}
+			_ = child.Wait()
+			os.Exit(0)
 		}
 	}
 	os.Exit(2)
@@ -0,0 +1,32 @@
+//go:build unix
+
+package process_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	processadapter "github.com/rtzll/tldw/internal/process"
+)
+
+func TestCommandRunnerCancellationKillsChildProcesses(t *testing.T) {
+	t.Setenv("GO_WANT_PROCESS_HELPER", "1")
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	_, err := (&processadapter.CommandRunner{}).Run(ctx, os.Args[0], "-test.run=TestProcessHelper", "--", "spawn")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() error = %v, want context.Canceled", err)
+	}
+	// An orphaned grandchild would hold stdout open until it exits.
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("Run() returned after %v, want the grandchild killed with its parent", elapsed)
+	}
+}
@@ -7,40 +7,61 @@ import (
 	"github.com/schollz/progressbar/v3"
 )
 
-// Spinner is the status surface used while generating summaries.
-type Spinner interface {
+// ProgressBar is the status surface used while fetching transcripts and
+// generating summaries. It spins until Set reports a known total.
+type ProgressBar interface {
 	Describe(description string)
+	// Set shows current out of total. A total of zero switches back to the
+	// spinner.
+	Set(current, total int64)
 	Finish()
 }
 
-// NewSpinner creates a terminal spinner, or a no-op spinner when output is
-// redirected and terminal animation would corrupt piped output.
-func NewSpinner(description string) Spinner {
-	if !isatty.IsTerminal(os.Stdout.Fd()) {
-		return NoOpSpinner{}
+// NewProgressBar creates a terminal progress bar on stderr, or a no-op bar
+// when stderr is redirected and terminal animation would corrupt the log.
+func NewProgressBar(description string) ProgressBar {
+	if !isatty.IsTerminal(os.Stderr.Fd()) {
+		return NoOpProgressBar{}
 	}
 	bar := progressbar.NewOptions(-1,
+		progressbar.OptionSetWriter(os.Stderr),
 		progressbar.OptionSetDescription(description),
 		progressbar.OptionSpinnerType(11),
 		progressbar.OptionSetWidth(30),
 		progressbar.OptionClearOnFinish(),
 	)
-	return terminalSpinner{bar: bar}
+	return &terminalProgressBar{bar: bar, total: -1}
 }
 
-type terminalSpinner struct {
-	bar *progressbar.ProgressBar
+type terminalProgressBar struct {
+	bar   *progressbar.ProgressBar
+	total int64
 }
 
-func (spinner terminalSpinner) Describe(description string) {
-	spinner.bar.Describe(description)
+func (b *terminalProgressBar) Describe(description string) {
+	b.bar.Describe(description)
 }
 
-func (spinner terminalSpinner) Finish() {
-	_ = spinner.bar.Finish()
+func (b *terminalProgressBar) Set(current, total int64) {
+	if total <= 0 {
+		total = -1
+	}
+	if total != b.total {
+		b.bar.Reset()
+		b.bar.ChangeMax64(total)
+		b.total = total
+	}
+	if total > 0 {
+		_ = b.bar.Set64(min(current, total))
+	}
+}
+
+func (b *terminalProgressBar) Finish() {
+	_ = b.bar.Finish()
 }
 
-type NoOpSpinner struct{}
+type NoOpProgressBar struct{}
 
-func (NoOpSpinner) Describe(string) {}
-func (NoOpSpinner) Finish()         {}
+func (NoOpProgressBar) Describe(string)  {}
+func (NoOpProgressBar) Set(int64, int64) {}
+func (NoOpProgressBar) Finish()          {}
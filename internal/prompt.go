@@ -2,14 +2,33 @@ package internal
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 	"text/template"
 
 	"github.com/rtzll/tldw/internal/tldw"
 )
 
+// PromptsDirName is the config subdirectory holding additional named prompt
+// templates, one per .txt file.
+const PromptsDirName = "prompts"
+
+var promptNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// PromptTemplate is a named template from the prompts directory.
+type PromptTemplate struct {
+	Name     string
+	Template string
+	// Err is set when the file cannot be read or does not parse; such a
+	// template must not be served.
+	Err error
+}
+
 type promptData struct {
 	Title       string
 	Channel     string
@@ -65,6 +84,53 @@ func (pm *PromptManager) CreatePrompt(transcript string, metadata *tldw.VideoMet
 		tmplContent = string(content)
 	}
 
+	return renderPrompt(tmplContent, transcript, metadata)
+}
+
+// Templates lists the templates in the prompts directory sorted by name. A
+// file's name without the .txt extension names its template; files whose
+// names are not lowercase identifiers are skipped. A template that cannot be
+// read or parsed is listed with its Err set, so one broken file does not hide
+// the others. A missing directory holds no templates.
+func (pm *PromptManager) Templates() ([]PromptTemplate, error) {
+	dir := filepath.Join(pm.configDir, PromptsDirName)
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading prompts directory: %w", err)
+	}
+	var templates []PromptTemplate
+	for _, file := range files {
+		name, ok := strings.CutSuffix(file.Name(), ".txt")
+		if !ok || file.IsDir() || !promptNamePattern.MatchString(name) {
+			continue
+		}
+		templates = append(templates, loadPromptTemplate(dir, file.Name(), name))
+	}
+	slices.SortFunc(templates, func(a, b PromptTemplate) int { return strings.Compare(a.Name, b.Name) })
+	return templates, nil
+}
+
+func loadPromptTemplate(dir, fileName, name string) PromptTemplate {
+	content, err := os.ReadFile(filepath.Join(dir, fileName))
+	if err != nil {
+		return PromptTemplate{Name: name, Err: fmt.Errorf("reading prompt template %s: %w", name, err)}
+	}
+	if _, err := template.New(name).Parse(string(content)); err != nil {
+		return PromptTemplate{Name: name, Err: fmt.Errorf("parsing prompt template %s: %w", name, err)}
+	}
+	return PromptTemplate{Name: name, Template: string(content)}
+}
+
+// CreateCustomPrompt builds a prompt from a template given as text, ignoring
+// the configured prompt. The template is never treated as a file path.
+func (pm *PromptManager) CreateCustomPrompt(tmplContent, transcript string, metadata *tldw.VideoMetadata) (string, error) {
+	return renderPrompt(tmplContent, transcript, metadata)
+}
+
+func renderPrompt(tmplContent, transcript string, metadata *tldw.VideoMetadata) (string, error) {
 	tmpl, err := template.New("prompt").Parse(tmplContent)
 	if err != nil {
 		return "", fmt.Errorf("parsing prompt template: %w", err)
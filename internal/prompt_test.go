@@ -71,6 +71,18 @@ func TestPromptManagerCreatePrompt(t *testing.T) {
 		}
 	})
 
+	t.Run("custom template is not a path", func(t *testing.T) {
+		promptPath := filepath.Join(tmpDir, "prompt.txt")
+		pm := NewPromptManager(tmpDir, "Configured: {{.Transcript}}")
+		got, err := pm.CreateCustomPrompt(promptPath, "Hello world", nil)
+		if err != nil {
+			t.Fatalf("CreateCustomPrompt() error = %v", err)
+		}
+		if got != promptPath {
+			t.Errorf("CreateCustomPrompt() = %q, want the template text %q", got, promptPath)
+		}
+	})
+
 	t.Run("missing file", func(t *testing.T) {
 		pm := NewPromptManager(t.TempDir(), "")
 		_, err := pm.CreatePrompt("Hello", nil)
@@ -86,3 +98,54 @@ func TestPromptManagerCreatePrompt(t *testing.T) {
 		}
 	})
 }
+
+func TestPromptManagerTemplates(t *testing.T) {
+	configDir := t.TempDir()
+	pm := NewPromptManager(configDir, "")
+	templates, err := pm.Templates()
+	if err != nil || len(templates) != 0 {
+		t.Fatalf("Templates() without a prompts directory = %v, %v", templates, err)
+	}
+
+	dir := filepath.Join(configDir, PromptsDirName)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("creating prompts directory: %v", err)
+	}
+	files := map[string]string{
+		"study-notes.txt": "Notes: {{.Transcript}}",
+		"bullets.txt":     "Bullets: {{.Transcript}}",
+		"Bad Name.txt":    "skipped",
+		"readme.md":       "skipped",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+
+	templates, err = pm.Templates()
+	if err != nil {
+		t.Fatalf("Templates() error = %v", err)
+	}
+	if len(templates) != 2 || templates[0].Name != "bullets" || templates[1].Name != "study-notes" {
+		t.Fatalf("Templates() = %+v, want bullets and study-notes", templates)
+	}
+	got, err := pm.CreateCustomPrompt(templates[1].Template, "Hello world", nil)
+	if err != nil || got != "Notes: Hello world" {
+		t.Fatalf("CreateCustomPrompt() = %q, %v", got, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.txt"), []byte("{{.Transcript"), 0o644); err != nil {
+		t.Fatalf("writing broken template: %v", err)
+	}
+	templates, err = pm.Templates()
+	if err != nil {
+		t.Fatalf("Templates() with a broken template error = %v", err)
+	}
+	if len(templates) != 3 || templates[0].Name != "broken" || templates[0].Err == nil {
+		t.Fatalf("Templates() = %+v, want broken listed with its parse error", templates)
+	}
+	if templates[1].Err != nil || templates[2].Err != nil {
+		t.Fatalf("Templates() = %+v, want the other templates unaffected", templates)
+	}
+}
@@ -0,0 +1,328 @@
+package store
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+// Library archives are gzip-compressed tarballs holding a manifest followed by
+// the entry files exactly as the store writes them, so metadata keeps its
+// first-seen time and files keep their modification times.
+const (
+	archiveFormat       = "tldw-library"
+	archiveVersion      = 1
+	archiveManifestName = "manifest.json"
+	maxArchiveFileSize  = 64 << 20
+	// maxArchiveSize caps the files of an archive together, since they are
+	// held in memory until the whole archive is validated.
+	maxArchiveSize = 1 << 30
+)
+
+type archiveManifest struct {
+	Format     string         `json:"format"`
+	Version    int            `json:"version"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Entries    []archiveEntry `json:"entries"`
+}
+
+type archiveEntry struct {
+	VideoID     string        `json:"video_id"`
+	Title       string        `json:"title,omitempty"`
+	Channel     string        `json:"channel,omitempty"`
+	FirstSeenAt time.Time     `json:"first_seen_at"`
+	CachedAt    time.Time     `json:"cached_at"`
+	Files       []archiveFile `json:"files"`
+}
+
+type archiveFile struct {
+	Name       string    `json:"name"`
+	Size       int64     `json:"size"`
+	SHA256     string    `json:"sha256"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// ExportEntries writes every file of the given videos and a manifest
+// describing them to a library archive.
+func (s *File) ExportEntries(archive io.Writer, videoIDs []string) error {
+	manifest := archiveManifest{Format: archiveFormat, Version: archiveVersion, ExportedAt: time.Now()}
+	contents := make(map[string][]byte)
+	for _, videoID := range videoIDs {
+		entry, err := s.LoadEntry(videoID)
+		if err != nil {
+			return fmt.Errorf("loading %s: %w", videoID, err)
+		}
+		exported := archiveEntry{
+			VideoID: videoID, Title: entry.Title(), Channel: entry.Channel(),
+			FirstSeenAt: entry.FirstSeenAt, CachedAt: entry.CachedAt,
+		}
+		for _, file := range entry.Files {
+			path := filepath.Join(s.dir, file.Name)
+			data, err := os.ReadFile(filepath.Clean(path))
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", file.Name, err)
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				return fmt.Errorf("inspecting %s: %w", file.Name, err)
+			}
+			contents[file.Name] = data
+			exported.Files = append(exported.Files, archiveFile{
+				Name: file.Name, Size: int64(len(data)), SHA256: sha256Hex(data), ModifiedAt: info.ModTime(),
+			})
+		}
+		manifest.Entries = append(manifest.Entries, exported)
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling manifest: %w", err)
+	}
+
+	compressed := gzip.NewWriter(archive)
+	tarball := tar.NewWriter(compressed)
+	if err := writeArchiveFile(tarball, archiveManifestName, manifestData, manifest.ExportedAt); err != nil {
+		return err
+	}
+	for _, entry := range manifest.Entries {
+		for _, file := range entry.Files {
+			if err := writeArchiveFile(tarball, file.Name, contents[file.Name], file.ModifiedAt); err != nil {
+				return err
+			}
+		}
+	}
+	if err := tarball.Close(); err != nil {
+		return fmt.Errorf("finishing archive: %w", err)
+	}
+	if err := compressed.Close(); err != nil {
+		return fmt.Errorf("compressing archive: %w", err)
+	}
+	return nil
+}
+
+// ImportEntries validates a library archive and merges its videos into the
+// store. Nothing is written unless the whole archive matches its manifest.
+// Failures on individual videos are joined and returned after the rest have
+// been imported.
+func (s *File) ImportEntries(archive io.Reader, policy tldw.ImportPolicy) ([]tldw.LibraryImport, error) {
+	manifest, contents, err := readArchive(archive)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating transcript store: %w", err)
+	}
+	imports := make([]tldw.LibraryImport, 0, len(manifest.Entries))
+	var errs []error
+	for _, entry := range manifest.Entries {
+		outcome := s.importOutcome(entry, policy)
+		if outcome != tldw.ImportSkipped {
+			if err := s.replaceEntry(entry, contents); err != nil {
+				errs = append(errs, fmt.Errorf("importing %s: %w", entry.VideoID, err))
+				continue
+			}
+		}
+		imports = append(imports, tldw.LibraryImport{VideoID: entry.VideoID, Outcome: outcome})
+	}
+	return imports, errors.Join(errs...)
+}
+
+func (s *File) importOutcome(entry archiveEntry, policy tldw.ImportPolicy) tldw.ImportOutcome {
+	local, err := s.LoadEntry(entry.VideoID)
+	switch {
+	case errors.Is(err, tldw.ErrStoreNotFound):
+		return tldw.ImportAdded
+	case policy == tldw.ImportKeepLocal:
+		return tldw.ImportSkipped
+	case policy == tldw.ImportKeepNewest && err == nil && !entry.CachedAt.After(local.CachedAt):
+		return tldw.ImportSkipped
+	default:
+		// Unreadable local entries are always replaced.
+		return tldw.ImportReplaced
+	}
+}
+
+// replaceEntry writes every file of entry to a temporary file before any of
+// them is renamed into place, so a failed write leaves the local entry as it
+// was. Local files the archive does not have are removed last.
+func (s *File) replaceEntry(entry archiveEntry, contents map[string][]byte) error {
+	staged := make([]string, 0, len(entry.Files))
+	defer func() {
+		for _, tempPath := range staged {
+			_ = os.Remove(tempPath)
+		}
+	}()
+	for _, file := range entry.Files {
+		tempPath, err := writeTempFile(s.dir, contents[file.Name], 0o644)
+		if err != nil {
+			return fmt.Errorf("writing %s: %w", file.Name, err)
+		}
+		staged = append(staged, tempPath)
+		if err := os.Chtimes(tempPath, file.ModifiedAt, file.ModifiedAt); err != nil {
+			return fmt.Errorf("restoring time of %s: %w", file.Name, err)
+		}
+	}
+
+	imported := make(map[string]struct{}, len(entry.Files))
+	for i, file := range entry.Files {
+		if err := os.Rename(staged[i], filepath.Join(s.dir, file.Name)); err != nil {
+			return fmt.Errorf("replacing %s: %w", file.Name, err)
+		}
+		imported[file.Name] = struct{}{}
+	}
+	staged = nil
+
+	for _, suffix := range entrySuffixes {
+		path, err := s.cachePath(entry.VideoID, suffix)
+		if err != nil {
+			return err
+		}
+		if _, ok := imported[filepath.Base(path)]; ok {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing %s: %w", filepath.Base(path), err)
+		}
+	}
+	return s.updateCatalog(entry.VideoID)
+}
+
+func readArchive(archive io.Reader) (archiveManifest, map[string][]byte, error) {
+	var manifest archiveManifest
+	compressed, err := gzip.NewReader(archive)
+	if err != nil {
+		return manifest, nil, fmt.Errorf("reading archive: %w", err)
+	}
+	defer func() { _ = compressed.Close() }()
+
+	tarball := tar.NewReader(compressed)
+	contents := make(map[string][]byte)
+	var manifestData []byte
+	var total int64
+	for {
+		header, err := tarball.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return manifest, nil, fmt.Errorf("reading archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			return manifest, nil, fmt.Errorf("archive entry %q is not a regular file", header.Name)
+		}
+		if header.Size < 0 || header.Size > maxArchiveFileSize {
+			return manifest, nil, fmt.Errorf("archive entry %q is too large", header.Name)
+		}
+		if total += header.Size; total > maxArchiveSize {
+			return manifest, nil, fmt.Errorf("archive is larger than %d MiB", maxArchiveSize>>20)
+		}
+		data, err := io.ReadAll(io.LimitReader(tarball, header.Size))
+		if err != nil {
+			return manifest, nil, fmt.Errorf("reading %s: %w", header.Name, err)
+		}
+		if header.Name == archiveManifestName {
+			manifestData = data
+			continue
+		}
+		if _, ok := entryFileVideoID(header.Name); !ok {
+			return manifest, nil, fmt.Errorf("archive entry %q is not a library file", header.Name)
+		}
+		if _, ok := contents[header.Name]; ok {
+			return manifest, nil, fmt.Errorf("archive contains %s more than once", header.Name)
+		}
+		contents[header.Name] = data
+	}
+	if manifestData == nil {
+		return manifest, nil, fmt.Errorf("archive has no %s", archiveManifestName)
+	}
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return manifest, nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+	if manifest.Format != archiveFormat || manifest.Version != archiveVersion {
+		return manifest, nil, fmt.Errorf("unsupported archive format %q version %d", manifest.Format, manifest.Version)
+	}
+	if err := validateArchive(manifest, contents); err != nil {
+		return manifest, nil, err
+	}
+	return manifest, contents, nil
+}
+
+func validateArchive(manifest archiveManifest, contents map[string][]byte) error {
+	seenVideos := make(map[string]struct{}, len(manifest.Entries))
+	listed := make(map[string]struct{}, len(contents))
+	for _, entry := range manifest.Entries {
+		if !tldw.IsValidVideoID(entry.VideoID) {
+			return fmt.Errorf("manifest lists invalid YouTube video ID: %q", entry.VideoID)
+		}
+		if _, ok := seenVideos[entry.VideoID]; ok {
+			return fmt.Errorf("manifest lists %s more than once", entry.VideoID)
+		}
+		seenVideos[entry.VideoID] = struct{}{}
+		for _, file := range entry.Files {
+			if videoID, ok := entryFileVideoID(file.Name); !ok || videoID != entry.VideoID {
+				return fmt.Errorf("manifest lists %s under %s", file.Name, entry.VideoID)
+			}
+			data, ok := contents[file.Name]
+			if !ok {
+				return fmt.Errorf("archive is missing %s", file.Name)
+			}
+			if int64(len(data)) != file.Size || sha256Hex(data) != file.SHA256 {
+				return fmt.Errorf("%s does not match its checksum", file.Name)
+			}
+			if err := validateArchiveContent(file.Name, data); err != nil {
+				return err
+			}
+			listed[file.Name] = struct{}{}
+		}
+	}
+	for name := range contents {
+		if _, ok := listed[name]; !ok {
+			return fmt.Errorf("archive entry %s is not in the manifest", name)
+		}
+	}
+	return nil
+}
+
+func validateArchiveContent(name string, data []byte) error {
+	var err error
+	switch {
+	case strings.HasSuffix(name, ".meta.json"):
+		var cached cachedMetadata
+		err = json.Unmarshal(data, &cached)
+	case strings.HasSuffix(name, ".transcript.json"):
+		var transcript tldw.Transcript
+		err = json.Unmarshal(data, &transcript)
+	}
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", name, err)
+	}
+	return nil
+}
+
+func writeArchiveFile(tarball *tar.Writer, name string, data []byte, modifiedAt time.Time) error {
+	header := &tar.Header{
+		Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: modifiedAt, Typeflag: tar.TypeReg,
+	}
+	if err := tarball.WriteHeader(header); err != nil {
+		return fmt.Errorf("writing %s to archive: %w", name, err)
+	}
+	if _, err := tarball.Write(data); err != nil {
+		return fmt.Errorf("writing %s to archive: %w", name, err)
+	}
+	return nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
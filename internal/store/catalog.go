@@ -0,0 +1,362 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+// The catalog is an append-only JSON-lines index of every library entry. Each
+// save appends a full snapshot of the entry and each delete appends a
+// tombstone; the newest record for a video wins. Reads compact the file once
+// superseded records dominate, and a missing, outdated, or corrupt catalog is
+// rebuilt from the entry files. Listings read only the catalog, so they carry
+// the compact metadata below rather than descriptions and chapters. Every
+// catalog access holds catalog.lock, which other processes share.
+const (
+	catalogFileName     = "catalog.jsonl"
+	catalogLockFileName = "catalog.lock"
+	catalogVersion      = 3
+	catalogCompactSlack = 64
+)
+
+var errCatalogCorrupt = errors.New("catalog is corrupt")
+
+type catalogHeader struct {
+	CatalogVersion int `json:"catalog_version"`
+}
+
+type catalogRecord struct {
+	VideoID          string                `json:"id"`
+	Deleted          bool                  `json:"deleted,omitempty"`
+	HasMetadata      bool                  `json:"has_metadata,omitempty"`
+	Title            string                `json:"title,omitempty"`
+	Channel          string                `json:"channel,omitempty"`
+	ChannelURL       string                `json:"channel_url,omitempty"`
+	Creators         []string              `json:"creators,omitempty"`
+	PublishedAt      string                `json:"published_at,omitempty"`
+	Duration         float64               `json:"duration,omitempty"`
+	Language         string                `json:"language,omitempty"`
+	Categories       []string              `json:"categories,omitempty"`
+	Tags             []string              `json:"tags,omitempty"`
+	HasCaptions      bool                  `json:"has_captions,omitempty"`
+	CaptionLanguages []string              `json:"caption_languages,omitempty"`
+	HasTranscript    bool                  `json:"has_transcript,omitempty"`
+	HasTimestamps    bool                  `json:"has_timestamps,omitempty"`
+	HasSummary       bool                  `json:"has_summary,omitempty"`
+	TranscriptSource tldw.TranscriptSource `json:"transcript_source,omitempty"`
+	FirstSeenAt      time.Time             `json:"first_seen_at"`
+	CachedAt         time.Time             `json:"cached_at"`
+	SummarizedAt     time.Time             `json:"summarized_at,omitzero"`
+	Files            []tldw.LibraryFile    `json:"files,omitempty"`
+}
+
+func recordFromEntry(entry tldw.LibraryEntry) catalogRecord {
+	record := catalogRecord{
+		VideoID: entry.VideoID, HasTranscript: entry.HasTranscript, HasTimestamps: entry.HasTimestamps,
+		HasSummary: entry.HasSummary, TranscriptSource: entry.TranscriptSource,
+		FirstSeenAt: entry.FirstSeenAt, CachedAt: entry.CachedAt, SummarizedAt: entry.SummarizedAt,
+		Files: entry.Files,
+	}
+	if metadata := entry.Metadata; metadata != nil {
+		record.HasMetadata = true
+		record.Title, record.Channel, record.ChannelURL = metadata.Title, metadata.Channel, metadata.ChannelURL
+		record.Creators, record.PublishedAt, record.Duration = metadata.Creators, metadata.PublishedAt, metadata.Duration
+		record.Language, record.Categories, record.Tags = metadata.Language, metadata.Categories, metadata.Tags
+		record.HasCaptions, record.CaptionLanguages = metadata.HasCaptions, metadata.CaptionLanguages
+	}
+	return record
+}
+
+func (record catalogRecord) entry() tldw.LibraryEntry {
+	entry := tldw.LibraryEntry{
+		VideoID: record.VideoID, HasTranscript: record.HasTranscript, HasTimestamps: record.HasTimestamps,
+		HasSummary: record.HasSummary, TranscriptSource: record.TranscriptSource,
+		FirstSeenAt: record.FirstSeenAt, CachedAt: record.CachedAt, SummarizedAt: record.SummarizedAt,
+		Files: record.Files,
+	}
+	if record.HasMetadata {
+		entry.Metadata = &tldw.VideoMetadata{
+			Title: record.Title, Channel: record.Channel, ChannelURL: record.ChannelURL,
+			Creators: record.Creators, PublishedAt: record.PublishedAt, Duration: record.Duration,
+			Language: record.Language, Categories: record.Categories, Tags: record.Tags,
+			HasCaptions: record.HasCaptions, CaptionLanguages: record.CaptionLanguages,
+		}
+	}
+	return entry
+}
+
+func (s *File) catalogPath() string {
+	return filepath.Join(s.dir, catalogFileName)
+}
+
+// lockCatalog serializes catalog reads and writes with other goroutines and,
+// through a lock file, with other processes sharing the store, such as the
+// CLI, watch runs, and the MCP server. Without it a record appended by one
+// process could be lost when another compacts or rebuilds the catalog.
+func (s *File) lockCatalog() (func(), error) {
+	s.catalogMu.Lock()
+	unlock, err := lockFile(filepath.Join(s.dir, catalogLockFileName))
+	if err != nil {
+		s.catalogMu.Unlock()
+		return nil, fmt.Errorf("locking catalog: %w", err)
+	}
+	return func() {
+		unlock()
+		s.catalogMu.Unlock()
+	}, nil
+}
+
+// catalogEntries returns every live catalog entry sorted by video ID.
+func (s *File) catalogEntries() ([]tldw.LibraryEntry, error) {
+	unlock, err := s.lockCatalog()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	records, err := s.loadCatalog()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]tldw.LibraryEntry, 0, len(records))
+	for _, record := range records {
+		entries = append(entries, record.entry())
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].VideoID < entries[j].VideoID })
+	return entries, nil
+}
+
+// updateCatalog records the current on-disk state of one entry.
+func (s *File) updateCatalog(videoID string) error {
+	unlock, err := s.lockCatalog()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, err := os.Stat(s.catalogPath()); os.IsNotExist(err) {
+		_, err := s.rebuildCatalog()
+		return err
+	}
+	record := catalogRecord{VideoID: videoID, Deleted: true}
+	entry, err := s.LoadEntry(videoID)
+	if err == nil {
+		record = recordFromEntry(*entry)
+	} else if !errors.Is(err, tldw.ErrStoreNotFound) {
+		return fmt.Errorf("updating catalog: %w", err)
+	}
+	return s.appendCatalogRecord(record)
+}
+
+// RebuildCatalog discards the catalog and indexes every entry file again.
+func (s *File) RebuildCatalog() error {
+	unlock, err := s.lockCatalog()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	_, err = s.rebuildCatalog()
+	return err
+}
+
+// maxDriftedIDs bounds the video IDs named in a catalog drift issue.
+const maxDriftedIDs = 5
+
+// checkCatalog compares the catalog with the entry files without changing
+// either. Saves write the entry files before they append to the catalog, so a
+// failed append or a crash in between leaves a record that reads would keep
+// serving. A missing catalog is not reported, since the next read rebuilds it.
+func (s *File) checkCatalog(videoIDs []string) (*tldw.StoreIssue, error) {
+	unlock, err := s.lockCatalog()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	records, _, err := readCatalog(s.catalogPath())
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, nil
+	case errors.Is(err, errCatalogCorrupt):
+		return &tldw.StoreIssue{Kind: tldw.StoreIssueCatalogDrift, File: catalogFileName, Detail: err.Error()}, nil
+	case err != nil:
+		return nil, err
+	}
+
+	var drifted []string
+	readable := make(map[string]struct{}, len(videoIDs))
+	for _, videoID := range videoIDs {
+		// Unreadable entries are reported on their own and kept out of the
+		// catalog until they are repaired.
+		entry, err := s.LoadEntry(videoID)
+		if err != nil {
+			continue
+		}
+		readable[videoID] = struct{}{}
+		record, ok := records[videoID]
+		if !ok || !sameCatalogRecord(record, recordFromEntry(*entry)) {
+			drifted = append(drifted, videoID)
+		}
+	}
+	for videoID := range records {
+		if _, ok := readable[videoID]; !ok {
+			drifted = append(drifted, videoID)
+		}
+	}
+	if len(drifted) == 0 {
+		return nil, nil
+	}
+	sort.Strings(drifted)
+	named := drifted[:min(len(drifted), maxDriftedIDs)]
+	detail := fmt.Sprintf("%d %s out of date: %s", len(drifted), pluralEntries(len(drifted)), strings.Join(named, ", "))
+	if len(drifted) > len(named) {
+		detail += ", ..."
+	}
+	return &tldw.StoreIssue{Kind: tldw.StoreIssueCatalogDrift, File: catalogFileName, Detail: detail}, nil
+}
+
+// sameCatalogRecord compares records by their encoding, which is what the
+// catalog stores, so times compare equal after a round trip.
+func sameCatalogRecord(a, b catalogRecord) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
+
+func pluralEntries(count int) string {
+	if count == 1 {
+		return "entry"
+	}
+	return "entries"
+}
+
+func (s *File) loadCatalog() (map[string]catalogRecord, error) {
+	records, total, err := readCatalog(s.catalogPath())
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, errCatalogCorrupt) {
+		return s.rebuildCatalog()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if total > 2*len(records)+catalogCompactSlack {
+		if err := s.writeCatalog(records); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+func (s *File) rebuildCatalog() (map[string]catalogRecord, error) {
+	videoIDs, err := s.entryVideoIDs()
+	if err != nil {
+		return nil, err
+	}
+	records := make(map[string]catalogRecord, len(videoIDs))
+	for _, videoID := range videoIDs {
+		// Unreadable entries stay out of the catalog until they are repaired.
+		entry, err := s.LoadEntry(videoID)
+		if err != nil {
+			continue
+		}
+		records[videoID] = recordFromEntry(*entry)
+	}
+	if len(videoIDs) == 0 {
+		if _, err := os.Stat(s.dir); os.IsNotExist(err) {
+			return records, nil
+		}
+	}
+	if err := s.writeCatalog(records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (s *File) writeCatalog(records map[string]catalogRecord) error {
+	videoIDs := make([]string, 0, len(records))
+	for videoID := range records {
+		videoIDs = append(videoIDs, videoID)
+	}
+	sort.Strings(videoIDs)
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if err := encoder.Encode(catalogHeader{CatalogVersion: catalogVersion}); err != nil {
+		return fmt.Errorf("encoding catalog: %w", err)
+	}
+	for _, videoID := range videoIDs {
+		if err := encoder.Encode(records[videoID]); err != nil {
+			return fmt.Errorf("encoding catalog: %w", err)
+		}
+	}
+	if err := atomicWriteFile(s.catalogPath(), buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing catalog: %w", err)
+	}
+	return nil
+}
+
+func (s *File) appendCatalogRecord(record catalogRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("encoding catalog record: %w", err)
+	}
+	file, err := os.OpenFile(s.catalogPath(), os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening catalog: %w", err)
+	}
+	// One write per record keeps appends from concurrent processes whole.
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("appending to catalog: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("closing catalog: %w", err)
+	}
+	return nil
+}
+
+// readCatalog returns the live records and the number of records read.
+func readCatalog(path string) (map[string]catalogRecord, int, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading catalog: %w", err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	if !scanner.Scan() {
+		return nil, 0, fmt.Errorf("%w: missing header", errCatalogCorrupt)
+	}
+	var header catalogHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil || header.CatalogVersion != catalogVersion {
+		return nil, 0, fmt.Errorf("%w: unsupported header", errCatalogCorrupt)
+	}
+
+	records := make(map[string]catalogRecord)
+	total := 0
+	for scanner.Scan() {
+		var record catalogRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil || !tldw.IsValidVideoID(record.VideoID) {
+			return nil, 0, fmt.Errorf("%w: record %d", errCatalogCorrupt, total+1)
+		}
+		total++
+		if record.Deleted {
+			delete(records, record.VideoID)
+			continue
+		}
+		records[record.VideoID] = record
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", errCatalogCorrupt, err)
+	}
+	return records, total, nil
+}
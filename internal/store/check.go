@@ -0,0 +1,156 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+const tempFilePrefix = ".tldw-"
+
+// orphanedTempAge keeps checks from reporting temporary files that a
+// concurrent write may still rename into place.
+const orphanedTempAge = time.Hour
+
+// CheckWritable creates the store directory if needed and verifies that new
+// files can be written to it. A leftover probe file is reported by Check.
+func (s *File) CheckWritable() error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("creating transcript store: %w", err)
+	}
+	probe, err := os.CreateTemp(s.dir, tempFilePrefix+"probe-*")
+	if err != nil {
+		return fmt.Errorf("writing to transcript store: %w", err)
+	}
+	return errors.Join(probe.Close(), os.Remove(probe.Name()))
+}
+
+// Check reports orphaned temporary files, unparsable entry files, stale
+// metadata, transcripts without structured JSON, and a catalog that differs
+// from the entry files. It changes nothing.
+func (s *File) Check() ([]tldw.StoreIssue, error) {
+	dirEntries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading transcript store: %w", err)
+	}
+	var issues []tldw.StoreIssue
+	now := time.Now()
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || !strings.HasPrefix(dirEntry.Name(), tempFilePrefix) {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("inspecting %s: %w", dirEntry.Name(), err)
+		}
+		if age := now.Sub(info.ModTime()); age >= orphanedTempAge {
+			issues = append(issues, tldw.StoreIssue{
+				Kind: tldw.StoreIssueOrphanedTemp, File: dirEntry.Name(),
+				Detail: fmt.Sprintf("left over for %s", age.Truncate(time.Minute)),
+			})
+		}
+	}
+
+	videoIDs, err := s.entryVideoIDs()
+	if err != nil {
+		return nil, err
+	}
+	for _, videoID := range videoIDs {
+		entryIssues, err := s.checkEntry(videoID)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, entryIssues...)
+	}
+	catalogIssue, err := s.checkCatalog(videoIDs)
+	if err != nil {
+		return nil, err
+	}
+	if catalogIssue != nil {
+		issues = append(issues, *catalogIssue)
+	}
+	return issues, nil
+}
+
+func (s *File) checkEntry(videoID string) ([]tldw.StoreIssue, error) {
+	var issues []tldw.StoreIssue
+	metadataPath, err := s.cachePath(videoID, ".meta.json")
+	if err != nil {
+		return nil, err
+	}
+	cached, err := readCachedMetadata(metadataPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		issues = append(issues, tldw.StoreIssue{
+			Kind: tldw.StoreIssueCorruptMetadata, VideoID: videoID, File: filepath.Base(metadataPath), Detail: err.Error(),
+		})
+	case cached.CacheVersion < metadataCacheVersion:
+		issues = append(issues, tldw.StoreIssue{
+			Kind: tldw.StoreIssueStaleMetadata, VideoID: videoID, File: filepath.Base(metadataPath),
+			Detail: fmt.Sprintf("cache version %d, current is %d", cached.CacheVersion, metadataCacheVersion),
+		})
+	}
+
+	_, err = s.loadStructuredTranscript(videoID)
+	switch {
+	case err == nil:
+	case errors.Is(err, tldw.ErrStoreNotFound):
+		plainPath, _ := s.cachePath(videoID, ".txt")
+		if _, statErr := os.Stat(plainPath); statErr == nil {
+			issues = append(issues, tldw.StoreIssue{
+				Kind: tldw.StoreIssueLegacyTranscript, VideoID: videoID, File: filepath.Base(plainPath),
+				Detail: "plain text without structured transcript",
+			})
+		}
+	default:
+		issues = append(issues, tldw.StoreIssue{
+			Kind: tldw.StoreIssueCorruptTranscript, VideoID: videoID, File: videoID + ".transcript.json", Detail: err.Error(),
+		})
+	}
+	return issues, nil
+}
+
+// Repair resolves an issue that needs nothing beyond the store: it removes
+// orphaned temporary files, rebuilds structured transcripts from the
+// plain-text copy, and rebuilds the catalog. Metadata issues must be
+// refetched by the caller.
+func (s *File) Repair(issue tldw.StoreIssue) (string, error) {
+	switch issue.Kind {
+	case tldw.StoreIssueOrphanedTemp:
+		if !strings.HasPrefix(issue.File, tempFilePrefix) || filepath.Base(issue.File) != issue.File {
+			return "", fmt.Errorf("invalid temporary file name: %q", issue.File)
+		}
+		if err := os.Remove(filepath.Join(s.dir, issue.File)); err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("removing %s: %w", issue.File, err)
+		}
+		return "removed temporary file", nil
+	case tldw.StoreIssueLegacyTranscript, tldw.StoreIssueCorruptTranscript:
+		text, err := s.loadPlainTranscript(issue.VideoID)
+		if err != nil {
+			return "", fmt.Errorf("no plain-text transcript to rebuild from: %w", err)
+		}
+		if err := s.SaveTranscript(&tldw.Transcript{VideoID: issue.VideoID, Text: text}); err != nil {
+			return "", err
+		}
+		return "wrote structured transcript from plain text", nil
+	case tldw.StoreIssueCatalogDrift:
+		if err := s.RebuildCatalog(); err != nil {
+			return "", err
+		}
+		return "rebuilt catalog", nil
+	default:
+		return "", fmt.Errorf("store cannot repair %s issues", issue.Kind)
+	}
+}
@@ -7,7 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/rtzll/tldw/internal/tldw"
@@ -17,13 +17,21 @@ const metadataCacheVersion = 3
 
 // File is the filesystem adapter for the application's persistence seam.
 type File struct {
-	dir string
+	dir               string
+	catalogMu         sync.Mutex
+	onTranscriptSaved func(videoID string)
 }
 
 func NewFile(dir string) *File {
 	return &File{dir: dir}
 }
 
+// SetTranscriptSavedHook registers fn to be called after each transcript is
+// saved. It must be set before the store is shared.
+func (s *File) SetTranscriptSavedHook(fn func(videoID string)) {
+	s.onTranscriptSaved = fn
+}
+
 func (s *File) LoadTranscript(videoID string) (*tldw.Transcript, error) {
 	transcript, err := s.loadStructuredTranscript(videoID)
 	if err == nil || !errors.Is(err, tldw.ErrStoreNotFound) {
@@ -47,7 +55,47 @@ func (s *File) SaveTranscript(transcript *tldw.Transcript) error {
 	if err != nil {
 		return err
 	}
-	return s.savePlainTranscript(transcript.VideoID, plain)
+	if err := s.savePlainTranscript(transcript.VideoID, plain); err != nil {
+		return err
+	}
+	if err := s.updateCatalog(transcript.VideoID); err != nil {
+		return err
+	}
+	if s.onTranscriptSaved != nil {
+		s.onTranscriptSaved(transcript.VideoID)
+	}
+	return nil
+}
+
+// LoadSummary returns the most recent summary generated for a video.
+func (s *File) LoadSummary(videoID string) (*tldw.Summary, error) {
+	path, err := s.cachePath(videoID, ".summary.md")
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: summary %s", tldw.ErrStoreNotFound, videoID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading summary: %w", err)
+	}
+	return &tldw.Summary{Markdown: string(data)}, nil
+}
+
+// SaveSummary replaces the cached summary of a video.
+func (s *File) SaveSummary(videoID string, summary tldw.Summary) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("creating summary store: %w", err)
+	}
+	path, err := s.cachePath(videoID, ".summary.md")
+	if err != nil {
+		return err
+	}
+	if err := atomicWriteFile(path, []byte(summary.Markdown), 0o644); err != nil {
+		return fmt.Errorf("saving summary: %w", err)
+	}
+	return s.updateCatalog(videoID)
 }
 
 func (s *File) LoadMetadata(videoID string) (*tldw.VideoMetadata, error) {
@@ -74,34 +122,22 @@ func (s *File) LoadMetadata(videoID string) (*tldw.VideoMetadata, error) {
 }
 
 // ListMetadata returns every cached video's metadata and original cache time.
+// It reads only the catalog, so descriptions and chapters are omitted.
 func (s *File) ListMetadata() ([]tldw.StoredVideoMetadata, error) {
-	files, err := filepath.Glob(filepath.Join(s.dir, "*.meta.json"))
+	entries, err := s.catalogEntries()
 	if err != nil {
-		return nil, fmt.Errorf("listing metadata cache: %w", err)
+		return nil, err
 	}
-	entries := make([]tldw.StoredVideoMetadata, 0, len(files))
-	for _, path := range files {
-		videoID := strings.TrimSuffix(filepath.Base(path), ".meta.json")
-		if !tldw.IsValidVideoID(videoID) {
+	stored := make([]tldw.StoredVideoMetadata, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Metadata == nil {
 			continue
 		}
-		data, err := os.ReadFile(filepath.Clean(path))
-		if err != nil {
-			return nil, fmt.Errorf("reading metadata cache %s: %w", filepath.Base(path), err)
-		}
-		var cached cachedMetadata
-		if err := json.Unmarshal(data, &cached); err != nil {
-			return nil, fmt.Errorf("parsing metadata cache %s: %w", filepath.Base(path), err)
-		}
-		firstSeenAt, err := cachedMetadataFirstSeenAt(path, cached)
-		if err != nil {
-			return nil, fmt.Errorf("reading metadata timestamp %s: %w", filepath.Base(path), err)
-		}
-		entries = append(entries, tldw.StoredVideoMetadata{
-			VideoID: videoID, Metadata: metadataFromCached(cached), FirstSeenAt: firstSeenAt,
+		stored = append(stored, tldw.StoredVideoMetadata{
+			VideoID: entry.VideoID, Metadata: *entry.Metadata, FirstSeenAt: entry.FirstSeenAt,
 		})
 	}
-	return entries, nil
+	return stored, nil
 }
 
 func (s *File) SaveMetadata(videoID string, metadata *tldw.VideoMetadata) error {
@@ -132,18 +168,14 @@ func (s *File) SaveMetadata(videoID string, metadata *tldw.VideoMetadata) error
 	if err := atomicWriteFile(path, data, 0o644); err != nil {
 		return fmt.Errorf("saving metadata: %w", err)
 	}
-	return nil
+	return s.updateCatalog(videoID)
 }
 
 func metadataFirstSeenAt(path string, fallback time.Time) time.Time {
-	data, err := os.ReadFile(filepath.Clean(path))
+	cached, err := readCachedMetadata(path)
 	if err != nil {
 		return fallback
 	}
-	var cached cachedMetadata
-	if err := json.Unmarshal(data, &cached); err != nil {
-		return fallback
-	}
 	firstSeenAt, err := cachedMetadataFirstSeenAt(path, cached)
 	if err != nil {
 		return fallback
@@ -151,6 +183,19 @@ func metadataFirstSeenAt(path string, fallback time.Time) time.Time {
 	return firstSeenAt
 }
 
+// readCachedMetadata decodes a metadata cache file regardless of its version.
+func readCachedMetadata(path string) (cachedMetadata, error) {
+	var cached cachedMetadata
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return cached, fmt.Errorf("reading metadata cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return cached, fmt.Errorf("parsing metadata cache: %w", err)
+	}
+	return cached, nil
+}
+
 func cachedMetadataFirstSeenAt(path string, cached cachedMetadata) (time.Time, error) {
 	if !cached.FirstSeenAt.IsZero() {
 		return cached.FirstSeenAt, nil
@@ -268,40 +313,49 @@ func metadataFromCached(cached cachedMetadata) tldw.VideoMetadata {
 	}
 }
 
-func atomicWriteFile(path string, data []byte, mode os.FileMode) (err error) {
-	dir := filepath.Dir(path)
-	temp, err := os.CreateTemp(dir, ".tldw-*")
+func atomicWriteFile(path string, data []byte, mode os.FileMode) error {
+	tempPath, err := writeTempFile(filepath.Dir(path), data, mode)
 	if err != nil {
-		return fmt.Errorf("creating temporary cache file: %w", err)
+		return err
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("replacing cache file: %w", err)
+	}
+	return nil
+}
+
+// writeTempFile writes data to a new synced temporary file in dir and returns
+// its path. The caller renames the file into place or removes it.
+func writeTempFile(dir string, data []byte, mode os.FileMode) (_ string, err error) {
+	temp, err := os.CreateTemp(dir, tempFilePrefix+"*")
+	if err != nil {
+		return "", fmt.Errorf("creating temporary cache file: %w", err)
 	}
 	tempPath := temp.Name()
 	closed := false
 	defer func() {
-		if !closed {
-			if closeErr := temp.Close(); err == nil && closeErr != nil {
-				err = fmt.Errorf("closing temporary cache file: %w", closeErr)
-			}
+		if err == nil {
+			return
 		}
-		if removeErr := os.Remove(tempPath); err == nil && removeErr != nil && !os.IsNotExist(removeErr) {
-			err = fmt.Errorf("removing temporary cache file: %w", removeErr)
+		if !closed {
+			_ = temp.Close()
 		}
+		_ = os.Remove(tempPath)
 	}()
 
 	if err := temp.Chmod(mode); err != nil {
-		return fmt.Errorf("setting temporary cache permissions: %w", err)
+		return "", fmt.Errorf("setting temporary cache permissions: %w", err)
 	}
 	if _, err := temp.Write(data); err != nil {
-		return fmt.Errorf("writing temporary cache file: %w", err)
+		return "", fmt.Errorf("writing temporary cache file: %w", err)
 	}
 	if err := temp.Sync(); err != nil {
-		return fmt.Errorf("syncing temporary cache file: %w", err)
+		return "", fmt.Errorf("syncing temporary cache file: %w", err)
 	}
 	if err := temp.Close(); err != nil {
-		return fmt.Errorf("closing temporary cache file: %w", err)
+		return "", fmt.Errorf("closing temporary cache file: %w", err)
 	}
 	closed = true
-	if err := os.Rename(tempPath, path); err != nil {
-		return fmt.Errorf("replacing cache file: %w", err)
-	}
-	return nil
+	return tempPath, nil
 }
@@ -1,10 +1,16 @@
 package store_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -143,6 +149,24 @@ func TestFileRoundTripsTranscriptAndMetadata(t *testing.T) {
 	}
 }
 
+func TestFileCallsTranscriptSavedHookAfterSaving(t *testing.T) {
+	adapter := store.NewFile(t.TempDir())
+	var saved []string
+	adapter.SetTranscriptSavedHook(func(videoID string) {
+		if _, err := adapter.LoadTranscript(videoID); err != nil {
+			t.Errorf("LoadTranscript() in hook error = %v", err)
+		}
+		saved = append(saved, videoID)
+	})
+
+	if err := adapter.SaveTranscript(&tldw.Transcript{VideoID: "dQw4w9WgXcQ", Text: "Hello"}); err != nil {
+		t.Fatalf("SaveTranscript() error = %v", err)
+	}
+	if len(saved) != 1 || saved[0] != "dQw4w9WgXcQ" {
+		t.Fatalf("hook calls = %v, want one for the saved video", saved)
+	}
+}
+
 func TestFileLoadsLegacyPlainTranscriptWithoutInventingItsSource(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "dQw4w9WgXcQ.txt")
@@ -194,3 +218,410 @@ func TestFileRejectsVideoIDPathTraversal(t *testing.T) {
 		t.Fatal("SaveTranscript() accepted an invalid video ID")
 	}
 }
+
+func TestFileListsLibraryEntriesAcrossAllCacheFiles(t *testing.T) {
+	dir := t.TempDir()
+	adapter := store.NewFile(dir)
+	if err := os.WriteFile(filepath.Join(dir, "tAP1eZYEuKA.txt"), []byte("legacy transcript"), 0o644); err != nil {
+		t.Fatalf("writing legacy transcript: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("unrelated"), 0o644); err != nil {
+		t.Fatalf("writing unrelated file: %v", err)
+	}
+	if err := adapter.SaveTranscript(&tldw.Transcript{
+		VideoID: "dQw4w9WgXcQ", Source: tldw.TranscriptSourceCaptions,
+		Segments: []tldw.TranscriptSegment{{Start: 1, End: 2, Text: "Hello world"}},
+	}); err != nil {
+		t.Fatalf("SaveTranscript() error = %v", err)
+	}
+	if err := adapter.SaveMetadata("dQw4w9WgXcQ", &tldw.VideoMetadata{Title: "Example", Channel: "Channel"}); err != nil {
+		t.Fatalf("SaveMetadata() error = %v", err)
+	}
+
+	entries, err := adapter.ListEntries()
+	if err != nil {
+		t.Fatalf("ListEntries() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("ListEntries() returned %d entries, want 2: %+v", len(entries), entries)
+	}
+	full, legacy := entries[0], entries[1]
+	if full.VideoID != "dQw4w9WgXcQ" || len(full.Files) != 3 || full.Title() != "Example" {
+		t.Fatalf("ListEntries() full entry = %+v", full)
+	}
+	if !full.HasTranscript || !full.HasTimestamps || full.TranscriptSource != tldw.TranscriptSourceCaptions {
+		t.Fatalf("ListEntries() full entry transcript = %+v", full)
+	}
+	if full.Size() <= 0 || full.FirstSeenAt.IsZero() || full.CachedAt.IsZero() {
+		t.Fatalf("ListEntries() full entry sizes and times = %+v", full)
+	}
+	if legacy.VideoID != "tAP1eZYEuKA" || legacy.Metadata != nil || !legacy.HasTranscript || legacy.TranscriptSource != "" {
+		t.Fatalf("ListEntries() legacy entry = %+v", legacy)
+	}
+}
+
+func TestFileDeletesEveryFileOfALibraryEntry(t *testing.T) {
+	dir := t.TempDir()
+	adapter := store.NewFile(dir)
+	if err := adapter.SaveTranscript(&tldw.Transcript{VideoID: "dQw4w9WgXcQ", Text: "Hello"}); err != nil {
+		t.Fatalf("SaveTranscript() error = %v", err)
+	}
+	if err := adapter.SaveMetadata("dQw4w9WgXcQ", &tldw.VideoMetadata{Title: "Example"}); err != nil {
+		t.Fatalf("SaveMetadata() error = %v", err)
+	}
+
+	if err := adapter.DeleteEntry("dQw4w9WgXcQ"); err != nil {
+		t.Fatalf("DeleteEntry() error = %v", err)
+	}
+	remaining, err := filepath.Glob(filepath.Join(dir, "dQw4w9WgXcQ*"))
+	if err != nil {
+		t.Fatalf("Glob() error = %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Fatalf("DeleteEntry() left files behind: %v", remaining)
+	}
+	if err := adapter.DeleteEntry("dQw4w9WgXcQ"); !errors.Is(err, tldw.ErrStoreNotFound) {
+		t.Fatalf("second DeleteEntry() error = %v, want ErrStoreNotFound", err)
+	}
+	if err := adapter.DeleteEntry("../outside"); err == nil {
+		t.Fatal("DeleteEntry() accepted an invalid video ID")
+	}
+}
+
+func TestFileCatalogTracksSavesAndDeletes(t *testing.T) {
+	dir := t.TempDir()
+	adapter := store.NewFile(dir)
+	for _, videoID := range []string{"dQw4w9WgXcQ", "tAP1eZYEuKA"} {
+		if err := adapter.SaveMetadata(videoID, &tldw.VideoMetadata{Title: videoID, Duration: 60}); err != nil {
+			t.Fatalf("SaveMetadata() error = %v", err)
+		}
+	}
+	if err := adapter.DeleteEntry("tAP1eZYEuKA"); err != nil {
+		t.Fatalf("DeleteEntry() error = %v", err)
+	}
+
+	// Listings come from the catalog, not the metadata files.
+	if err := os.WriteFile(filepath.Join(dir, "dQw4w9WgXcQ.meta.json"), []byte("{not json"), 0o644); err != nil {
+		t.Fatalf("corrupting metadata: %v", err)
+	}
+	entries, err := adapter.ListMetadata()
+	if err != nil {
+		t.Fatalf("ListMetadata() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].VideoID != "dQw4w9WgXcQ" || entries[0].Metadata.Duration != 60 {
+		t.Fatalf("ListMetadata() = %+v, want only dQw4w9WgXcQ", entries)
+	}
+}
+
+func TestFileRebuildsCorruptCatalog(t *testing.T) {
+	dir := t.TempDir()
+	adapter := store.NewFile(dir)
+	if err := adapter.SaveMetadata("dQw4w9WgXcQ", &tldw.VideoMetadata{Title: "Example"}); err != nil {
+		t.Fatalf("SaveMetadata() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tAP1eZYEuKA.txt"), []byte("legacy transcript"), 0o644); err != nil {
+		t.Fatalf("writing legacy transcript: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "catalog.jsonl"), []byte("{\"catalog_version\":1}\n{trunc"), 0o644); err != nil {
+		t.Fatalf("corrupting catalog: %v", err)
+	}
+
+	entries, err := adapter.ListEntries()
+	if err != nil {
+		t.Fatalf("ListEntries() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Title() != "Example" || entries[1].VideoID != "tAP1eZYEuKA" {
+		t.Fatalf("ListEntries() = %+v, want rebuilt catalog with both videos", entries)
+	}
+}
+
+func TestFileCatalogKeepsRecordsFromConcurrentStores(t *testing.T) {
+	dir := t.TempDir()
+	// Separate adapters stand in for the CLI and MCP server processes: they
+	// share the directory but not an in-process mutex.
+	writer, rebuilder := store.NewFile(dir), store.NewFile(dir)
+	if err := rebuilder.SaveMetadata("dQw4w9WgXcQ", &tldw.VideoMetadata{Title: "Example"}); err != nil {
+		t.Fatalf("SaveMetadata() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := range 100 {
+			if err := writer.SaveMetadata(fmt.Sprintf("video%06d", i), &tldw.VideoMetadata{Title: "New"}); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		// Rebuilds and compactions replace the catalog while the other store
+		// appends to it.
+		for range 50 {
+			if err := rebuilder.RebuildCatalog(); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("concurrent store error = %v", err)
+	}
+
+	entries, err := store.NewFile(dir).ListEntries()
+	if err != nil {
+		t.Fatalf("ListEntries() error = %v", err)
+	}
+	if len(entries) != 101 {
+		t.Fatalf("ListEntries() returned %d entries, want 101", len(entries))
+	}
+}
+
+func TestFileCheckReportsCatalogDriftAndRepairRebuildsIt(t *testing.T) {
+	dir := t.TempDir()
+	adapter := store.NewFile(dir)
+	for _, videoID := range []string{"dQw4w9WgXcQ", "tAP1eZYEuKA"} {
+		if err := adapter.SaveMetadata(videoID, &tldw.VideoMetadata{Title: videoID}); err != nil {
+			t.Fatalf("SaveMetadata() error = %v", err)
+		}
+	}
+	if issues, err := adapter.Check(); err != nil || len(issues) != 0 {
+		t.Fatalf("Check() of an indexed store = %+v, %v", issues, err)
+	}
+
+	// Simulate saves that crashed before their catalog append.
+	if err := os.WriteFile(filepath.Join(dir, "dQw4w9WgXcQ.summary.md"), []byte("## Summary"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "tAP1eZYEuKA.meta.json")); err != nil {
+		t.Fatal(err)
+	}
+	issues, err := adapter.Check()
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].Kind != tldw.StoreIssueCatalogDrift ||
+		issues[0].Detail != "2 entries out of date: dQw4w9WgXcQ, tAP1eZYEuKA" {
+		t.Fatalf("Check() = %+v, want one catalog drift issue", issues)
+	}
+
+	if _, err := adapter.Repair(issues[0]); err != nil {
+		t.Fatalf("Repair() error = %v", err)
+	}
+	entries, err := adapter.ListEntries()
+	if err != nil {
+		t.Fatalf("ListEntries() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].VideoID != "dQw4w9WgXcQ" || !entries[0].HasSummary {
+		t.Fatalf("ListEntries() after Repair() = %+v", entries)
+	}
+	if issues, err := adapter.Check(); err != nil || len(issues) != 0 {
+		t.Fatalf("Check() after Repair() = %+v, %v", issues, err)
+	}
+}
+
+func TestFileCheckFindsIssuesAndRepairsTranscripts(t *testing.T) {
+	dir := t.TempDir()
+	adapter := store.NewFile(dir)
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+	write("dQw4w9WgXcQ.meta.json", `{"cache_version": 2, "title": "Old"}`)
+	write("dQw4w9WgXcQ.txt", "legacy transcript")
+	write("tAP1eZYEuKA.meta.json", "{not json")
+	write(".tldw-123", "partial")
+	write(".tldw-456", "in flight")
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, ".tldw-123"), old, old); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+
+	issues, err := adapter.Check()
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	kinds := make(map[tldw.StoreIssueKind]tldw.StoreIssue)
+	for _, issue := range issues {
+		kinds[issue.Kind] = issue
+	}
+	if len(issues) != 4 || kinds[tldw.StoreIssueOrphanedTemp].File != ".tldw-123" ||
+		kinds[tldw.StoreIssueStaleMetadata].VideoID != "dQw4w9WgXcQ" ||
+		kinds[tldw.StoreIssueLegacyTranscript].VideoID != "dQw4w9WgXcQ" ||
+		kinds[tldw.StoreIssueCorruptMetadata].VideoID != "tAP1eZYEuKA" {
+		t.Fatalf("Check() = %+v", issues)
+	}
+
+	for _, kind := range []tldw.StoreIssueKind{tldw.StoreIssueOrphanedTemp, tldw.StoreIssueLegacyTranscript} {
+		if _, err := adapter.Repair(kinds[kind]); err != nil {
+			t.Fatalf("Repair(%s) error = %v", kind, err)
+		}
+	}
+	if _, err := adapter.Repair(kinds[tldw.StoreIssueStaleMetadata]); err == nil {
+		t.Fatal("Repair() accepted a metadata issue")
+	}
+	transcript, err := adapter.LoadTranscript("dQw4w9WgXcQ")
+	if err != nil || transcript.Text != "legacy transcript" {
+		t.Fatalf("LoadTranscript() = %+v, %v", transcript, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".tldw-123")); !os.IsNotExist(err) {
+		t.Fatalf("orphaned temp file still exists: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".tldw-456")); err != nil {
+		t.Fatalf("recent temp file was removed: %v", err)
+	}
+}
+
+func TestFileExportsAndImportsLibraryArchives(t *testing.T) {
+	source := store.NewFile(t.TempDir())
+	if err := source.SaveTranscript(&tldw.Transcript{VideoID: "dQw4w9WgXcQ", Text: "exported transcript"}); err != nil {
+		t.Fatalf("SaveTranscript() error = %v", err)
+	}
+	if err := source.SaveMetadata("dQw4w9WgXcQ", &tldw.VideoMetadata{Title: "Exported"}); err != nil {
+		t.Fatalf("SaveMetadata() error = %v", err)
+	}
+	if err := source.SaveSummary("dQw4w9WgXcQ", tldw.Summary{Markdown: "## Exported"}); err != nil {
+		t.Fatalf("SaveSummary() error = %v", err)
+	}
+	exported, err := source.LoadEntry("dQw4w9WgXcQ")
+	if err != nil {
+		t.Fatalf("LoadEntry() error = %v", err)
+	}
+	var archive bytes.Buffer
+	if err := source.ExportEntries(&archive, []string{"dQw4w9WgXcQ"}); err != nil {
+		t.Fatalf("ExportEntries() error = %v", err)
+	}
+
+	tests := []struct {
+		policy  tldw.ImportPolicy
+		outcome tldw.ImportOutcome
+		title   string
+	}{
+		{policy: tldw.ImportKeepLocal, outcome: tldw.ImportSkipped, title: "Local"},
+		{policy: tldw.ImportKeepNewest, outcome: tldw.ImportSkipped, title: "Local"},
+		{policy: tldw.ImportOverwrite, outcome: tldw.ImportReplaced, title: "Exported"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			target := store.NewFile(t.TempDir())
+			if err := target.SaveMetadata("dQw4w9WgXcQ", &tldw.VideoMetadata{Title: "Local"}); err != nil {
+				t.Fatalf("SaveMetadata() error = %v", err)
+			}
+			imports, err := target.ImportEntries(bytes.NewReader(archive.Bytes()), tt.policy)
+			if err != nil {
+				t.Fatalf("ImportEntries() error = %v", err)
+			}
+			if len(imports) != 1 || imports[0].Outcome != tt.outcome {
+				t.Fatalf("ImportEntries() = %+v, want %s", imports, tt.outcome)
+			}
+			entries, err := target.ListEntries()
+			if err != nil {
+				t.Fatalf("ListEntries() error = %v", err)
+			}
+			if len(entries) != 1 || entries[0].Title() != tt.title {
+				t.Fatalf("ListEntries() = %+v, want title %q", entries, tt.title)
+			}
+		})
+	}
+
+	target := store.NewFile(t.TempDir())
+	imports, err := target.ImportEntries(bytes.NewReader(archive.Bytes()), tldw.ImportKeepNewest)
+	if err != nil || len(imports) != 1 || imports[0].Outcome != tldw.ImportAdded {
+		t.Fatalf("ImportEntries() into an empty store = %+v, %v", imports, err)
+	}
+	imported, err := target.LoadEntry("dQw4w9WgXcQ")
+	if err != nil {
+		t.Fatalf("LoadEntry() error = %v", err)
+	}
+	if !imported.HasSummary || !imported.FirstSeenAt.Equal(exported.FirstSeenAt) || !imported.CachedAt.Equal(exported.CachedAt) {
+		t.Fatalf("imported entry = %+v, want %+v", imported, exported)
+	}
+}
+
+func TestFileImportReplacesLocalFilesWithoutLeavingTemporaryFiles(t *testing.T) {
+	source := store.NewFile(t.TempDir())
+	if err := source.SaveMetadata("dQw4w9WgXcQ", &tldw.VideoMetadata{Title: "Exported"}); err != nil {
+		t.Fatalf("SaveMetadata() error = %v", err)
+	}
+	var archive bytes.Buffer
+	if err := source.ExportEntries(&archive, []string{"dQw4w9WgXcQ"}); err != nil {
+		t.Fatalf("ExportEntries() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	target := store.NewFile(dir)
+	if err := target.SaveMetadata("dQw4w9WgXcQ", &tldw.VideoMetadata{Title: "Local"}); err != nil {
+		t.Fatalf("SaveMetadata() error = %v", err)
+	}
+	if err := target.SaveTranscript(&tldw.Transcript{VideoID: "dQw4w9WgXcQ", Text: "local transcript"}); err != nil {
+		t.Fatalf("SaveTranscript() error = %v", err)
+	}
+	if _, err := target.ImportEntries(&archive, tldw.ImportOverwrite); err != nil {
+		t.Fatalf("ImportEntries() error = %v", err)
+	}
+
+	entry, err := target.LoadEntry("dQw4w9WgXcQ")
+	if err != nil {
+		t.Fatalf("LoadEntry() error = %v", err)
+	}
+	if entry.Title() != "Exported" || entry.HasTranscript {
+		t.Fatalf("imported entry = %+v, want the archived metadata without the local transcript", entry)
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	for _, file := range files {
+		if strings.HasPrefix(file.Name(), ".tldw-") {
+			t.Errorf("ImportEntries() left temporary file %s", file.Name())
+		}
+	}
+}
+
+func TestFileRejectsArchivesWithUnexpectedFiles(t *testing.T) {
+	var archive bytes.Buffer
+	compressed := gzip.NewWriter(&archive)
+	tarball := tar.NewWriter(compressed)
+	data := []byte("escape")
+	if err := tarball.WriteHeader(&tar.Header{Name: "../outside.txt", Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatalf("WriteHeader() error = %v", err)
+	}
+	if _, err := tarball.Write(data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := tarball.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := compressed.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	if _, err := store.NewFile(dir).ImportEntries(&archive, tldw.ImportOverwrite); err == nil {
+		t.Fatal("ImportEntries() accepted a file outside the library")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "outside.txt")); !os.IsNotExist(err) {
+		t.Fatalf("ImportEntries() wrote outside the store: %v", err)
+	}
+}
+
+func TestFileCheckWritableCreatesStoreAndLeavesNoProbe(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "transcripts")
+	if err := store.NewFile(dir).CheckWritable(); err != nil {
+		t.Fatalf("CheckWritable() error = %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("store entries = %v, want the probe removed", entries)
+	}
+}
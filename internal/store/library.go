@@ -0,0 +1,142 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+// entrySuffixes lists every file a library entry may own, in display order.
+var entrySuffixes = []string{".transcript.json", ".txt", ".meta.json", ".summary.md"}
+
+// ListEntries returns every video that has at least one cached file. It reads
+// only the catalog, so metadata omits descriptions and chapters.
+func (s *File) ListEntries() ([]tldw.LibraryEntry, error) {
+	return s.catalogEntries()
+}
+
+// LoadEntry describes the cached files, metadata, and transcript of a video.
+// Metadata is reported regardless of its cache version.
+func (s *File) LoadEntry(videoID string) (*tldw.LibraryEntry, error) {
+	entry := &tldw.LibraryEntry{VideoID: videoID}
+	var oldest, newest time.Time
+	for _, suffix := range entrySuffixes {
+		path, err := s.cachePath(videoID, suffix)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("inspecting %s: %w", filepath.Base(path), err)
+		}
+		entry.Files = append(entry.Files, tldw.LibraryFile{Name: info.Name(), Size: info.Size()})
+		if suffix == ".summary.md" {
+			entry.HasSummary = true
+			entry.SummarizedAt = info.ModTime()
+		}
+		if oldest.IsZero() || info.ModTime().Before(oldest) {
+			oldest = info.ModTime()
+		}
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+	}
+	if len(entry.Files) == 0 {
+		return nil, fmt.Errorf("%w: library entry %s", tldw.ErrStoreNotFound, videoID)
+	}
+	entry.FirstSeenAt = oldest
+	entry.CachedAt = newest
+
+	metadataPath, _ := s.cachePath(videoID, ".meta.json")
+	if cached, err := readCachedMetadata(metadataPath); err == nil {
+		metadata := metadataFromCached(cached)
+		entry.Metadata = &metadata
+		if firstSeenAt, err := cachedMetadataFirstSeenAt(metadataPath, cached); err == nil {
+			entry.FirstSeenAt = firstSeenAt
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	transcript, err := s.LoadTranscript(videoID)
+	if err != nil && !errors.Is(err, tldw.ErrStoreNotFound) {
+		return nil, err
+	}
+	if transcript != nil {
+		entry.HasTranscript = true
+		entry.HasTimestamps = transcript.HasTimestamps()
+		entry.TranscriptSource = transcript.Source
+	}
+	return entry, nil
+}
+
+// DeleteEntry removes the transcript, plain-text, metadata, and summary files
+// of a video together. It reports ErrStoreNotFound when nothing was cached.
+func (s *File) DeleteEntry(videoID string) error {
+	removed := 0
+	var errs []error
+	for _, suffix := range entrySuffixes {
+		path, err := s.cachePath(videoID, suffix)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err == nil {
+			removed++
+		} else if !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("removing %s: %w", filepath.Base(path), err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	if removed == 0 {
+		return fmt.Errorf("%w: library entry %s", tldw.ErrStoreNotFound, videoID)
+	}
+	return s.updateCatalog(videoID)
+}
+
+// entryVideoIDs returns the sorted IDs of all videos with cached files.
+func (s *File) entryVideoIDs() ([]string, error) {
+	dirEntries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading transcript store: %w", err)
+	}
+	seen := make(map[string]struct{})
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			continue
+		}
+		videoID, ok := entryFileVideoID(dirEntry.Name())
+		if !ok {
+			continue
+		}
+		seen[videoID] = struct{}{}
+	}
+	videoIDs := make([]string, 0, len(seen))
+	for videoID := range seen {
+		videoIDs = append(videoIDs, videoID)
+	}
+	sort.Strings(videoIDs)
+	return videoIDs, nil
+}
+
+func entryFileVideoID(name string) (string, bool) {
+	for _, suffix := range entrySuffixes {
+		if videoID, ok := strings.CutSuffix(name, suffix); ok && tldw.IsValidVideoID(videoID) {
+			return videoID, true
+		}
+	}
+	return "", false
+}
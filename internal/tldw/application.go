@@ -14,15 +14,19 @@ type Config struct {
 
 type PromptBuilder interface {
 	CreatePrompt(transcript string, metadata *VideoMetadata) (string, error)
+	// CreateCustomPrompt renders a caller-supplied template instead of the
+	// configured one.
+	CreateCustomPrompt(template, transcript string, metadata *VideoMetadata) (string, error)
 }
 
 // Dependencies contains the collaborators required by every Engine instance.
 type Dependencies struct {
-	Video   VideoAdapter
-	Store   VideoStore
-	AI      AIAdapter
-	Prompts PromptBuilder
-	Log     LogSink
+	Video    VideoAdapter
+	Store    VideoStore
+	AI       AIAdapter
+	Prompts  PromptBuilder
+	Log      LogSink
+	Observer Observer
 }
 
 // Engine is the application's deep module and owns workflow policy.
@@ -33,6 +37,7 @@ type Engine struct {
 	promptManager PromptBuilder
 	config        Config
 	log           LogSink
+	observer      Observer
 	metadataCache map[string]*VideoMetadata
 	metadataMu    sync.RWMutex
 }
@@ -58,6 +63,10 @@ func NewEngine(config Config, dependencies Dependencies) (*Engine, error) {
 	if log == nil {
 		log = discardLogSink{}
 	}
+	observer := dependencies.Observer
+	if observer == nil {
+		observer = discardObserver{}
+	}
 	app := &Engine{
 		video:         dependencies.Video,
 		store:         dependencies.Store,
@@ -65,6 +74,7 @@ func NewEngine(config Config, dependencies Dependencies) (*Engine, error) {
 		promptManager: dependencies.Prompts,
 		config:        config,
 		log:           log,
+		observer:      observer,
 		metadataCache: make(map[string]*VideoMetadata),
 	}
 	return app, nil
@@ -85,6 +95,13 @@ func (app *Engine) setCachedMetadata(id string, metadata *VideoMetadata) {
 	app.metadataCache[id] = metadata
 }
 
+// forgetCachedMetadata drops a video from the in-memory cache.
+func (app *Engine) forgetCachedMetadata(id string) {
+	app.metadataMu.Lock()
+	defer app.metadataMu.Unlock()
+	delete(app.metadataCache, id)
+}
+
 func (app *Engine) metadataRefreshReason(metadata *VideoMetadata) string {
 	if metadata == nil {
 		return ""
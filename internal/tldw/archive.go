@@ -0,0 +1,80 @@
+package tldw
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ImportPolicy decides what happens when an imported video is already in the
+// local library.
+type ImportPolicy string
+
+const (
+	// ImportKeepNewest keeps whichever copy was cached most recently.
+	ImportKeepNewest ImportPolicy = "newest"
+	// ImportKeepLocal never replaces a video that is already cached.
+	ImportKeepLocal ImportPolicy = "local"
+	// ImportOverwrite always replaces local videos with the imported copy.
+	ImportOverwrite ImportPolicy = "overwrite"
+)
+
+// ParseImportPolicy validates a user-supplied conflict policy.
+func ParseImportPolicy(value string) (ImportPolicy, error) {
+	policy := ImportPolicy(strings.ToLower(strings.TrimSpace(value)))
+	switch policy {
+	case ImportKeepNewest, ImportKeepLocal, ImportOverwrite:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unsupported import policy %q: use newest, local, or overwrite", value)
+	}
+}
+
+// ImportOutcome describes what an import did with one video.
+type ImportOutcome string
+
+const (
+	ImportAdded    ImportOutcome = "added"
+	ImportReplaced ImportOutcome = "replaced"
+	ImportSkipped  ImportOutcome = "skipped"
+)
+
+// LibraryImport is the result of importing one video.
+type LibraryImport struct {
+	VideoID string
+	Outcome ImportOutcome
+}
+
+// ExportLibrary writes the videos matching query to an archive and returns
+// the exported entries.
+func (app *Engine) ExportLibrary(query LibraryQuery, archive io.Writer) ([]LibraryEntry, error) {
+	entries, err := app.Library(query)
+	if err != nil {
+		return nil, err
+	}
+	videoIDs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		videoIDs = append(videoIDs, entry.VideoID)
+	}
+	if err := app.store.ExportEntries(archive, videoIDs); err != nil {
+		return nil, fmt.Errorf("exporting library: %w", err)
+	}
+	return entries, nil
+}
+
+// ImportLibrary merges an archive written by ExportLibrary into the library.
+func (app *Engine) ImportLibrary(archive io.Reader, policy ImportPolicy) ([]LibraryImport, error) {
+	if _, err := ParseImportPolicy(string(policy)); err != nil {
+		return nil, err
+	}
+	imports, err := app.store.ImportEntries(archive, policy)
+	for _, imported := range imports {
+		if imported.Outcome != ImportSkipped {
+			app.forgetCachedMetadata(imported.VideoID)
+		}
+	}
+	if err != nil {
+		return imports, fmt.Errorf("importing library: %w", err)
+	}
+	return imports, nil
+}
@@ -0,0 +1,32 @@
+package tldw
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// NewUploads lists a channel's uploads newer than the video since, oldest
+// first. At most limit recent uploads are inspected, so when since is empty
+// or has dropped out of that window every inspected upload is returned.
+func (app *Engine) NewUploads(ctx context.Context, channel YouTubeRef, since string, limit int) ([]YouTubeRef, error) {
+	if channel.Kind() != ContentTypeChannel {
+		return nil, fmt.Errorf("new uploads require a channel reference")
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("upload limit must be positive")
+	}
+	uploads, err := app.video.FetchChannelUploads(ctx, channel, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing uploads of %s: %w", channel.ID(), err)
+	}
+	var fresh []YouTubeRef
+	for _, upload := range uploads.Videos {
+		if since != "" && upload.ID() == since {
+			break
+		}
+		fresh = append(fresh, upload)
+	}
+	slices.Reverse(fresh)
+	return fresh, nil
+}
@@ -0,0 +1,52 @@
+package tldw_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+func TestEngineNewUploadsStopsAtTheLastSeenVideo(t *testing.T) {
+	var uploads []tldw.YouTubeRef
+	for _, id := range []string{"ccccccccccc", "bbbbbbbbbbb", "aaaaaaaaaaa"} {
+		ref, err := tldw.ParseVideoRef(id)
+		if err != nil {
+			t.Fatalf("ParseVideoRef() error = %v", err)
+		}
+		uploads = append(uploads, ref)
+	}
+	engine, err := tldw.NewEngine(tldw.Config{}, tldw.Dependencies{
+		Video: &videoStub{playlist: &tldw.PlaylistInfo{Videos: uploads}}, Store: &memoryStore{}, AI: &aiStub{}, Prompts: &promptStub{},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+	channel, err := tldw.ParseChannelRef("@CMUDatabaseGroup")
+	if err != nil {
+		t.Fatalf("ParseChannelRef() error = %v", err)
+	}
+
+	tests := []struct {
+		since string
+		want  []string
+	}{
+		{since: "aaaaaaaaaaa", want: []string{"bbbbbbbbbbb", "ccccccccccc"}},
+		{since: "ccccccccccc", want: nil},
+		{since: "", want: []string{"aaaaaaaaaaa", "bbbbbbbbbbb", "ccccccccccc"}},
+	}
+	for _, tt := range tests {
+		fresh, err := engine.NewUploads(context.Background(), channel, tt.since, 10)
+		if err != nil {
+			t.Fatalf("NewUploads(%q) error = %v", tt.since, err)
+		}
+		var got []string
+		for _, ref := range fresh {
+			got = append(got, ref.ID())
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("NewUploads(%q) = %v, want %v", tt.since, got, tt.want)
+		}
+	}
+}
@@ -4,8 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
-	"time"
 )
 
 // TranscriptPolicy controls whether transcript acquisition may use paid
@@ -22,16 +22,22 @@ const (
 // concerns. Timestamp rendering is kept outside the acquisition module, but
 // RequireTimestamps prevents a plain-text-only cache entry from satisfying the
 // request.
+//
+// Language selects a caption track such as "de" or "pt-BR"; empty picks the
+// video's English or original captions. Whisper transcribes whatever is
+// spoken, so Language does not constrain it.
 type TranscriptRequest struct {
 	Policy            TranscriptPolicy
 	RequireTimestamps bool
+	Language          string
 }
 
 // ErrCaptionsUnavailable is returned when captions are unavailable and the
 // request does not permit Whisper transcription.
 var ErrCaptionsUnavailable = errors.New("captions are unavailable")
 
-// ErrDownloadFailed marks a retryable failure from a video adapter.
+// ErrDownloadFailed marks a failed download from a video adapter. Adapters
+// retry transient failures before returning it.
 var ErrDownloadFailed = errors.New("video download failed")
 
 // ErrInvalidTranscriptPolicy indicates a request with an unknown policy value.
@@ -50,12 +56,15 @@ type VideoAdapter interface {
 	FetchCaptions(ctx context.Context, ref YouTubeRef, preferredLangs []string, originalLang string) (*Transcript, error)
 	DownloadAudio(ctx context.Context, ref YouTubeRef) (string, error)
 	FetchPlaylist(ctx context.Context, ref YouTubeRef) (*PlaylistInfo, error)
+	FetchChannelUploads(ctx context.Context, ref YouTubeRef, limit int) (*PlaylistInfo, error)
 }
 
 // AIAdapter is the seam for paid transcription and summary generation.
 type AIAdapter interface {
 	Transcribe(ctx context.Context, audioFile string) (string, error)
-	Summary(ctx context.Context, prompt string) (string, error)
+	Summary(ctx context.Context, model, prompt string) (string, error)
+	// SummaryModel names the model used when a request does not choose one.
+	SummaryModel() string
 }
 
 // VideoStore is the persistence seam used by application workflows.
@@ -65,6 +74,15 @@ type VideoStore interface {
 	LoadMetadata(videoID string) (*VideoMetadata, error)
 	SaveMetadata(videoID string, metadata *VideoMetadata) error
 	ListMetadata() ([]StoredVideoMetadata, error)
+	ListEntries() ([]LibraryEntry, error)
+	LoadEntry(videoID string) (*LibraryEntry, error)
+	DeleteEntry(videoID string) error
+	LoadSummary(videoID string) (*Summary, error)
+	SaveSummary(videoID string, summary Summary) error
+	ExportEntries(archive io.Writer, videoIDs []string) error
+	ImportEntries(archive io.Reader, policy ImportPolicy) ([]LibraryImport, error)
+	Check() ([]StoreIssue, error)
+	Repair(issue StoreIssue) (string, error)
 }
 
 // LogSink receives diagnostic events without coupling workflows to a terminal.
@@ -76,10 +94,33 @@ type discardLogSink struct{}
 
 func (discardLogSink) Printf(string, ...any) {}
 
+// Observer counts cache lookups without coupling workflows to a metrics
+// backend. kind names the cached data, such as "transcript" or "metadata".
+type Observer interface {
+	CacheLookup(kind string, hit bool)
+}
+
+type discardObserver struct{}
+
+func (discardObserver) CacheLookup(string, bool) {}
+
 // Summary is transport-neutral output. Terminal rendering belongs to the CLI
 // adapter and structured serialization belongs to MCP.
 type Summary struct {
 	Markdown string
+	// Model and TranscriptSource describe how a fresh summary was generated;
+	// they are empty for summaries loaded from the store.
+	Model            string
+	TranscriptSource TranscriptSource
+}
+
+// SummaryRequest selects the transcript to summarize. Prompt, when set, is a
+// prompt template used instead of the configured one; Model, when set,
+// replaces the configured model.
+type SummaryRequest struct {
+	Transcript TranscriptRequest
+	Prompt     string
+	Model      string
 }
 
 type PlaylistSummaryRequest struct {
@@ -106,11 +147,13 @@ func (app *Engine) Transcript(ctx context.Context, ref YouTubeRef, request Trans
 	}
 	if transcript, err := app.store.LoadTranscript(ref.ID()); err == nil {
 		if cachedTranscriptAllowed(transcript, request) {
+			app.observer.CacheLookup("transcript", true)
 			return transcript, nil
 		}
 	} else if !errors.Is(err, ErrStoreNotFound) && !errors.Is(err, ErrStoreStale) {
 		return nil, fmt.Errorf("loading cached transcript: %w", err)
 	}
+	app.observer.CacheLookup("transcript", false)
 	if request.Policy == TranscriptPolicyWhisperOnly {
 		return app.transcribeVideo(ctx, ref)
 	}
@@ -119,26 +162,32 @@ func (app *Engine) Transcript(ctx context.Context, ref YouTubeRef, request Trans
 	if err != nil {
 		return nil, fmt.Errorf("checking video metadata: %w", err)
 	}
-	if !metadata.HasCaptions {
+	ReportProgress(ctx, Progress{Stage: ProgressStageMetadata, Message: "Fetched video metadata"})
+	preferredLangs, originalLang := metadata.CaptionLanguages, metadata.Language
+	if request.Language != "" {
+		preferredLangs = matchingCaptionLanguages(metadata.CaptionLanguages, request.Language)
+		originalLang = request.Language
+	}
+	if !metadata.HasCaptions || (request.Language != "" && len(preferredLangs) == 0) {
 		if request.RequireTimestamps {
 			return nil, ErrTranscriptTimestampsUnavailable
 		}
 		if request.Policy == TranscriptPolicyCaptionsOnly {
-			return nil, fmt.Errorf("%w for %s", ErrCaptionsUnavailable, ref.ID())
+			return nil, fmt.Errorf("%w for %s", ErrCaptionsUnavailable, captionsDescription(ref, request))
 		}
 		return app.transcribeVideo(ctx, ref)
 	}
 
-	transcript, err := app.video.FetchCaptions(ctx, ref, metadata.CaptionLanguages, metadata.Language)
-	if errors.Is(err, ErrDownloadFailed) {
-		if waitErr := sleepWithContext(ctx, time.Second); waitErr != nil {
-			return nil, waitErr
-		}
-		transcript, err = app.video.FetchCaptions(ctx, ref, metadata.CaptionLanguages, metadata.Language)
-	}
+	ReportProgress(ctx, Progress{Stage: ProgressStageCaptions, Message: "Fetching captions"})
+	transcript, err := app.video.FetchCaptions(ctx, ref, preferredLangs, originalLang)
 	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 		return nil, err
 	}
+	if err == nil && transcript != nil && request.Language != "" &&
+		!captionLanguageMatches(transcript.Language, request.Language) {
+		// yt-dlp fell back to English captions.
+		err = fmt.Errorf("%w for %s", ErrCaptionsUnavailable, captionsDescription(ref, request))
+	}
 	if (err != nil || transcript == nil) && request.Policy == TranscriptPolicyCaptionsThenWhisper && !request.RequireTimestamps {
 		return app.transcribeVideo(ctx, ref)
 	}
@@ -153,8 +202,12 @@ func (app *Engine) Transcript(ctx context.Context, ref YouTubeRef, request Trans
 	}
 
 	transcript.VideoID = ref.ID()
-	if err := app.persistTranscript(transcript); err != nil {
-		app.log.Printf("Warning: %v\n", err)
+	// The store keeps one transcript per video, which requests without a
+	// language are served from, so other languages are not cached.
+	if request.Language == "" || captionLanguageMatches(transcript.Language, metadata.Language) {
+		if err := app.persistTranscript(transcript); err != nil {
+			app.log.Printf("Warning: %v\n", err)
+		}
 	}
 	return transcript, nil
 }
@@ -163,6 +216,10 @@ func cachedTranscriptAllowed(transcript *Transcript, request TranscriptRequest)
 	if transcript == nil || (request.RequireTimestamps && !transcript.HasTimestamps()) {
 		return false
 	}
+	if request.Language != "" && transcript.Source == TranscriptSourceCaptions &&
+		!captionLanguageMatches(transcript.Language, request.Language) {
+		return false
+	}
 	switch request.Policy {
 	case TranscriptPolicyCaptionsOnly:
 		return transcript.Source == TranscriptSourceCaptions
@@ -173,10 +230,37 @@ func cachedTranscriptAllowed(transcript *Transcript, request TranscriptRequest)
 	}
 }
 
+// captionLanguageMatches reports whether a caption track serves the requested
+// language: "en" is served by "en" and regional tracks such as "en-GB".
+func captionLanguageMatches(track, requested string) bool {
+	if strings.EqualFold(track, requested) {
+		return true
+	}
+	prefix, _, found := strings.Cut(track, "-")
+	return found && strings.EqualFold(prefix, requested)
+}
+
+func matchingCaptionLanguages(tracks []string, requested string) []string {
+	var matching []string
+	for _, track := range tracks {
+		if captionLanguageMatches(track, requested) {
+			matching = append(matching, track)
+		}
+	}
+	return matching
+}
+
+func captionsDescription(ref YouTubeRef, request TranscriptRequest) string {
+	if request.Language == "" {
+		return ref.ID()
+	}
+	return fmt.Sprintf("%s in %s", ref.ID(), request.Language)
+}
+
 // SummarizeVideo acquires a transcript and returns raw Markdown without
 // transport-specific rendering or output.
-func (app *Engine) SummarizeVideo(ctx context.Context, ref YouTubeRef, request TranscriptRequest) (Summary, error) {
-	transcript, err := app.Transcript(ctx, ref, request)
+func (app *Engine) SummarizeVideo(ctx context.Context, ref YouTubeRef, request SummaryRequest) (Summary, error) {
+	transcript, err := app.Transcript(ctx, ref, request.Transcript)
 	if err != nil {
 		return Summary{}, err
 	}
@@ -189,15 +273,59 @@ func (app *Engine) SummarizeVideo(ctx context.Context, ref YouTubeRef, request T
 		app.log.Printf("Failed to extract video metadata: %v\n", err)
 		metadata = nil
 	}
-	prompt, err := app.promptManager.CreatePrompt(plain, metadata)
+	var prompt string
+	if strings.TrimSpace(request.Prompt) != "" {
+		prompt, err = app.promptManager.CreateCustomPrompt(request.Prompt, plain, metadata)
+	} else {
+		prompt, err = app.promptManager.CreatePrompt(plain, metadata)
+	}
 	if err != nil {
 		return Summary{}, fmt.Errorf("creating prompt: %w", err)
 	}
-	markdown, err := app.ai.Summary(ctx, prompt)
+	model := strings.TrimSpace(request.Model)
+	if model == "" {
+		model = app.ai.SummaryModel()
+	}
+	ReportProgress(ctx, Progress{Stage: ProgressStageSummarizing, Message: "Summarizing with " + model})
+	markdown, err := app.ai.Summary(ctx, model, prompt)
 	if err != nil {
 		return Summary{}, fmt.Errorf("generating summary: %w", err)
 	}
-	return Summary{Markdown: markdown}, nil
+	summary := Summary{Markdown: markdown, Model: model, TranscriptSource: transcript.Source}
+	// The cached summary is the one the library, feed, and API serve, so only
+	// summaries with the configured prompt and model in the default language
+	// replace it.
+	if request.Transcript.Language == "" && strings.TrimSpace(request.Prompt) == "" && strings.TrimSpace(request.Model) == "" {
+		if err := app.store.SaveSummary(ref.ID(), summary); err != nil {
+			app.log.Printf("Warning: Failed to cache summary: %v\n", err)
+		}
+	}
+	return summary, nil
+}
+
+// Playlist lists a playlist's videos without fetching their transcripts.
+// Entries is always populated, from Videos alone if the adapter reported no
+// titles or durations.
+func (app *Engine) Playlist(ctx context.Context, ref YouTubeRef) (*PlaylistInfo, error) {
+	if !ref.IsPlaylist() {
+		return nil, fmt.Errorf("playlist listing requires a playlist reference")
+	}
+	playlist, err := app.video.FetchPlaylist(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("extracting playlist videos: %w", err)
+	}
+	if playlist == nil {
+		return nil, fmt.Errorf("extracting playlist videos: adapter returned no playlist")
+	}
+	if len(playlist.Entries) != len(playlist.Videos) {
+		listed := *playlist
+		listed.Entries = make([]PlaylistEntry, 0, len(playlist.Videos))
+		for _, video := range playlist.Videos {
+			listed.Entries = append(listed.Entries, PlaylistEntry{Ref: video})
+		}
+		playlist = &listed
+	}
+	return playlist, nil
 }
 
 // CreatePlaylistSummary owns playlist traversal and transcript acquisition but
@@ -210,12 +338,9 @@ func (app *Engine) CreatePlaylistSummary(ctx context.Context, ref YouTubeRef, re
 	if !ref.IsPlaylist() {
 		return PlaylistSummaryResult{}, fmt.Errorf("playlist summary requires a playlist reference")
 	}
-	playlist, err := app.video.FetchPlaylist(ctx, ref)
+	playlist, err := app.Playlist(ctx, ref)
 	if err != nil {
-		return PlaylistSummaryResult{}, fmt.Errorf("extracting playlist videos: %w", err)
-	}
-	if playlist == nil {
-		return PlaylistSummaryResult{}, fmt.Errorf("extracting playlist videos: adapter returned no playlist")
+		return PlaylistSummaryResult{}, err
 	}
 	if len(playlist.Videos) == 0 {
 		return PlaylistSummaryResult{}, fmt.Errorf("no videos found in playlist")
@@ -224,6 +349,12 @@ func (app *Engine) CreatePlaylistSummary(ctx context.Context, ref YouTubeRef, re
 	result := PlaylistSummaryResult{Title: playlist.Title, Total: len(playlist.Videos)}
 	var videos []VideoTranscript
 	for i, videoRef := range playlist.Videos {
+		ReportProgress(ctx, Progress{
+			Stage:   ProgressStageCaptions,
+			Message: fmt.Sprintf("Fetching transcript for video %d of %d", i+1, len(playlist.Videos)),
+			Current: i,
+			Total:   len(playlist.Videos),
+		})
 		transcript, transcriptErr := app.Transcript(ctx, videoRef, request.Transcript)
 		metadata, metadataErr := app.resolveMetadata(ctx, videoRef)
 		if metadataErr != nil {
@@ -263,7 +394,8 @@ func (app *Engine) CreatePlaylistSummary(ctx context.Context, ref YouTubeRef, re
 	if err != nil {
 		return result, fmt.Errorf("creating prompt: %w", err)
 	}
-	result.Markdown, err = app.ai.Summary(ctx, prompt)
+	ReportProgress(ctx, Progress{Stage: ProgressStageSummarizing, Message: fmt.Sprintf("Summarizing %d videos", len(videos))})
+	result.Markdown, err = app.ai.Summary(ctx, app.ai.SummaryModel(), prompt)
 	if err != nil {
 		return result, fmt.Errorf("generating playlist summary: %w", err)
 	}
@@ -285,16 +417,19 @@ func validateTranscriptRequest(request TranscriptRequest) error {
 
 func (app *Engine) resolveMetadata(ctx context.Context, ref YouTubeRef) (*VideoMetadata, error) {
 	if cached, ok := app.getCachedMetadata(ref.ID()); ok {
+		app.observer.CacheLookup("metadata", true)
 		return app.useOrRefreshMetadata(ctx, ref, cached), nil
 	}
 
 	if cached, err := app.store.LoadMetadata(ref.ID()); err == nil {
+		app.observer.CacheLookup("metadata", true)
 		resolved := app.useOrRefreshMetadata(ctx, ref, cached)
 		app.setCachedMetadata(ref.ID(), resolved)
 		return resolved, nil
 	} else if !errors.Is(err, ErrStoreNotFound) && !errors.Is(err, ErrStoreStale) {
 		return nil, fmt.Errorf("loading cached metadata: %w", err)
 	}
+	app.observer.CacheLookup("metadata", false)
 
 	metadata, err := app.video.FetchMetadata(ctx, ref)
 	if err != nil {
@@ -327,10 +462,12 @@ func (app *Engine) cacheMetadata(videoID string, metadata *VideoMetadata) {
 }
 
 func (app *Engine) transcribeVideo(ctx context.Context, ref YouTubeRef) (*Transcript, error) {
+	ReportProgress(ctx, Progress{Stage: ProgressStageAudio, Message: "Downloading audio"})
 	audioFile, err := app.video.DownloadAudio(ctx, ref)
 	if err != nil {
 		return nil, fmt.Errorf("downloading audio: %w", err)
 	}
+	ReportProgress(ctx, Progress{Stage: ProgressStageTranscribing, Message: "Transcribing audio with Whisper"})
 	transcript, err := app.transcribeAudio(ctx, audioFile)
 	if err != nil {
 		return nil, err
@@ -358,14 +495,3 @@ func (app *Engine) transcribeAudio(ctx context.Context, audioFile string) (*Tran
 func (app *Engine) persistTranscript(transcript *Transcript) error {
 	return app.store.SaveTranscript(transcript)
 }
-
-func sleepWithContext(ctx context.Context, duration time.Duration) error {
-	timer := time.NewTimer(duration)
-	defer timer.Stop()
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-timer.C:
-		return nil
-	}
-}
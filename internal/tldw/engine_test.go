@@ -3,6 +3,8 @@ package tldw_test
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/rtzll/tldw/internal/tldw"
@@ -148,8 +150,9 @@ func TestEngineCachesCaptionTranscript(t *testing.T) {
 		},
 	}
 	store := &memoryStore{}
+	observer := &observerStub{}
 	engine, err := tldw.NewEngine(tldw.Config{}, tldw.Dependencies{
-		Video: video, Store: store, AI: &aiStub{}, Prompts: &promptStub{},
+		Video: video, Store: store, AI: &aiStub{}, Prompts: &promptStub{}, Observer: observer,
 	})
 	if err != nil {
 		t.Fatalf("NewEngine() error = %v", err)
@@ -175,6 +178,90 @@ func TestEngineCachesCaptionTranscript(t *testing.T) {
 	if video.captionCalls != 1 || store.transcriptSaves != 1 {
 		t.Fatalf("caption calls = %d, saves = %d", video.captionCalls, store.transcriptSaves)
 	}
+	var transcriptLookups []string
+	for _, lookup := range observer.lookups {
+		if strings.HasPrefix(lookup, "transcript ") {
+			transcriptLookups = append(transcriptLookups, lookup)
+		}
+	}
+	if want := []string{"transcript miss", "transcript hit"}; !slices.Equal(transcriptLookups, want) {
+		t.Fatalf("transcript cache lookups = %v, want %v", transcriptLookups, want)
+	}
+}
+
+func TestEngineSelectsRequestedCaptionLanguage(t *testing.T) {
+	video := &videoStub{
+		metadata: &tldw.VideoMetadata{HasCaptions: true, Language: "en", CaptionLanguages: []string{"de-DE", "en", "fr"}},
+		captions: &tldw.Transcript{
+			Language: "de-DE",
+			Source:   tldw.TranscriptSourceCaptions,
+			Segments: []tldw.TranscriptSegment{{Start: 0, End: 2, Text: "hallo welt"}},
+		},
+	}
+	store := &memoryStore{transcript: &tldw.Transcript{Language: "en", Source: tldw.TranscriptSourceCaptions, Text: "hello world"}}
+	engine, err := tldw.NewEngine(tldw.Config{}, tldw.Dependencies{
+		Video: video, Store: store, AI: &aiStub{}, Prompts: &promptStub{},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+	ref, err := tldw.ParseVideoRef(testVideoID)
+	if err != nil {
+		t.Fatalf("ParseVideoRef() error = %v", err)
+	}
+
+	got, err := engine.Transcript(context.Background(), ref, tldw.TranscriptRequest{Language: "de"})
+	if err != nil {
+		t.Fatalf("Transcript() error = %v", err)
+	}
+	if got.PlainText() != "hallo welt" || !slices.Equal(video.captionLangs, []string{"de-DE"}) {
+		t.Fatalf("Transcript() = %q using languages %v, want the German track", got.PlainText(), video.captionLangs)
+	}
+
+	_, err = engine.Transcript(context.Background(), ref, tldw.TranscriptRequest{Language: "ja"})
+	if !errors.Is(err, tldw.ErrCaptionsUnavailable) {
+		t.Fatalf("Transcript() error = %v, want unavailable Japanese captions", err)
+	}
+	video.captions = &tldw.Transcript{Language: "en", Source: tldw.TranscriptSourceCaptions, Text: "hello world"}
+	_, err = engine.Transcript(context.Background(), ref, tldw.TranscriptRequest{Language: "fr"})
+	if !errors.Is(err, tldw.ErrCaptionsUnavailable) {
+		t.Fatalf("Transcript() error = %v, want an English fallback to be rejected", err)
+	}
+}
+
+func TestEngineServesDefaultLanguageAfterLanguageRequest(t *testing.T) {
+	video := &videoStub{
+		metadata: &tldw.VideoMetadata{HasCaptions: true, Language: "en", CaptionLanguages: []string{"de-DE", "en"}},
+		captions: &tldw.Transcript{Language: "de-DE", Source: tldw.TranscriptSourceCaptions, Text: "hallo welt"},
+	}
+	store := &memoryStore{}
+	engine, err := tldw.NewEngine(tldw.Config{}, tldw.Dependencies{
+		Video: video, Store: store, AI: &aiStub{}, Prompts: &promptStub{},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+	ref, err := tldw.ParseVideoRef(testVideoID)
+	if err != nil {
+		t.Fatalf("ParseVideoRef() error = %v", err)
+	}
+
+	german := tldw.TranscriptRequest{Language: "de"}
+	if _, err := engine.SummarizeVideo(context.Background(), ref, tldw.SummaryRequest{Transcript: german}); err != nil {
+		t.Fatalf("SummarizeVideo() error = %v", err)
+	}
+	if store.transcriptSaves != 0 || len(store.summaries) != 0 {
+		t.Fatalf("German request cached %d transcripts and %d summaries, want none", store.transcriptSaves, len(store.summaries))
+	}
+
+	video.captions = &tldw.Transcript{Language: "en", Source: tldw.TranscriptSourceCaptions, Text: "hello world"}
+	got, err := engine.Transcript(context.Background(), ref, tldw.TranscriptRequest{})
+	if err != nil {
+		t.Fatalf("Transcript() error = %v", err)
+	}
+	if got.PlainText() != "hello world" || store.transcriptSaves != 1 {
+		t.Fatalf("Transcript() = %q with %d saves, want the cached English transcript", got.PlainText(), store.transcriptSaves)
+	}
 }
 
 func TestEngineReturnsUnexpectedStoreFailure(t *testing.T) {
@@ -260,8 +347,9 @@ func TestEngineSummarizeVideoReturnsTransportNeutralMarkdown(t *testing.T) {
 		captions: &tldw.Transcript{Source: tldw.TranscriptSourceCaptions, Text: "source transcript"},
 	}
 	prompts := &promptStub{prompt: "prompt"}
+	store := &memoryStore{}
 	engine, err := tldw.NewEngine(tldw.Config{}, tldw.Dependencies{
-		Video: video, Store: &memoryStore{}, AI: &aiStub{summary: "## Raw summary"}, Prompts: prompts,
+		Video: video, Store: store, AI: &aiStub{summary: "## Raw summary"}, Prompts: prompts,
 	})
 	if err != nil {
 		t.Fatalf("NewEngine() error = %v", err)
@@ -271,8 +359,8 @@ func TestEngineSummarizeVideoReturnsTransportNeutralMarkdown(t *testing.T) {
 		t.Fatalf("ParseVideoRef() error = %v", err)
 	}
 
-	summary, err := engine.SummarizeVideo(context.Background(), ref, tldw.TranscriptRequest{
-		Policy: tldw.TranscriptPolicyCaptionsOnly,
+	summary, err := engine.SummarizeVideo(context.Background(), ref, tldw.SummaryRequest{
+		Transcript: tldw.TranscriptRequest{Policy: tldw.TranscriptPolicyCaptionsOnly},
 	})
 	if err != nil {
 		t.Fatalf("SummarizeVideo() error = %v", err)
@@ -280,6 +368,83 @@ func TestEngineSummarizeVideoReturnsTransportNeutralMarkdown(t *testing.T) {
 	if summary.Markdown != "## Raw summary" || prompts.transcript != "source transcript" {
 		t.Fatalf("summary = %q, prompt transcript = %q", summary.Markdown, prompts.transcript)
 	}
+	if summary.Model != "default-model" || summary.TranscriptSource != tldw.TranscriptSourceCaptions {
+		t.Fatalf("summary model = %q, source = %q", summary.Model, summary.TranscriptSource)
+	}
+	if cached := store.summaries[testVideoID]; cached.Markdown != "## Raw summary" {
+		t.Fatalf("cached summary = %q, want the generated summary", cached.Markdown)
+	}
+}
+
+func TestEngineReportsProgressThroughWhisperSummary(t *testing.T) {
+	video := &videoStub{metadata: &tldw.VideoMetadata{HasCaptions: false}, audioPath: "audio.mp3"}
+	engine, err := tldw.NewEngine(tldw.Config{}, tldw.Dependencies{
+		Video: video, Store: &memoryStore{}, AI: &aiStub{transcription: "whisper transcript", summary: "summary"}, Prompts: &promptStub{prompt: "prompt"},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+	ref, err := tldw.ParseVideoRef(testVideoID)
+	if err != nil {
+		t.Fatalf("ParseVideoRef() error = %v", err)
+	}
+
+	var stages []tldw.ProgressStage
+	ctx := tldw.WithProgress(context.Background(), func(progress tldw.Progress) {
+		stages = append(stages, progress.Stage)
+	})
+	if _, err := engine.SummarizeVideo(ctx, ref, tldw.SummaryRequest{
+		Transcript: tldw.TranscriptRequest{Policy: tldw.TranscriptPolicyCaptionsThenWhisper},
+	}); err != nil {
+		t.Fatalf("SummarizeVideo() error = %v", err)
+	}
+	want := []tldw.ProgressStage{
+		tldw.ProgressStageMetadata,
+		tldw.ProgressStageAudio,
+		tldw.ProgressStageTranscribing,
+		tldw.ProgressStageSummarizing,
+	}
+	if !slices.Equal(stages, want) {
+		t.Fatalf("progress stages = %v, want %v", stages, want)
+	}
+}
+
+func TestEngineSummarizeVideoAppliesPromptAndModelOverrides(t *testing.T) {
+	video := &videoStub{
+		metadata: &tldw.VideoMetadata{Title: "Example", HasCaptions: true, CaptionLanguages: []string{"en"}},
+		captions: &tldw.Transcript{Source: tldw.TranscriptSourceCaptions, Text: "source transcript"},
+	}
+	prompts := &promptStub{prompt: "configured"}
+	ai := &aiStub{summary: "## Custom"}
+	store := &memoryStore{summaries: map[string]tldw.Summary{testVideoID: {Markdown: "## Canonical"}}}
+	engine, err := tldw.NewEngine(tldw.Config{}, tldw.Dependencies{
+		Video: video, Store: store, AI: ai, Prompts: prompts,
+	})
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+	ref, err := tldw.ParseVideoRef(testVideoID)
+	if err != nil {
+		t.Fatalf("ParseVideoRef() error = %v", err)
+	}
+
+	summary, err := engine.SummarizeVideo(context.Background(), ref, tldw.SummaryRequest{
+		Transcript: tldw.TranscriptRequest{Policy: tldw.TranscriptPolicyCaptionsOnly},
+		Prompt:     "Bullet points: {{.Transcript}}",
+		Model:      "gpt-5-mini",
+	})
+	if err != nil {
+		t.Fatalf("SummarizeVideo() error = %v", err)
+	}
+	if prompts.template != "Bullet points: {{.Transcript}}" || prompts.transcript != "source transcript" {
+		t.Fatalf("custom prompt template = %q, transcript = %q", prompts.template, prompts.transcript)
+	}
+	if ai.summaryModel != "gpt-5-mini" || summary.Model != "gpt-5-mini" {
+		t.Fatalf("summary model = %q, adapter model = %q, want gpt-5-mini", summary.Model, ai.summaryModel)
+	}
+	if cached := store.summaries[testVideoID]; cached.Markdown != "## Canonical" {
+		t.Fatalf("cached summary = %q, want the canonical summary kept", cached.Markdown)
+	}
 }
 
 func TestEngineSummarizePlaylistReturnsTransportNeutralResult(t *testing.T) {
@@ -314,6 +479,41 @@ func TestEngineSummarizePlaylistReturnsTransportNeutralResult(t *testing.T) {
 	}
 }
 
+func TestEnginePlaylistListsEntriesWithoutTranscripts(t *testing.T) {
+	videoRef, err := tldw.ParseVideoRef(testVideoID)
+	if err != nil {
+		t.Fatalf("ParseVideoRef() error = %v", err)
+	}
+	playlistRef, err := tldw.ParseReference("PLSE8ODhjZXjYDBpQnSymaectKjxCy6BYq")
+	if err != nil {
+		t.Fatalf("ParseReference() error = %v", err)
+	}
+	video := &videoStub{playlist: &tldw.PlaylistInfo{Title: "Examples", Videos: []tldw.YouTubeRef{videoRef}}}
+	engine, err := tldw.NewEngine(tldw.Config{}, tldw.Dependencies{
+		Video: video, Store: &memoryStore{}, AI: &aiStub{}, Prompts: &promptStub{},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+
+	playlist, err := engine.Playlist(context.Background(), playlistRef)
+	if err != nil {
+		t.Fatalf("Playlist() error = %v", err)
+	}
+	if playlist.Title != "Examples" || len(playlist.Entries) != 1 || playlist.Entries[0].Ref.ID() != testVideoID {
+		t.Fatalf("Playlist() = %+v, want one entry for each video", playlist)
+	}
+	if video.playlist.Entries != nil {
+		t.Fatal("Playlist() modified the adapter's listing")
+	}
+	if video.captionCalls != 0 || video.audioCalls != 0 {
+		t.Fatalf("Playlist() fetched captions %d times and audio %d times", video.captionCalls, video.audioCalls)
+	}
+	if _, err := engine.Playlist(context.Background(), videoRef); err == nil {
+		t.Fatal("Playlist() accepted a video reference")
+	}
+}
+
 func TestEngineSummarizePlaylistUsesWhisperAfterConsent(t *testing.T) {
 	videoRef, err := tldw.ParseVideoRef(testVideoID)
 	if err != nil {
@@ -0,0 +1,236 @@
+package tldw
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LibraryFile is one on-disk file that belongs to a cached video.
+type LibraryFile struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// LibraryEntry describes everything the local store holds for one video. The
+// transcript, plain-text, metadata, and summary files of a video are handled
+// together.
+type LibraryEntry struct {
+	VideoID          string           `json:"video_id"`
+	Metadata         *VideoMetadata   `json:"metadata,omitempty"`
+	HasTranscript    bool             `json:"has_transcript"`
+	HasTimestamps    bool             `json:"has_timestamps"`
+	HasSummary       bool             `json:"has_summary"`
+	TranscriptSource TranscriptSource `json:"transcript_source,omitempty"`
+	FirstSeenAt      time.Time        `json:"first_seen_at"`
+	CachedAt         time.Time        `json:"cached_at"`
+	SummarizedAt     time.Time        `json:"summarized_at,omitzero"`
+	Files            []LibraryFile    `json:"files"`
+}
+
+// Size returns the combined size of the entry's files in bytes.
+func (entry LibraryEntry) Size() int64 {
+	var total int64
+	for _, file := range entry.Files {
+		total += file.Size
+	}
+	return total
+}
+
+// Title returns the cached title, or an empty string without metadata.
+func (entry LibraryEntry) Title() string {
+	if entry.Metadata == nil {
+		return ""
+	}
+	return entry.Metadata.Title
+}
+
+// Channel returns the cached channel name, or an empty string without metadata.
+func (entry LibraryEntry) Channel() string {
+	if entry.Metadata == nil {
+		return ""
+	}
+	return entry.Metadata.Channel
+}
+
+// Duration returns the cached runtime in seconds, or zero without metadata.
+func (entry LibraryEntry) Duration() float64 {
+	if entry.Metadata == nil {
+		return 0
+	}
+	return entry.Metadata.Duration
+}
+
+// LibrarySort controls the order of a library listing.
+type LibrarySort string
+
+const (
+	// LibrarySortSeen lists the most recently added videos first.
+	LibrarySortSeen LibrarySort = "seen"
+	// LibrarySortTitle lists videos alphabetically by title.
+	LibrarySortTitle LibrarySort = "title"
+	// LibrarySortChannel lists videos alphabetically by channel, then title.
+	LibrarySortChannel LibrarySort = "channel"
+	// LibrarySortDuration lists the longest videos first.
+	LibrarySortDuration LibrarySort = "duration"
+	// LibrarySortSize lists the entries using the most disk space first.
+	LibrarySortSize LibrarySort = "size"
+)
+
+// LibraryQuery filters and orders library entries. Text filters match
+// case-insensitively; zero values leave the corresponding filter open.
+type LibraryQuery struct {
+	Channel string
+	Search  string
+	Source  TranscriptSource
+	SortBy  LibrarySort
+	Reverse bool
+	Limit   int
+}
+
+// Library lists cached videos that match the query.
+func (app *Engine) Library(query LibraryQuery) ([]LibraryEntry, error) {
+	if err := validateLibraryQuery(query); err != nil {
+		return nil, err
+	}
+	entries, err := app.store.ListEntries()
+	if err != nil {
+		return nil, fmt.Errorf("listing library: %w", err)
+	}
+
+	matching := make([]LibraryEntry, 0, len(entries))
+	for _, entry := range entries {
+		if libraryEntryMatches(entry, query) {
+			matching = append(matching, entry)
+		}
+	}
+	sortLibraryEntries(matching, query.SortBy, query.Reverse)
+	if query.Limit > 0 && len(matching) > query.Limit {
+		matching = matching[:query.Limit]
+	}
+	return matching, nil
+}
+
+// LibraryEntry loads one cached video from the local library.
+func (app *Engine) LibraryEntry(ref YouTubeRef) (*LibraryEntry, error) {
+	if !validVideoRef(ref) {
+		return nil, fmt.Errorf("library lookup requires a valid video reference")
+	}
+	entry, err := app.store.LoadEntry(ref.ID())
+	if err != nil {
+		return nil, fmt.Errorf("loading library entry: %w", err)
+	}
+	return entry, nil
+}
+
+// RemoveFromLibrary deletes every cached file for a video.
+func (app *Engine) RemoveFromLibrary(ref YouTubeRef) error {
+	if !validVideoRef(ref) {
+		return fmt.Errorf("library removal requires a valid video reference")
+	}
+	app.forgetCachedMetadata(ref.ID())
+	if err := app.store.DeleteEntry(ref.ID()); err != nil {
+		return fmt.Errorf("removing library entry: %w", err)
+	}
+	return nil
+}
+
+// PruneLibrary removes entries whose cache was last written before cutoff and
+// returns them. With dryRun set, the matching entries are only reported.
+func (app *Engine) PruneLibrary(cutoff time.Time, dryRun bool) ([]LibraryEntry, error) {
+	if cutoff.IsZero() {
+		return nil, fmt.Errorf("prune cutoff is required")
+	}
+	entries, err := app.store.ListEntries()
+	if err != nil {
+		return nil, fmt.Errorf("listing library: %w", err)
+	}
+
+	var pruned []LibraryEntry
+	var errs []error
+	for _, entry := range entries {
+		if !entry.CachedAt.Before(cutoff) {
+			continue
+		}
+		if !dryRun {
+			app.forgetCachedMetadata(entry.VideoID)
+			if err := app.store.DeleteEntry(entry.VideoID); err != nil && !errors.Is(err, ErrStoreNotFound) {
+				errs = append(errs, fmt.Errorf("removing %s: %w", entry.VideoID, err))
+				continue
+			}
+		}
+		pruned = append(pruned, entry)
+	}
+	sortLibraryEntries(pruned, LibrarySortSeen, true)
+	return pruned, errors.Join(errs...)
+}
+
+func validateLibraryQuery(query LibraryQuery) error {
+	switch query.SortBy {
+	case "", LibrarySortSeen, LibrarySortTitle, LibrarySortChannel, LibrarySortDuration, LibrarySortSize:
+	default:
+		return fmt.Errorf("unsupported library sort: %q", query.SortBy)
+	}
+	switch query.Source {
+	case "", TranscriptSourceCaptions, TranscriptSourceWhisper:
+	default:
+		return fmt.Errorf("unsupported transcript source: %q", query.Source)
+	}
+	if query.Limit < 0 {
+		return fmt.Errorf("library limit must not be negative")
+	}
+	return nil
+}
+
+func libraryEntryMatches(entry LibraryEntry, query LibraryQuery) bool {
+	if query.Source != "" && entry.TranscriptSource != query.Source {
+		return false
+	}
+	if channel := strings.TrimSpace(query.Channel); channel != "" &&
+		!strings.Contains(strings.ToLower(entry.Channel()), strings.ToLower(channel)) {
+		return false
+	}
+	search := strings.ToLower(strings.TrimSpace(query.Search))
+	if search == "" {
+		return true
+	}
+	if strings.Contains(strings.ToLower(entry.VideoID), search) || strings.Contains(strings.ToLower(entry.Title()), search) {
+		return true
+	}
+	if entry.Metadata != nil {
+		for _, tag := range entry.Metadata.Tags {
+			if strings.Contains(strings.ToLower(tag), search) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sortLibraryEntries(entries []LibraryEntry, by LibrarySort, reverse bool) {
+	less := func(a, b LibraryEntry) bool {
+		switch by {
+		case LibrarySortTitle:
+			return strings.ToLower(a.Title()) < strings.ToLower(b.Title())
+		case LibrarySortChannel:
+			if !strings.EqualFold(a.Channel(), b.Channel()) {
+				return strings.ToLower(a.Channel()) < strings.ToLower(b.Channel())
+			}
+			return strings.ToLower(a.Title()) < strings.ToLower(b.Title())
+		case LibrarySortDuration:
+			return a.Duration() > b.Duration()
+		case LibrarySortSize:
+			return a.Size() > b.Size()
+		default:
+			return a.FirstSeenAt.After(b.FirstSeenAt)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if reverse {
+			return less(entries[j], entries[i])
+		}
+		return less(entries[i], entries[j])
+	})
+}
@@ -0,0 +1,86 @@
+package tldw_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+func TestEngineLibraryFiltersAndSortsEntries(t *testing.T) {
+	store := &memoryStore{libraryEntries: []tldw.LibraryEntry{
+		{VideoID: "aaaaaaaaaaa", Metadata: &tldw.VideoMetadata{Title: "Postgres internals", Channel: "CMU Database Group", Duration: 3600}, TranscriptSource: tldw.TranscriptSourceCaptions, FirstSeenAt: time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC)},
+		{VideoID: "bbbbbbbbbbb", Metadata: &tldw.VideoMetadata{Title: "B-trees", Channel: "CMU Database Group", Duration: 1800, Tags: []string{"postgres"}}, TranscriptSource: tldw.TranscriptSourceWhisper, FirstSeenAt: time.Date(2026, time.July, 3, 0, 0, 0, 0, time.UTC)},
+		{VideoID: "ccccccccccc", Metadata: &tldw.VideoMetadata{Title: "Go generics", Channel: "GopherCon", Duration: 2400}, TranscriptSource: tldw.TranscriptSourceCaptions, FirstSeenAt: time.Date(2026, time.July, 2, 0, 0, 0, 0, time.UTC)},
+	}}
+	engine, err := tldw.NewEngine(tldw.Config{}, tldw.Dependencies{
+		Video: &videoStub{}, Store: store, AI: &aiStub{}, Prompts: &promptStub{},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query tldw.LibraryQuery
+		want  []string
+	}{
+		{name: "newest first by default", want: []string{"bbbbbbbbbbb", "ccccccccccc", "aaaaaaaaaaa"}},
+		{name: "channel filter", query: tldw.LibraryQuery{Channel: "cmu"}, want: []string{"bbbbbbbbbbb", "aaaaaaaaaaa"}},
+		{name: "search matches titles and tags", query: tldw.LibraryQuery{Search: "POSTGRES", SortBy: tldw.LibrarySortTitle}, want: []string{"bbbbbbbbbbb", "aaaaaaaaaaa"}},
+		{name: "source filter", query: tldw.LibraryQuery{Source: tldw.TranscriptSourceCaptions}, want: []string{"ccccccccccc", "aaaaaaaaaaa"}},
+		{name: "longest first", query: tldw.LibraryQuery{SortBy: tldw.LibrarySortDuration}, want: []string{"aaaaaaaaaaa", "ccccccccccc", "bbbbbbbbbbb"}},
+		{name: "reverse with limit", query: tldw.LibraryQuery{SortBy: tldw.LibrarySortDuration, Reverse: true, Limit: 2}, want: []string{"bbbbbbbbbbb", "ccccccccccc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := engine.Library(tt.query)
+			if err != nil {
+				t.Fatalf("Library() error = %v", err)
+			}
+			got := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				got = append(got, entry.VideoID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("Library() IDs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := engine.Library(tldw.LibraryQuery{SortBy: "rating"}); err == nil {
+		t.Fatal("Library() accepted an unknown sort order")
+	}
+}
+
+func TestEnginePruneLibraryRemovesEntriesCachedBeforeCutoff(t *testing.T) {
+	cutoff := time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)
+	store := &memoryStore{libraryEntries: []tldw.LibraryEntry{
+		{VideoID: "aaaaaaaaaaa", CachedAt: cutoff.Add(-time.Hour)},
+		{VideoID: "bbbbbbbbbbb", CachedAt: cutoff},
+		{VideoID: "ccccccccccc", CachedAt: cutoff.AddDate(0, -1, 0)},
+	}}
+	engine, err := tldw.NewEngine(tldw.Config{}, tldw.Dependencies{
+		Video: &videoStub{}, Store: store, AI: &aiStub{}, Prompts: &promptStub{},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+
+	planned, err := engine.PruneLibrary(cutoff, true)
+	if err != nil {
+		t.Fatalf("PruneLibrary(dry run) error = %v", err)
+	}
+	if len(planned) != 2 || len(store.deletedEntries) != 0 {
+		t.Fatalf("PruneLibrary(dry run) = %+v, deleted %v", planned, store.deletedEntries)
+	}
+
+	pruned, err := engine.PruneLibrary(cutoff, false)
+	if err != nil {
+		t.Fatalf("PruneLibrary() error = %v", err)
+	}
+	if len(pruned) != 2 || !slices.Equal(store.deletedEntries, []string{"aaaaaaaaaaa", "ccccccccccc"}) {
+		t.Fatalf("PruneLibrary() = %+v, deleted %v", pruned, store.deletedEntries)
+	}
+}
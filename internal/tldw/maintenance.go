@@ -0,0 +1,92 @@
+package tldw
+
+import (
+	"context"
+	"fmt"
+)
+
+// StoreIssueKind classifies a problem found by a store check.
+type StoreIssueKind string
+
+const (
+	// StoreIssueOrphanedTemp is a temporary file left behind by an interrupted write.
+	StoreIssueOrphanedTemp StoreIssueKind = "orphaned-temp"
+	// StoreIssueCorruptMetadata is a metadata file that cannot be parsed.
+	StoreIssueCorruptMetadata StoreIssueKind = "corrupt-metadata"
+	// StoreIssueStaleMetadata is metadata written by an older cache version.
+	StoreIssueStaleMetadata StoreIssueKind = "stale-metadata"
+	// StoreIssueCorruptTranscript is a structured transcript that cannot be parsed.
+	StoreIssueCorruptTranscript StoreIssueKind = "corrupt-transcript"
+	// StoreIssueLegacyTranscript is a plain-text transcript without structured JSON.
+	StoreIssueLegacyTranscript StoreIssueKind = "legacy-transcript"
+	// StoreIssueCatalogDrift is a library index that no longer matches the
+	// entry files, e.g. after a crash between writing a file and indexing it.
+	StoreIssueCatalogDrift StoreIssueKind = "catalog-drift"
+)
+
+// StoreIssue describes one problem in the local store. VideoID is empty for
+// files that do not belong to a video.
+type StoreIssue struct {
+	Kind    StoreIssueKind
+	VideoID string
+	File    string
+	Detail  string
+}
+
+// StoreChange records how a migration resolved an issue. Err is set when the
+// issue could not be resolved.
+type StoreChange struct {
+	Issue  StoreIssue
+	Action string
+	Err    error
+}
+
+// CheckStore reports integrity problems in the local store without changing it.
+func (app *Engine) CheckStore() ([]StoreIssue, error) {
+	issues, err := app.store.Check()
+	if err != nil {
+		return nil, fmt.Errorf("checking store: %w", err)
+	}
+	return issues, nil
+}
+
+// MigrateStore resolves every issue CheckStore reports. Corrupt and stale
+// metadata is fetched again; everything else is repaired by the store. Issues
+// that cannot be resolved are reported in their change rather than stopping
+// the migration.
+func (app *Engine) MigrateStore(ctx context.Context) ([]StoreChange, error) {
+	issues, err := app.CheckStore()
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]StoreChange, 0, len(issues))
+	for _, issue := range issues {
+		if err := ctx.Err(); err != nil {
+			return changes, err
+		}
+		change := StoreChange{Issue: issue}
+		switch issue.Kind {
+		case StoreIssueCorruptMetadata, StoreIssueStaleMetadata:
+			change.Action, change.Err = app.refetchMetadata(ctx, issue.VideoID)
+		default:
+			change.Action, change.Err = app.store.Repair(issue)
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func (app *Engine) refetchMetadata(ctx context.Context, videoID string) (string, error) {
+	if !IsValidVideoID(videoID) {
+		return "", fmt.Errorf("invalid YouTube video ID: %q", videoID)
+	}
+	metadata, err := app.video.FetchMetadata(ctx, videoRef(videoID))
+	if err != nil {
+		return "", fmt.Errorf("refetching metadata: %w", err)
+	}
+	app.forgetCachedMetadata(videoID)
+	if err := app.store.SaveMetadata(videoID, metadata); err != nil {
+		return "", fmt.Errorf("saving metadata: %w", err)
+	}
+	return "refetched metadata", nil
+}
@@ -0,0 +1,42 @@
+package tldw_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+func TestEngineMigrateStoreRefetchesMetadataAndDelegatesRepairs(t *testing.T) {
+	store := &memoryStore{issues: []tldw.StoreIssue{
+		{Kind: tldw.StoreIssueStaleMetadata, VideoID: "dQw4w9WgXcQ", File: "dQw4w9WgXcQ.meta.json"},
+		{Kind: tldw.StoreIssueLegacyTranscript, VideoID: "dQw4w9WgXcQ", File: "dQw4w9WgXcQ.txt"},
+		{Kind: tldw.StoreIssueOrphanedTemp, File: ".tldw-123"},
+	}}
+	video := &videoStub{metadata: &tldw.VideoMetadata{Title: "Fresh", Channel: "Channel"}}
+	engine, err := tldw.NewEngine(tldw.Config{}, tldw.Dependencies{
+		Video: video, Store: store, AI: &aiStub{}, Prompts: &promptStub{},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+
+	changes, err := engine.MigrateStore(context.Background())
+	if err != nil {
+		t.Fatalf("MigrateStore() error = %v", err)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("MigrateStore() returned %d changes, want 3: %+v", len(changes), changes)
+	}
+	for _, change := range changes {
+		if change.Err != nil || change.Action == "" {
+			t.Fatalf("MigrateStore() change = %+v, want a successful action", change)
+		}
+	}
+	if video.metadataCalls != 1 || store.metadataSaves != 1 || store.metadata.Title != "Fresh" {
+		t.Fatalf("MigrateStore() metadata calls = %d saves = %d, want one refetch", video.metadataCalls, store.metadataSaves)
+	}
+	if len(store.repaired) != 2 || store.repaired[0].Kind != tldw.StoreIssueLegacyTranscript {
+		t.Fatalf("MigrateStore() delegated repairs = %+v", store.repaired)
+	}
+}
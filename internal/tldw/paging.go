@@ -0,0 +1,161 @@
+package tldw
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrTranscriptWindowInvalid indicates a transcript window that cannot select
+// any content, such as an end before its start.
+var ErrTranscriptWindowInvalid = errors.New("invalid transcript window")
+
+// TranscriptWindow selects part of a transcript. Start and End are in seconds
+// and match segments by their start time; End zero means the end of the video.
+// MaxChars bounds the rendered text, zero meaning unlimited. Offset is the
+// position returned as TranscriptPage.Next by the previous page.
+type TranscriptWindow struct {
+	Start    float64
+	End      float64
+	MaxChars int
+	Offset   int
+}
+
+// TranscriptPage is one rendered slice of a transcript.
+type TranscriptPage struct {
+	Text string
+	// Start and End are the covered time range in seconds. Both are zero for
+	// transcripts without timestamps.
+	Start float64
+	End   float64
+	// Next is the offset of the following page, or zero when this page
+	// reaches the end of the window.
+	Next int
+	// TotalChars is the length of the whole transcript in the same format.
+	TotalChars int
+}
+
+// IsTimed reports whether the window restricts the time range.
+func (w TranscriptWindow) IsTimed() bool {
+	return w.Start > 0 || w.End > 0
+}
+
+// transcriptUnit is the smallest piece a page can hold: a segment, or a word
+// of a transcript without timestamps.
+type transcriptUnit struct {
+	separator string
+	text      string
+	segment   *TranscriptSegment
+}
+
+// Page renders the part of the transcript selected by window. Pages are
+// built from whole segments, so the same window and offset always return the
+// same page. A page holds at least one unit even if it exceeds MaxChars, so
+// paging always makes progress.
+func (t *Transcript) Page(format TranscriptRenderFormat, window TranscriptWindow) (TranscriptPage, error) {
+	if window.Start < 0 || window.End < 0 || window.MaxChars < 0 || window.Offset < 0 {
+		return TranscriptPage{}, fmt.Errorf("%w: values must not be negative", ErrTranscriptWindowInvalid)
+	}
+	if window.End > 0 && window.End <= window.Start {
+		return TranscriptPage{}, fmt.Errorf("%w: end must be after start", ErrTranscriptWindowInvalid)
+	}
+	if window.IsTimed() && !t.HasTimestamps() {
+		return TranscriptPage{}, ErrTranscriptTimestampsUnavailable
+	}
+	full, err := t.Render(format)
+	if err != nil {
+		return TranscriptPage{}, err
+	}
+	if !window.IsTimed() && window.MaxChars == 0 && window.Offset == 0 {
+		page := TranscriptPage{Text: full, TotalChars: utf8.RuneCountInString(full)}
+		if t.HasTimestamps() {
+			page.Start, page.End = t.timeRange()
+		}
+		return page, nil
+	}
+	units, err := t.units(format)
+	if err != nil {
+		return TranscriptPage{}, err
+	}
+	if window.Offset > len(units) {
+		return TranscriptPage{}, fmt.Errorf("%w: offset %d is past the end of the transcript", ErrTranscriptWindowInvalid, window.Offset)
+	}
+
+	page := TranscriptPage{TotalChars: utf8.RuneCountInString(full)}
+	var sb strings.Builder
+	chars := 0
+	var first, last *TranscriptSegment
+	for i := window.Offset; i < len(units); i++ {
+		unit := units[i]
+		if segment := unit.segment; segment != nil {
+			if segment.Start < window.Start {
+				continue
+			}
+			if window.End > 0 && segment.Start >= window.End {
+				break
+			}
+		}
+		piece := unit.text
+		if sb.Len() > 0 {
+			piece = unit.separator + piece
+		}
+		pieceChars := utf8.RuneCountInString(piece)
+		if window.MaxChars > 0 && sb.Len() > 0 && chars+pieceChars > window.MaxChars {
+			page.Next = i
+			break
+		}
+		sb.WriteString(piece)
+		chars += pieceChars
+		if unit.segment != nil {
+			if first == nil {
+				first = unit.segment
+			}
+			last = unit.segment
+		}
+	}
+	if first != nil {
+		page.Start, page.End = first.Start, segmentEnd(*last)
+	}
+	page.Text = sb.String()
+	return page, nil
+}
+
+func (t *Transcript) units(format TranscriptRenderFormat) ([]transcriptUnit, error) {
+	if t.HasTimestamps() {
+		units := make([]transcriptUnit, 0, len(t.Segments))
+		for i := range t.Segments {
+			segment := &t.Segments[i]
+			text := strings.TrimSpace(segment.Text)
+			if text == "" {
+				continue
+			}
+			if format == TranscriptRenderFormatTimestamps {
+				text = fmt.Sprintf("[%s] %s", formatTranscriptTimestamp(segment.Start), text)
+			}
+			units = append(units, transcriptUnit{separator: "\n", text: text, segment: segment})
+		}
+		return units, nil
+	}
+	if format == TranscriptRenderFormatTimestamps {
+		return nil, ErrTranscriptTimestampsUnavailable
+	}
+
+	var units []transcriptUnit
+	for _, line := range strings.Split(strings.TrimSpace(t.Text), "\n") {
+		separator := "\n"
+		for _, word := range strings.Fields(line) {
+			units = append(units, transcriptUnit{separator: separator, text: word})
+			separator = " "
+		}
+	}
+	return units, nil
+}
+
+func (t *Transcript) timeRange() (start, end float64) {
+	return t.Segments[0].Start, segmentEnd(t.Segments[len(t.Segments)-1])
+}
+
+func segmentEnd(segment TranscriptSegment) float64 {
+	return max(segment.End, segment.Start)
+}
@@ -0,0 +1,126 @@
+package tldw
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func pagingTestTranscript() *Transcript {
+	return &Transcript{Segments: []TranscriptSegment{
+		{Start: 0, End: 4, Text: "intro"},
+		{Start: 4, End: 9, Text: "first topic"},
+		{Start: 9, End: 15, Text: "  "},
+		{Start: 15, End: 21, Text: "second topic"},
+		{Start: 3600, End: 3605, Text: "late"},
+	}}
+}
+
+func TestTranscriptPageWithoutWindowMatchesRender(t *testing.T) {
+	transcript := pagingTestTranscript()
+	want, err := transcript.Render(TranscriptRenderFormatTimestamps)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	page, err := transcript.Page(TranscriptRenderFormatTimestamps, TranscriptWindow{})
+	if err != nil {
+		t.Fatalf("Page() error = %v", err)
+	}
+	if page.Text != want || page.Next != 0 || page.TotalChars != len(want) {
+		t.Fatalf("Page() = %+v, want the full render", page)
+	}
+	if page.Start != 0 || page.End != 3605 {
+		t.Fatalf("Page() time range = %v-%v, want 0-3605", page.Start, page.End)
+	}
+}
+
+func TestTranscriptPageFollowsCursorAcrossPages(t *testing.T) {
+	transcript := pagingTestTranscript()
+	window := TranscriptWindow{MaxChars: 20}
+
+	var texts []string
+	for {
+		page, err := transcript.Page(TranscriptRenderFormatPlain, window)
+		if err != nil {
+			t.Fatalf("Page(offset %d) error = %v", window.Offset, err)
+		}
+		texts = append(texts, page.Text)
+		if page.Next == 0 {
+			break
+		}
+		window.Offset = page.Next
+	}
+
+	want := []string{"intro\nfirst topic", "second topic\nlate"}
+	if strings.Join(texts, "|") != strings.Join(want, "|") {
+		t.Fatalf("pages = %q, want %q", texts, want)
+	}
+	plain, _ := transcript.Render(TranscriptRenderFormatPlain)
+	if strings.Join(texts, "\n") != plain {
+		t.Fatalf("joined pages = %q, want the full transcript %q", strings.Join(texts, "\n"), plain)
+	}
+}
+
+func TestTranscriptPageSelectsTimeWindow(t *testing.T) {
+	page, err := pagingTestTranscript().Page(TranscriptRenderFormatTimestamps, TranscriptWindow{Start: 4, End: 3600})
+	if err != nil {
+		t.Fatalf("Page() error = %v", err)
+	}
+	if page.Text != "[00:04] first topic\n[00:15] second topic" {
+		t.Fatalf("Page() text = %q", page.Text)
+	}
+	if page.Start != 4 || page.End != 21 || page.Next != 0 {
+		t.Fatalf("Page() = %+v, want 4-21 without a next page", page)
+	}
+}
+
+func TestTranscriptPageKeepsOversizedSegment(t *testing.T) {
+	page, err := pagingTestTranscript().Page(TranscriptRenderFormatPlain, TranscriptWindow{MaxChars: 3})
+	if err != nil {
+		t.Fatalf("Page() error = %v", err)
+	}
+	if page.Text != "intro" || page.Next != 1 {
+		t.Fatalf("Page() = %+v, want one whole segment and a next page", page)
+	}
+}
+
+func TestTranscriptPageSplitsUntimedTextAtWords(t *testing.T) {
+	transcript := &Transcript{Source: TranscriptSourceWhisper, Text: "one two three\nfour five"}
+
+	first, err := transcript.Page(TranscriptRenderFormatPlain, TranscriptWindow{MaxChars: 10})
+	if err != nil {
+		t.Fatalf("Page() error = %v", err)
+	}
+	second, err := transcript.Page(TranscriptRenderFormatPlain, TranscriptWindow{MaxChars: 10, Offset: first.Next})
+	if err != nil {
+		t.Fatalf("Page(next) error = %v", err)
+	}
+	if first.Text != "one two" || second.Text != "three\nfour" || second.Next == 0 {
+		t.Fatalf("pages = %q (next %d), %q (next %d)", first.Text, first.Next, second.Text, second.Next)
+	}
+	if first.Start != 0 || first.End != 0 {
+		t.Fatalf("untimed page time range = %v-%v, want zero", first.Start, first.End)
+	}
+}
+
+func TestTranscriptPageRejectsInvalidWindows(t *testing.T) {
+	tests := []struct {
+		name       string
+		transcript *Transcript
+		window     TranscriptWindow
+		want       error
+	}{
+		{"end before start", pagingTestTranscript(), TranscriptWindow{Start: 10, End: 5}, ErrTranscriptWindowInvalid},
+		{"negative", pagingTestTranscript(), TranscriptWindow{MaxChars: -1}, ErrTranscriptWindowInvalid},
+		{"offset past end", pagingTestTranscript(), TranscriptWindow{Offset: 99, MaxChars: 10}, ErrTranscriptWindowInvalid},
+		{"time window without timestamps", &Transcript{Text: "words"}, TranscriptWindow{Start: 5}, ErrTranscriptTimestampsUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.transcript.Page(TranscriptRenderFormatPlain, tt.window); !errors.Is(err, tt.want) {
+				t.Fatalf("Page() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package tldw
+
+import "context"
+
+// ProgressStage names a step of a long-running workflow.
+type ProgressStage string
+
+const (
+	ProgressStageMetadata     ProgressStage = "metadata"
+	ProgressStageCaptions     ProgressStage = "captions"
+	ProgressStageAudio        ProgressStage = "audio"
+	ProgressStageTranscribing ProgressStage = "transcribing"
+	ProgressStageSummarizing  ProgressStage = "summarizing"
+)
+
+// Progress describes one step of a workflow. Current and Total count units
+// within the stage, such as transcribed audio chunks, and are zero when the
+// stage has no natural unit.
+type Progress struct {
+	Stage   ProgressStage
+	Message string
+	Current int
+	Total   int
+}
+
+// ProgressFunc receives progress events. It is called synchronously from the
+// workflow, so it should return quickly.
+type ProgressFunc func(Progress)
+
+type progressKey struct{}
+
+// WithProgress returns a context whose workflows report progress to fn.
+// Progress travels with the context so adapters such as the Whisper client can
+// report their own steps without widening the adapter interfaces.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	if fn == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// ReportProgress sends a progress event to the context's ProgressFunc, if any.
+func ReportProgress(ctx context.Context, progress Progress) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok {
+		fn(progress)
+	}
+}
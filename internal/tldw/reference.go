@@ -47,6 +47,17 @@ func (ref YouTubeRef) IsPlaylist() bool  { return ref.kind == ContentTypePlaylis
 type PlaylistInfo struct {
 	Title  string
 	Videos []YouTubeRef
+	// Entries describes each of Videos, in the same order, when the listing
+	// reports titles and durations. It may be empty.
+	Entries []PlaylistEntry
+}
+
+// PlaylistEntry is what a playlist listing reports about one video, without
+// fetching the video's own metadata.
+type PlaylistEntry struct {
+	Ref      YouTubeRef
+	Title    string
+	Duration float64
 }
 
 var (
@@ -116,6 +127,23 @@ func ParseVideoRef(input string) (YouTubeRef, error) {
 	return ref, nil
 }
 
+// ParseChannelRef validates a channel ID, @handle, or supported YouTube channel
+// URL. Unlike ParseReference, an explicit @handle is always read as a channel.
+func ParseChannelRef(input string) (YouTubeRef, error) {
+	original := strings.TrimSpace(input)
+	if strings.HasPrefix(original, "@") && channelHandlePattern.MatchString(original) {
+		return channelRef(original, "https://www.youtube.com/"+original), nil
+	}
+	ref, err := ParseReference(original)
+	if err != nil {
+		return YouTubeRef{}, err
+	}
+	if ref.Kind() != ContentTypeChannel {
+		return YouTubeRef{}, fmt.Errorf("expected a YouTube channel, got %s", ref.Kind())
+	}
+	return ref, nil
+}
+
 func parseReferenceURL(original string) (YouTubeRef, error) {
 	parsed, err := url.Parse(original)
 	if err != nil {
@@ -31,6 +31,24 @@ func TestParseVideoRefRejectsNonVideoInput(t *testing.T) {
 	}
 }
 
+func TestParseChannelRefAcceptsExplicitHandles(t *testing.T) {
+	for input, want := range map[string]string{
+		"@CMUDatabaseGroup":                         "https://www.youtube.com/@CMUDatabaseGroup",
+		"https://www.youtube.com/@CMUDatabaseGroup": "https://www.youtube.com/@CMUDatabaseGroup",
+		"UCHnBsf2rH-K7pn09rb3qvkA":                  "https://www.youtube.com/channel/UCHnBsf2rH-K7pn09rb3qvkA",
+	} {
+		ref, err := tldw.ParseChannelRef(input)
+		if err != nil || ref.URL() != want {
+			t.Fatalf("ParseChannelRef(%q) = %+v, %v, want %s", input, ref, err, want)
+		}
+	}
+	for _, input := range []string{"dQw4w9WgXcQ", "@a", "CMUDatabaseGroup"} {
+		if _, err := tldw.ParseChannelRef(input); err == nil {
+			t.Fatalf("ParseChannelRef(%q) succeeded", input)
+		}
+	}
+}
+
 func TestParseReferenceNormalizesSupportedContent(t *testing.T) {
 	tests := []struct {
 		name     string
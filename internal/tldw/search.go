@@ -0,0 +1,212 @@
+package tldw
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// TranscriptSearchQuery searches the text of cached transcripts. Text matches
+// case-insensitively as a phrase. From, To, and Channel filter videos like
+// StatsQuery and LibraryQuery; zero values leave them open.
+type TranscriptSearchQuery struct {
+	Text    string
+	Channel string
+	From    time.Time
+	To      time.Time
+	// Limit caps the number of videos returned; zero returns all.
+	Limit int
+	// SnippetsPerVideo caps the snippets returned per video; zero returns all.
+	SnippetsPerVideo int
+}
+
+// TranscriptSnippet is one match in a transcript. Start is the caption
+// time in seconds when Timed is set; Whisper transcripts have no timestamps.
+type TranscriptSnippet struct {
+	Start float64 `json:"start,omitempty"`
+	Timed bool    `json:"timed"`
+	Text  string  `json:"text"`
+}
+
+// TranscriptSearchResult is a cached video whose transcript matched.
+type TranscriptSearchResult struct {
+	VideoID     string              `json:"video_id"`
+	Title       string              `json:"title,omitempty"`
+	Channel     string              `json:"channel,omitempty"`
+	FirstSeenAt time.Time           `json:"first_seen_at"`
+	Matches     int                 `json:"matches"`
+	Snippets    []TranscriptSnippet `json:"snippets"`
+}
+
+// snippetContext is how many bytes around a match in an untimed
+// transcript are returned with it.
+const snippetContext = 80
+
+// SearchTranscripts finds cached transcripts containing the query text. Videos
+// with the most matches come first, then the most recently seen.
+func (app *Engine) SearchTranscripts(query TranscriptSearchQuery) ([]TranscriptSearchResult, error) {
+	needle := strings.ToLower(strings.Join(strings.Fields(query.Text), " "))
+	if needle == "" {
+		return nil, fmt.Errorf("search text is required")
+	}
+	if query.Limit < 0 || query.SnippetsPerVideo < 0 {
+		return nil, fmt.Errorf("search limits must not be negative")
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return nil, fmt.Errorf("search start time must be before end time")
+	}
+	entries, err := app.store.ListEntries()
+	if err != nil {
+		return nil, fmt.Errorf("listing library: %w", err)
+	}
+
+	var results []TranscriptSearchResult
+	for _, entry := range entries {
+		if !entry.HasTranscript || !searchEntryMatches(entry, query) {
+			continue
+		}
+		transcript, err := app.store.LoadTranscript(entry.VideoID)
+		if errors.Is(err, ErrStoreNotFound) || errors.Is(err, ErrStoreStale) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("loading transcript %s: %w", entry.VideoID, err)
+		}
+		snippets := searchTranscript(transcript, needle)
+		if len(snippets) == 0 {
+			continue
+		}
+		result := TranscriptSearchResult{
+			VideoID:     entry.VideoID,
+			Title:       entry.Title(),
+			Channel:     entry.Channel(),
+			FirstSeenAt: entry.FirstSeenAt,
+			Matches:     len(snippets),
+			Snippets:    snippets,
+		}
+		if query.SnippetsPerVideo > 0 && len(result.Snippets) > query.SnippetsPerVideo {
+			result.Snippets = result.Snippets[:query.SnippetsPerVideo]
+		}
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Matches != results[j].Matches {
+			return results[i].Matches > results[j].Matches
+		}
+		return results[i].FirstSeenAt.After(results[j].FirstSeenAt)
+	})
+	if query.Limit > 0 && len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+	return results, nil
+}
+
+func searchEntryMatches(entry LibraryEntry, query TranscriptSearchQuery) bool {
+	if (!query.From.IsZero() && entry.FirstSeenAt.Before(query.From)) ||
+		(!query.To.IsZero() && !entry.FirstSeenAt.Before(query.To)) {
+		return false
+	}
+	channel := strings.TrimSpace(query.Channel)
+	return channel == "" || strings.Contains(strings.ToLower(entry.Channel()), strings.ToLower(channel))
+}
+
+// searchTranscript returns one snippet per caption line a match starts in,
+// or one per occurrence in a transcript without timestamps.
+func searchTranscript(transcript *Transcript, needle string) []TranscriptSnippet {
+	if transcript.HasTimestamps() {
+		return searchSegments(transcript.Segments, needle)
+	}
+
+	var snippets []TranscriptSnippet
+	text := strings.Join(strings.Fields(transcript.Text), " ")
+	lower := strings.ToLower(text)
+	// Snippets are cut from the original text when lowercasing kept byte
+	// offsets intact, which holds for nearly all transcripts.
+	source := text
+	if len(lower) != len(text) {
+		source = lower
+	}
+	for offset := 0; ; {
+		index := strings.Index(lower[offset:], needle)
+		if index < 0 {
+			break
+		}
+		match := offset + index
+		start := runeBoundary(source, max(0, match-snippetContext))
+		end := runeBoundary(source, min(len(source), match+len(needle)+snippetContext))
+		snippet := strings.TrimSpace(source[start:end])
+		if start > 0 {
+			snippet = "…" + snippet
+		}
+		if end < len(source) {
+			snippet += "…"
+		}
+		snippets = append(snippets, TranscriptSnippet{Text: snippet})
+		offset = match + len(needle)
+	}
+	return snippets
+}
+
+// searchSegments matches the phrase in the caption lines joined into one
+// text, so a phrase split across lines is found. Each match is reported at the
+// line it starts in, with the text of every line it covers.
+func searchSegments(segments []TranscriptSegment, needle string) []TranscriptSnippet {
+	var (
+		texts   []string
+		starts  []float64
+		offsets []int // offset of each line in lower
+		lower   strings.Builder
+	)
+	for _, segment := range segments {
+		text := strings.Join(strings.Fields(segment.Text), " ")
+		if text == "" {
+			continue
+		}
+		if lower.Len() > 0 {
+			lower.WriteByte(' ')
+		}
+		texts = append(texts, text)
+		starts = append(starts, segment.Start)
+		offsets = append(offsets, lower.Len())
+		lower.WriteString(strings.ToLower(text))
+	}
+	joined := lower.String()
+	// segmentAt returns the line containing the byte at offset.
+	segmentAt := func(offset int) int {
+		return sort.Search(len(offsets), func(i int) bool { return offsets[i] > offset }) - 1
+	}
+
+	var snippets []TranscriptSnippet
+	last := -1
+	for offset := 0; ; {
+		index := strings.Index(joined[offset:], needle)
+		if index < 0 {
+			break
+		}
+		match := offset + index
+		offset = match + len(needle)
+		first := segmentAt(match)
+		if first == last {
+			continue
+		}
+		last = first
+		snippets = append(snippets, TranscriptSnippet{
+			Start: starts[first],
+			Timed: true,
+			Text:  strings.Join(texts[first:segmentAt(offset-1)+1], " "),
+		})
+	}
+	return snippets
+}
+
+// runeBoundary moves a byte offset back to the start of the rune containing it.
+func runeBoundary(text string, offset int) int {
+	for offset > 0 && offset < len(text) && !utf8.RuneStart(text[offset]) {
+		offset--
+	}
+	return offset
+}
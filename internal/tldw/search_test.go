@@ -0,0 +1,104 @@
+package tldw_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+func TestEngineSearchTranscriptsReturnsTimestampedHits(t *testing.T) {
+	june := time.Date(2026, time.June, 10, 0, 0, 0, 0, time.UTC)
+	july := time.Date(2026, time.July, 2, 0, 0, 0, 0, time.UTC)
+	store := &memoryStore{
+		libraryEntries: []tldw.LibraryEntry{
+			{VideoID: "aaaaaaaaaaa", HasTranscript: true, Metadata: &tldw.VideoMetadata{Title: "Postgres internals", Channel: "CMU Database Group"}, FirstSeenAt: june},
+			{VideoID: "bbbbbbbbbbb", HasTranscript: true, Metadata: &tldw.VideoMetadata{Title: "Scaling talk", Channel: "GopherCon"}, FirstSeenAt: july},
+			{VideoID: "ccccccccccc", HasTranscript: true, Metadata: &tldw.VideoMetadata{Title: "Go generics", Channel: "GopherCon"}, FirstSeenAt: july},
+			{VideoID: "ddddddddddd", Metadata: &tldw.VideoMetadata{Title: "Metadata only"}, FirstSeenAt: july},
+		},
+		transcripts: map[string]*tldw.Transcript{
+			"aaaaaaaaaaa": {Source: tldw.TranscriptSourceCaptions, Segments: []tldw.TranscriptSegment{
+				{Start: 12, Text: "Postgres   stores tuples in pages"},
+				{Start: 95, Text: "vacuum reclaims space"},
+				{Start: 300, Text: "why postgres uses MVCC"},
+			}},
+			"bbbbbbbbbbb": {Source: tldw.TranscriptSourceWhisper, Text: "We moved everything to Postgres and it was fine."},
+			"ccccccccccc": {Source: tldw.TranscriptSourceCaptions, Segments: []tldw.TranscriptSegment{{Start: 1, Text: "type parameters"}}},
+		},
+	}
+	engine, err := tldw.NewEngine(tldw.Config{}, tldw.Dependencies{
+		Video: &videoStub{}, Store: store, AI: &aiStub{}, Prompts: &promptStub{},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+
+	results, err := engine.SearchTranscripts(tldw.TranscriptSearchQuery{Text: "POSTGRES"})
+	if err != nil {
+		t.Fatalf("SearchTranscripts() error = %v", err)
+	}
+	var ids []string
+	for _, result := range results {
+		ids = append(ids, result.VideoID)
+	}
+	if !slices.Equal(ids, []string{"aaaaaaaaaaa", "bbbbbbbbbbb"}) {
+		t.Fatalf("SearchTranscripts() videos = %v, want most matches first", ids)
+	}
+	want := []tldw.TranscriptSnippet{
+		{Start: 12, Timed: true, Text: "Postgres stores tuples in pages"},
+		{Start: 300, Timed: true, Text: "why postgres uses MVCC"},
+	}
+	if results[0].Matches != 2 || !slices.Equal(results[0].Snippets, want) {
+		t.Fatalf("caption hits = %+v", results[0])
+	}
+	if got := results[1].Snippets; len(got) != 1 || got[0].Timed || got[0].Text != "We moved everything to Postgres and it was fine." {
+		t.Fatalf("Whisper hits = %+v", got)
+	}
+
+	filtered, err := engine.SearchTranscripts(tldw.TranscriptSearchQuery{
+		Text: "postgres", From: time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC), SnippetsPerVideo: 1,
+	})
+	if err != nil {
+		t.Fatalf("SearchTranscripts(filtered) error = %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].VideoID != "bbbbbbbbbbb" {
+		t.Fatalf("SearchTranscripts(from July) = %+v", filtered)
+	}
+
+	if _, err := engine.SearchTranscripts(tldw.TranscriptSearchQuery{Text: "  "}); err == nil {
+		t.Fatal("SearchTranscripts() accepted empty text")
+	}
+}
+
+func TestEngineSearchTranscriptsFindsPhraseAcrossCaptionLines(t *testing.T) {
+	store := &memoryStore{
+		libraryEntries: []tldw.LibraryEntry{{VideoID: "aaaaaaaaaaa", HasTranscript: true}},
+		transcripts: map[string]*tldw.Transcript{
+			"aaaaaaaaaaa": {Source: tldw.TranscriptSourceCaptions, Segments: []tldw.TranscriptSegment{
+				{Start: 40, Text: "so the write-ahead"},
+				{Start: 43, Text: "  log is flushed first"},
+				{Start: 90, Text: "the write-ahead log again"},
+			}},
+		},
+	}
+	engine, err := tldw.NewEngine(tldw.Config{}, tldw.Dependencies{
+		Video: &videoStub{}, Store: store, AI: &aiStub{}, Prompts: &promptStub{},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+
+	results, err := engine.SearchTranscripts(tldw.TranscriptSearchQuery{Text: "Write-Ahead Log"})
+	if err != nil {
+		t.Fatalf("SearchTranscripts() error = %v", err)
+	}
+	want := []tldw.TranscriptSnippet{
+		{Start: 40, Timed: true, Text: "so the write-ahead log is flushed first"},
+		{Start: 90, Timed: true, Text: "the write-ahead log again"},
+	}
+	if len(results) != 1 || results[0].Matches != 2 || !slices.Equal(results[0].Snippets, want) {
+		t.Fatalf("SearchTranscripts() = %+v, want %+v", results, want)
+	}
+}
@@ -3,6 +3,7 @@ package tldw
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -25,6 +26,15 @@ type StatsQuery struct {
 	Location *time.Location
 }
 
+// StatsPeriod is a named calendar period for a stats report. Zero bounds are
+// open, as in StatsQuery.
+type StatsPeriod struct {
+	Name  string
+	Label string
+	From  time.Time
+	To    time.Time
+}
+
 // StatsBucket is one calendar bucket in a grouped stats report.
 type StatsBucket struct {
 	Label           string  `json:"label"`
@@ -111,3 +121,42 @@ func statsGroupLabel(timestamp time.Time, group StatsGroup, location *time.Locat
 		return ""
 	}
 }
+
+// ResolveStatsPeriod returns the calendar period named today, week, month, or
+// all that contains now, using now's location for day boundaries.
+func ResolveStatsPeriod(name string, now time.Time) (StatsPeriod, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	location := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	switch name {
+	case "today", "day":
+		return StatsPeriod{Name: "today", Label: "today", From: today, To: today.AddDate(0, 0, 1)}, nil
+	case "week":
+		weekdayOffset := (int(today.Weekday()) + 6) % 7
+		from := today.AddDate(0, 0, -weekdayOffset)
+		return StatsPeriod{Name: name, Label: "this week", From: from, To: from.AddDate(0, 0, 7)}, nil
+	case "month":
+		from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location)
+		return StatsPeriod{Name: name, Label: "this month", From: from, To: from.AddDate(0, 1, 0)}, nil
+	case "all":
+		return StatsPeriod{Name: name, Label: "all time"}, nil
+	default:
+		return StatsPeriod{}, fmt.Errorf("unsupported period %q: use today, week, month, or all", name)
+	}
+}
+
+// ParseStatsGroup parses a grouping name; empty and "none" disable grouping.
+func ParseStatsGroup(name string) (StatsGroup, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "none":
+		return StatsGroupNone, nil
+	case "day":
+		return StatsGroupDay, nil
+	case "week":
+		return StatsGroupWeek, nil
+	case "month":
+		return StatsGroupMonth, nil
+	default:
+		return StatsGroupNone, fmt.Errorf("unsupported grouping %q: use day, week, or month", name)
+	}
+}
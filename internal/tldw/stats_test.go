@@ -79,3 +79,43 @@ func TestEngineStatsSupportsWeekAndMonthGrouping(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveStatsPeriodUsesLocalCalendarBoundaries(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+	now := time.Date(2026, time.July, 19, 23, 30, 0, 0, berlin) // Sunday
+	tests := []struct {
+		name     string
+		wantFrom time.Time
+		wantTo   time.Time
+	}{
+		{
+			name:     "today",
+			wantFrom: time.Date(2026, time.July, 19, 0, 0, 0, 0, berlin),
+			wantTo:   time.Date(2026, time.July, 20, 0, 0, 0, 0, berlin),
+		},
+		{
+			name:     "week",
+			wantFrom: time.Date(2026, time.July, 13, 0, 0, 0, 0, berlin),
+			wantTo:   time.Date(2026, time.July, 20, 0, 0, 0, 0, berlin),
+		},
+		{
+			name:     "month",
+			wantFrom: time.Date(2026, time.July, 1, 0, 0, 0, 0, berlin),
+			wantTo:   time.Date(2026, time.August, 1, 0, 0, 0, 0, berlin),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			period, err := tldw.ResolveStatsPeriod(tt.name, now)
+			if err != nil {
+				t.Fatalf("ResolveStatsPeriod() error = %v", err)
+			}
+			if !period.From.Equal(tt.wantFrom) || !period.To.Equal(tt.wantTo) {
+				t.Fatalf("ResolveStatsPeriod() = %v to %v, want %v to %v", period.From, period.To, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package tldw
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// StoredSummary is a cached summary together with the video it describes.
+type StoredSummary struct {
+	VideoID      string
+	Metadata     *VideoMetadata
+	Markdown     string
+	SummarizedAt time.Time
+}
+
+// SummaryQuery filters cached summaries. Channel matches case-insensitively
+// as a substring; Tag must equal one of the video's tags, ignoring case.
+type SummaryQuery struct {
+	Channel string
+	Tag     string
+	Limit   int
+}
+
+// RecentSummaries returns cached summaries matching the query, most recently
+// generated first.
+func (app *Engine) RecentSummaries(query SummaryQuery) ([]StoredSummary, error) {
+	if query.Limit < 0 {
+		return nil, fmt.Errorf("summary limit must not be negative")
+	}
+	entries, err := app.store.ListEntries()
+	if err != nil {
+		return nil, fmt.Errorf("listing library: %w", err)
+	}
+
+	var matching []LibraryEntry
+	for _, entry := range entries {
+		if entry.HasSummary && summaryEntryMatches(entry, query) {
+			matching = append(matching, entry)
+		}
+	}
+	slices.SortStableFunc(matching, func(a, b LibraryEntry) int {
+		if c := b.SummarizedAt.Compare(a.SummarizedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.VideoID, b.VideoID)
+	})
+
+	summaries := make([]StoredSummary, 0, len(matching))
+	for _, entry := range matching {
+		if query.Limit > 0 && len(summaries) == query.Limit {
+			break
+		}
+		summary, err := app.store.LoadSummary(entry.VideoID)
+		if errors.Is(err, ErrStoreNotFound) {
+			// Removed since the listing was taken.
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("loading summary of %s: %w", entry.VideoID, err)
+		}
+		summaries = append(summaries, StoredSummary{
+			VideoID: entry.VideoID, Metadata: entry.Metadata, Markdown: summary.Markdown, SummarizedAt: entry.SummarizedAt,
+		})
+	}
+	return summaries, nil
+}
+
+func summaryEntryMatches(entry LibraryEntry, query SummaryQuery) bool {
+	if channel := strings.TrimSpace(query.Channel); channel != "" &&
+		!strings.Contains(strings.ToLower(entry.Channel()), strings.ToLower(channel)) {
+		return false
+	}
+	tag := strings.TrimSpace(query.Tag)
+	if tag == "" {
+		return true
+	}
+	if entry.Metadata == nil {
+		return false
+	}
+	return slices.ContainsFunc(entry.Metadata.Tags, func(candidate string) bool {
+		return strings.EqualFold(candidate, tag)
+	})
+}
+
+// CachedSummary returns the last summary generated for a video without
+// generating a new one.
+func (app *Engine) CachedSummary(ref YouTubeRef) (*StoredSummary, error) {
+	if !validVideoRef(ref) {
+		return nil, fmt.Errorf("cached summary requires a valid video reference")
+	}
+	summary, err := app.store.LoadSummary(ref.ID())
+	if err != nil {
+		return nil, fmt.Errorf("loading summary: %w", err)
+	}
+	stored := &StoredSummary{VideoID: ref.ID(), Markdown: summary.Markdown}
+	if entry, err := app.store.LoadEntry(ref.ID()); err == nil {
+		stored.Metadata, stored.SummarizedAt = entry.Metadata, entry.SummarizedAt
+	}
+	return stored, nil
+}
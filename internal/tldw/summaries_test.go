@@ -0,0 +1,83 @@
+package tldw_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+func TestEngineRecentSummariesFiltersByChannelAndTag(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, time.July, d, 0, 0, 0, 0, time.UTC) }
+	store := &memoryStore{
+		libraryEntries: []tldw.LibraryEntry{
+			{VideoID: "aaaaaaaaaaa", HasSummary: true, SummarizedAt: day(1), Metadata: &tldw.VideoMetadata{Channel: "CMU Database Group", Tags: []string{"Postgres"}}},
+			{VideoID: "bbbbbbbbbbb", HasSummary: true, SummarizedAt: day(3), Metadata: &tldw.VideoMetadata{Channel: "CMU Database Group"}},
+			{VideoID: "ccccccccccc", HasSummary: true, SummarizedAt: day(2), Metadata: &tldw.VideoMetadata{Channel: "GopherCon", Tags: []string{"go", "postgres"}}},
+			{VideoID: "ddddddddddd", Metadata: &tldw.VideoMetadata{Channel: "GopherCon"}},
+		},
+		summaries: map[string]tldw.Summary{
+			"aaaaaaaaaaa": {Markdown: "a"}, "bbbbbbbbbbb": {Markdown: "b"}, "ccccccccccc": {Markdown: "c"},
+		},
+	}
+	engine, err := tldw.NewEngine(tldw.Config{}, tldw.Dependencies{
+		Video: &videoStub{}, Store: store, AI: &aiStub{}, Prompts: &promptStub{},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query tldw.SummaryQuery
+		want  []string
+	}{
+		{name: "newest first", want: []string{"bbbbbbbbbbb", "ccccccccccc", "aaaaaaaaaaa"}},
+		{name: "channel filter", query: tldw.SummaryQuery{Channel: "cmu"}, want: []string{"bbbbbbbbbbb", "aaaaaaaaaaa"}},
+		{name: "tag filter", query: tldw.SummaryQuery{Tag: "POSTGRES"}, want: []string{"ccccccccccc", "aaaaaaaaaaa"}},
+		{name: "limit", query: tldw.SummaryQuery{Limit: 1}, want: []string{"bbbbbbbbbbb"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summaries, err := engine.RecentSummaries(tt.query)
+			if err != nil {
+				t.Fatalf("RecentSummaries() error = %v", err)
+			}
+			got := make([]string, 0, len(summaries))
+			for _, summary := range summaries {
+				if summary.Markdown != summary.VideoID[:1] {
+					t.Fatalf("RecentSummaries() markdown of %s = %q", summary.VideoID, summary.Markdown)
+				}
+				got = append(got, summary.VideoID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("RecentSummaries() IDs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngineCachedSummaryDoesNotGenerate(t *testing.T) {
+	store := &memoryStore{summaries: map[string]tldw.Summary{testVideoID: {Markdown: "## Cached"}}}
+	engine, err := tldw.NewEngine(tldw.Config{}, tldw.Dependencies{
+		Video: &videoStub{}, Store: store, AI: &aiStub{}, Prompts: &promptStub{},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+	ref, err := tldw.ParseVideoRef(testVideoID)
+	if err != nil {
+		t.Fatalf("ParseVideoRef() error = %v", err)
+	}
+
+	summary, err := engine.CachedSummary(ref)
+	if err != nil || summary.Markdown != "## Cached" {
+		t.Fatalf("CachedSummary() = %+v, %v", summary, err)
+	}
+	delete(store.summaries, testVideoID)
+	if _, err := engine.CachedSummary(ref); !errors.Is(err, tldw.ErrStoreNotFound) {
+		t.Fatalf("CachedSummary() error = %v, want not found", err)
+	}
+}
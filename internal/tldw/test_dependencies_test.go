@@ -2,6 +2,7 @@ package tldw_test
 
 import (
 	"context"
+	"io"
 
 	"github.com/rtzll/tldw/internal/tldw"
 )
@@ -16,6 +17,7 @@ type videoStub struct {
 	audioPath     string
 	metadataCalls int
 	captionCalls  int
+	captionLangs  []string
 	audioCalls    int
 }
 
@@ -24,8 +26,9 @@ func (stub *videoStub) FetchMetadata(context.Context, tldw.YouTubeRef) (*tldw.Vi
 	return stub.metadata, nil
 }
 
-func (stub *videoStub) FetchCaptions(context.Context, tldw.YouTubeRef, []string, string) (*tldw.Transcript, error) {
+func (stub *videoStub) FetchCaptions(_ context.Context, _ tldw.YouTubeRef, preferredLangs []string, _ string) (*tldw.Transcript, error) {
 	stub.captionCalls++
+	stub.captionLangs = preferredLangs
 	return stub.captions, stub.captionsErr
 }
 
@@ -38,23 +41,90 @@ func (stub *videoStub) FetchPlaylist(context.Context, tldw.YouTubeRef) (*tldw.Pl
 	return stub.playlist, nil
 }
 
+func (stub *videoStub) FetchChannelUploads(context.Context, tldw.YouTubeRef, int) (*tldw.PlaylistInfo, error) {
+	return stub.playlist, nil
+}
+
 type memoryStore struct {
 	transcript      *tldw.Transcript
+	transcripts     map[string]*tldw.Transcript
 	transcriptErr   error
 	metadata        *tldw.VideoMetadata
 	metadataEntries []tldw.StoredVideoMetadata
+	libraryEntries  []tldw.LibraryEntry
+	deletedEntries  []string
+	issues          []tldw.StoreIssue
+	repaired        []tldw.StoreIssue
 	transcriptSaves int
 	metadataSaves   int
+	summaries       map[string]tldw.Summary
+	exported        []string
+	imports         []tldw.LibraryImport
 }
 
 func (store *memoryStore) ListMetadata() ([]tldw.StoredVideoMetadata, error) {
 	return store.metadataEntries, nil
 }
 
+func (store *memoryStore) ListEntries() ([]tldw.LibraryEntry, error) {
+	return store.libraryEntries, nil
+}
+
+func (store *memoryStore) LoadEntry(videoID string) (*tldw.LibraryEntry, error) {
+	for _, entry := range store.libraryEntries {
+		if entry.VideoID == videoID {
+			return &entry, nil
+		}
+	}
+	return nil, tldw.ErrStoreNotFound
+}
+
+func (store *memoryStore) DeleteEntry(videoID string) error {
+	store.deletedEntries = append(store.deletedEntries, videoID)
+	return nil
+}
+
+func (store *memoryStore) Check() ([]tldw.StoreIssue, error) {
+	return store.issues, nil
+}
+
+func (store *memoryStore) Repair(issue tldw.StoreIssue) (string, error) {
+	store.repaired = append(store.repaired, issue)
+	return "repaired", nil
+}
+
+func (store *memoryStore) LoadSummary(videoID string) (*tldw.Summary, error) {
+	summary, ok := store.summaries[videoID]
+	if !ok {
+		return nil, tldw.ErrStoreNotFound
+	}
+	return &summary, nil
+}
+
+func (store *memoryStore) SaveSummary(videoID string, summary tldw.Summary) error {
+	if store.summaries == nil {
+		store.summaries = make(map[string]tldw.Summary)
+	}
+	store.summaries[videoID] = summary
+	return nil
+}
+
+func (store *memoryStore) ExportEntries(_ io.Writer, videoIDs []string) error {
+	store.exported = videoIDs
+	return nil
+}
+
+func (store *memoryStore) ImportEntries(io.Reader, tldw.ImportPolicy) ([]tldw.LibraryImport, error) {
+	return store.imports, nil
+}
+
 func (store *memoryStore) LoadTranscript(videoID string) (*tldw.Transcript, error) {
 	if store.transcriptErr != nil {
 		return nil, store.transcriptErr
 	}
+	if transcript, ok := store.transcripts[videoID]; ok {
+		return transcript, nil
+	}
 	if store.transcript == nil {
 		return nil, tldw.ErrStoreNotFound
 	}
@@ -85,6 +155,7 @@ type aiStub struct {
 	summary         string
 	transcribeCalls int
 	sawDeadline     bool
+	summaryModel    string
 }
 
 func (stub *aiStub) Transcribe(ctx context.Context, _ string) (string, error) {
@@ -93,16 +164,41 @@ func (stub *aiStub) Transcribe(ctx context.Context, _ string) (string, error) {
 	return stub.transcription, nil
 }
 
-func (stub *aiStub) Summary(context.Context, string) (string, error) {
+func (stub *aiStub) Summary(_ context.Context, model, _ string) (string, error) {
+	stub.summaryModel = model
 	return stub.summary, nil
 }
 
+func (stub *aiStub) SummaryModel() string {
+	return "default-model"
+}
+
 type promptStub struct {
 	prompt     string
 	transcript string
+	template   string
 }
 
 func (stub *promptStub) CreatePrompt(transcript string, _ *tldw.VideoMetadata) (string, error) {
 	stub.transcript = transcript
 	return stub.prompt, nil
 }
+
+func (stub *promptStub) CreateCustomPrompt(template, transcript string, _ *tldw.VideoMetadata) (string, error) {
+	stub.template = template
+	stub.transcript = transcript
+	return template, nil
+}
+
+// observerStub records cache lookups as "kind hit" or "kind miss".
+type observerStub struct {
+	lookups []string
+}
+
+func (stub *observerStub) CacheLookup(kind string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	stub.lookups = append(stub.lookups, kind+" "+result)
+}
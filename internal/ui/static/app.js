@@ -0,0 +1,251 @@
+"use strict";
+
+// The dashboard is a hash-routed single page over the JSON API. Every value
+// from the API is inserted as text, except summary HTML, which the server
+// renders from Markdown without raw HTML.
+
+const API = "/api/v1";
+const app = document.getElementById("app");
+
+function el(tag, attrs = {}, ...children) {
+  const node = document.createElement(tag);
+  for (const [key, value] of Object.entries(attrs)) {
+    if (value === undefined || value === null || value === false) continue;
+    if (key.startsWith("on")) node.addEventListener(key.slice(2), value);
+    else node.setAttribute(key, value === true ? "" : value);
+  }
+  for (const child of children.flat()) {
+    if (child === undefined || child === null || child === false) continue;
+    node.append(child instanceof Node ? child : document.createTextNode(String(child)));
+  }
+  return node;
+}
+
+// Servers with bearer tokens answer 401; the token is asked for once and kept
+// for this tab only.
+const TOKEN_KEY = "tldw-token";
+
+async function request(path, options = {}, retried = false) {
+  const headers = { "Content-Type": "application/json", ...(options.headers || {}) };
+  const token = sessionStorage.getItem(TOKEN_KEY);
+  if (token) headers.Authorization = `Bearer ${token}`;
+  const response = await fetch(API + path, { ...options, headers });
+  if (response.status === 401 && !retried) {
+    const entered = window.prompt("This server needs a token:");
+    if (entered) {
+      sessionStorage.setItem(TOKEN_KEY, entered.trim());
+      return request(path, options, true);
+    }
+  }
+  const body = await response.json().catch(() => ({}));
+  if (!response.ok) {
+    const error = new Error((body.error && body.error.message) || response.statusText);
+    error.code = body.error && body.error.code;
+    error.status = response.status;
+    throw error;
+  }
+  return body;
+}
+
+function formatDuration(seconds) {
+  return seconds ? formatTimestamp(seconds) : "-";
+}
+
+function formatTimestamp(seconds) {
+  const total = Math.floor(seconds || 0);
+  const h = Math.floor(total / 3600);
+  const m = Math.floor((total % 3600) / 60);
+  const s = total % 60;
+  const pad = (n) => String(n).padStart(2, "0");
+  return h > 0 ? `${h}:${pad(m)}:${pad(s)}` : `${m}:${pad(s)}`;
+}
+
+function formatDate(value) {
+  if (!value) return "-";
+  const date = new Date(value);
+  return Number.isNaN(date.getTime()) ? value : date.toLocaleString();
+}
+
+function videoURL(id, seconds) {
+  const url = `https://www.youtube.com/watch?v=${encodeURIComponent(id)}`;
+  return seconds === undefined ? url : `${url}&t=${Math.floor(seconds)}s`;
+}
+
+function errorBox(error) {
+  return el("p", { class: "error" }, error.message);
+}
+
+function render(...nodes) {
+  app.replaceChildren(...nodes);
+}
+
+// Library
+
+const libraryState = { channel: "", search: "", sort: "seen" };
+
+async function showLibrary() {
+  const list = el("div", {}, el("p", { class: "muted" }, "Loading…"));
+  const input = (name, placeholder) =>
+    el("input", {
+      type: "search", placeholder, value: libraryState[name],
+      oninput: (event) => { libraryState[name] = event.target.value; refresh(); },
+    });
+  const sort = el("select", { onchange: (event) => { libraryState.sort = event.target.value; refresh(); } },
+    ["seen", "title", "channel", "duration", "size"].map((value) =>
+      el("option", { value, selected: value === libraryState.sort }, `Sort by ${value}`)));
+  render(el("h1", {}, "Library"), el("div", { class: "filters" }, input("search", "Search titles and tags"), input("channel", "Channel"), sort), list);
+
+  let generation = 0;
+  async function refresh() {
+    const current = ++generation;
+    const params = new URLSearchParams({ sort: libraryState.sort });
+    if (libraryState.channel) params.set("channel", libraryState.channel);
+    if (libraryState.search) params.set("search", libraryState.search);
+    try {
+      const { entries } = await request(`/library?${params}`);
+      if (current !== generation) return;
+      list.replaceChildren(libraryTable(entries));
+    } catch (error) {
+      if (current === generation) list.replaceChildren(errorBox(error));
+    }
+  }
+  await refresh();
+}
+
+function libraryTable(entries) {
+  if (entries.length === 0) return el("p", { class: "muted" }, "No cached videos match.");
+  return el("table", {},
+    el("thead", {}, el("tr", {}, ["Title", "Channel", "Duration", "Transcript", "Summary", "Added"].map((h) => el("th", {}, h)))),
+    el("tbody", {}, entries.map((entry) => {
+      const metadata = entry.metadata || {};
+      return el("tr", {},
+        el("td", {}, el("a", { href: `#/video/${entry.video_id}` }, metadata.title || entry.video_id)),
+        el("td", {}, metadata.channel || "-"),
+        el("td", { class: "number" }, formatDuration(metadata.duration)),
+        el("td", {}, entry.transcript_source || (entry.has_transcript ? "yes" : "-")),
+        el("td", {}, entry.has_summary ? "yes" : "-"),
+        el("td", {}, formatDate(entry.first_seen_at)));
+    })));
+}
+
+// Video
+
+async function showVideo(id) {
+  const summary = el("section", {}, el("h2", {}, "Summary"), el("p", { class: "muted" }, "Loading…"));
+  const transcript = el("section", {}, el("h2", {}, "Transcript"), el("p", { class: "muted" }, "Loading…"));
+  const header = el("div", {}, el("h1", {}, id));
+  render(el("p", {}, el("a", { href: "#/" }, "← Library")), header, summary, transcript);
+
+  request(`/videos/${id}/metadata`).then(({ metadata }) => {
+    header.replaceChildren(
+      el("h1", {}, metadata.title || id),
+      el("dl", { class: "facts" },
+        el("dt", {}, "Channel"), el("dd", {}, metadata.channel || "-"),
+        el("dt", {}, "Published"), el("dd", {}, metadata.published_at || "-"),
+        el("dt", {}, "Duration"), el("dd", {}, formatDuration(metadata.duration)),
+        el("dt", {}, "Tags"), el("dd", {}, (metadata.tags || []).join(", ") || "-"),
+        el("dt", {}, "Video"), el("dd", {}, el("a", { href: videoURL(id), rel: "noreferrer", target: "_blank" }, videoURL(id)))));
+  }).catch((error) => header.append(errorBox(error)));
+
+  loadSummary(id, summary);
+  loadTranscript(id, transcript);
+}
+
+async function loadSummary(id, section) {
+  const body = el("div", {}, el("p", { class: "muted" }, "Loading…"));
+  const button = el("button", { type: "button" }, "Generate summary");
+  button.addEventListener("click", async () => {
+    if (!confirm("Generating a summary calls OpenAI and costs money. Continue?")) return;
+    button.disabled = true;
+    body.replaceChildren(el("p", { class: "muted" }, "Summarizing… this can take a minute."));
+    try {
+      showSummary(await request(`/videos/${id}/summary`, { method: "POST", body: "{}" }));
+    } catch (error) {
+      body.replaceChildren(errorBox(error));
+    } finally {
+      button.disabled = false;
+    }
+  });
+  section.replaceChildren(el("div", { class: "section-header" }, el("h2", {}, "Summary"), button), body);
+
+  function showSummary(summary) {
+    const content = el("div", { class: "markdown" });
+    content.innerHTML = summary.html;
+    const note = summary.summarized_at ? el("p", { class: "muted" }, `Generated ${formatDate(summary.summarized_at)}`) : null;
+    body.replaceChildren(content, note || "");
+    button.textContent = "Regenerate";
+  }
+
+  try {
+    showSummary(await request(`/videos/${id}/summary`));
+  } catch (error) {
+    body.replaceChildren(error.status === 404 ? el("p", { class: "muted" }, "No summary yet.") : errorBox(error));
+  }
+}
+
+async function loadTranscript(id, section) {
+  try {
+    const transcript = await request(`/videos/${id}/transcript?format=json`);
+    const meta = el("p", { class: "muted" }, [transcript.source, transcript.language].filter(Boolean).join(" · "));
+    let lines;
+    if (transcript.segments && transcript.segments.length > 0) {
+      lines = el("ol", { class: "transcript" }, transcript.segments.map((segment) =>
+        el("li", {},
+          el("a", { class: "timestamp", href: videoURL(id, segment.start), rel: "noreferrer", target: "_blank" }, formatTimestamp(segment.start)),
+          " ", segment.text)));
+    } else {
+      lines = el("pre", { class: "transcript-text" }, transcript.text);
+    }
+    section.replaceChildren(el("h2", {}, "Transcript"), meta, lines);
+  } catch (error) {
+    section.replaceChildren(el("h2", {}, "Transcript"), errorBox(error));
+  }
+}
+
+// Stats
+
+async function showStats(group = "month") {
+  const select = el("select", { onchange: (event) => showStats(event.target.value) },
+    ["day", "week", "month"].map((value) => el("option", { value, selected: value === group }, `Per ${value}`)));
+  const body = el("div", {}, el("p", { class: "muted" }, "Loading…"));
+  render(el("h1", {}, "Stats"), el("div", { class: "filters" }, select), body);
+  try {
+    const report = await request(`/stats?group_by=${group}`);
+    body.replaceChildren(
+      el("p", { class: "totals" },
+        el("strong", {}, String(report.video_count)), " videos · ",
+        el("strong", {}, (report.duration_seconds / 3600).toFixed(1)), " hours"),
+      chart("Videos", report.groups || [], (bucket) => bucket.video_count, (v) => String(v)),
+      chart("Hours", report.groups || [], (bucket) => bucket.duration_seconds / 3600, (v) => v.toFixed(1)));
+  } catch (error) {
+    body.replaceChildren(errorBox(error));
+  }
+}
+
+function chart(title, buckets, value, label) {
+  if (buckets.length === 0) return el("p", { class: "muted" }, "No videos yet.");
+  const max = Math.max(...buckets.map(value), 1);
+  return el("figure", { class: "chart" },
+    el("figcaption", {}, title),
+    el("div", { class: "bars" }, buckets.map((bucket) => {
+      // Set through the CSSOM, which the content security policy allows.
+      const bar = el("span", { class: "bar" });
+      bar.style.width = `${(value(bucket) / max) * 100}%`;
+      return el("div", { class: "bar-row" },
+        el("span", { class: "bar-label" }, bucket.label), bar,
+        el("span", { class: "bar-value" }, label(value(bucket))));
+    })));
+}
+
+// Routing
+
+function route() {
+  const hash = location.hash.replace(/^#/, "") || "/";
+  const video = hash.match(/^\/video\/([A-Za-z0-9_-]{11})$/);
+  if (video) return showVideo(video[1]);
+  if (hash === "/stats") return showStats();
+  return showLibrary();
+}
+
+window.addEventListener("hashchange", route);
+route();
@@ -0,0 +1,36 @@
+package ui_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rtzll/tldw/internal/ui"
+)
+
+func TestHandlerServesSelfContainedDashboard(t *testing.T) {
+	handler := ui.Handler()
+	for _, path := range []string{"/", "/app.js", "/style.css"} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("GET %s status = %d", path, recorder.Code)
+		}
+		if !strings.Contains(recorder.Header().Get("Content-Security-Policy"), "default-src 'self'") {
+			t.Fatalf("GET %s has no content security policy", path)
+		}
+		body := recorder.Body.String()
+		for _, external := range []string{`src="http`, `href="http`, "@import", "cdn."} {
+			if strings.Contains(body, external) {
+				t.Fatalf("GET %s loads an external asset (%q)", path, external)
+			}
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST / status = %d, want %d", recorder.Code, http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,294 @@
+// Package watch keeps a list of YouTube channels and summarizes their new
+// uploads into Markdown files.
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+// DefaultMaxAttempts is how often an upload is tried before its channel
+// moves past it.
+const DefaultMaxAttempts = 3
+
+// Channel is one watched channel. HighWater is the newest upload that has
+// been handled; later runs only consider uploads published after it.
+type Channel struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Backfill  int       `json:"backfill,omitempty"`
+	HighWater string    `json:"high_water,omitempty"`
+	AddedAt   time.Time `json:"added_at"`
+	CheckedAt time.Time `json:"checked_at,omitzero"`
+	// Failures counts the failed attempts of the upload the channel waits on.
+	Failures map[string]int `json:"failures,omitempty"`
+}
+
+// Ref returns the channel reference the list was created from.
+func (channel Channel) Ref() (tldw.YouTubeRef, error) {
+	return tldw.ParseChannelRef(channel.URL)
+}
+
+// List persists watched channels as a JSON file.
+type List struct {
+	Path string
+}
+
+// Load returns every watched channel. A missing file is an empty list.
+func (list List) Load() ([]Channel, error) {
+	data, err := os.ReadFile(filepath.Clean(list.Path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading watchlist: %w", err)
+	}
+	var channels []Channel
+	if err := json.Unmarshal(data, &channels); err != nil {
+		return nil, fmt.Errorf("parsing watchlist: %w", err)
+	}
+	return channels, nil
+}
+
+// Save atomically replaces the watchlist.
+func (list List) Save(channels []Channel) error {
+	if channels == nil {
+		channels = []Channel{}
+	}
+	data, err := json.MarshalIndent(channels, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling watchlist: %w", err)
+	}
+	dir := filepath.Dir(list.Path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating watchlist directory: %w", err)
+	}
+	temp, err := os.CreateTemp(dir, ".tldw-*")
+	if err != nil {
+		return fmt.Errorf("creating temporary watchlist: %w", err)
+	}
+	defer func() { _ = os.Remove(temp.Name()) }()
+	if _, err := temp.Write(data); err != nil {
+		_ = temp.Close()
+		return fmt.Errorf("writing watchlist: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("writing watchlist: %w", err)
+	}
+	if err := os.Rename(temp.Name(), list.Path); err != nil {
+		return fmt.Errorf("replacing watchlist: %w", err)
+	}
+	return nil
+}
+
+// Add watches a channel. New channels start without a high-water mark, so
+// their first run summarizes the backfill most recent uploads.
+func (list List) Add(ref tldw.YouTubeRef, backfill int, now time.Time) (Channel, error) {
+	if ref.Kind() != tldw.ContentTypeChannel {
+		return Channel{}, fmt.Errorf("watch requires a channel reference")
+	}
+	if backfill < 0 {
+		return Channel{}, fmt.Errorf("backfill must not be negative")
+	}
+	channels, err := list.Load()
+	if err != nil {
+		return Channel{}, err
+	}
+	for _, channel := range channels {
+		if strings.EqualFold(channel.ID, ref.ID()) {
+			return Channel{}, fmt.Errorf("already watching %s", ref.ID())
+		}
+	}
+	channel := Channel{ID: ref.ID(), URL: ref.URL(), Backfill: backfill, AddedAt: now}
+	return channel, list.Save(append(channels, channel))
+}
+
+// Remove stops watching a channel.
+func (list List) Remove(ref tldw.YouTubeRef) error {
+	channels, err := list.Load()
+	if err != nil {
+		return err
+	}
+	for i, channel := range channels {
+		if strings.EqualFold(channel.ID, ref.ID()) {
+			return list.Save(append(channels[:i], channels[i+1:]...))
+		}
+	}
+	return fmt.Errorf("not watching %s", ref.ID())
+}
+
+// Application is the part of the engine a watch run needs.
+type Application interface {
+	NewUploads(ctx context.Context, channel tldw.YouTubeRef, since string, limit int) ([]tldw.YouTubeRef, error)
+	SummarizeVideo(ctx context.Context, ref tldw.YouTubeRef, request tldw.SummaryRequest) (tldw.Summary, error)
+	MetadataFor(ctx context.Context, ref tldw.YouTubeRef) (*tldw.VideoMetadata, error)
+}
+
+// Result reports one summarized upload, or a channel or upload that failed.
+type Result struct {
+	Channel string
+	VideoID string
+	Path    string
+	Err     error
+	// Skipped marks a failed upload that will not be retried.
+	Skipped bool
+}
+
+// Runner checks every watched channel and writes a Markdown file per new
+// upload to OutputDir/<channel>/<video-id>.md.
+type Runner struct {
+	App       Application
+	List      List
+	OutputDir string
+	Request   tldw.TranscriptRequest
+	// ScanLimit bounds how many recent uploads are inspected per channel.
+	ScanLimit int
+	// MaxAttempts bounds how often a failing upload is tried; zero means
+	// DefaultMaxAttempts.
+	MaxAttempts int
+	Now         func() time.Time
+}
+
+// Run processes new uploads oldest first and advances each channel's
+// high-water mark after every upload, so an interrupted run resumes where it
+// stopped. A failed upload stops its channel until the next run, unless it
+// failed permanently, e.g. without captions, or MaxAttempts times; then it is
+// skipped so that later uploads are still summarized.
+func (runner Runner) Run(ctx context.Context) ([]Result, error) {
+	if runner.ScanLimit <= 0 {
+		return nil, fmt.Errorf("scan limit must be positive")
+	}
+	channels, err := runner.List.Load()
+	if err != nil {
+		return nil, err
+	}
+	var results []Result
+	for i := range channels {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		channelResults, err := runner.runChannel(ctx, &channels[i], func() error { return runner.List.Save(channels) })
+		results = append(results, channelResults...)
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+func (runner Runner) runChannel(ctx context.Context, channel *Channel, save func() error) ([]Result, error) {
+	ref, err := channel.Ref()
+	if err != nil {
+		return []Result{{Channel: channel.ID, Err: err}}, nil
+	}
+	limit := runner.ScanLimit
+	if channel.HighWater == "" {
+		limit = max(channel.Backfill, 1)
+	}
+	uploads, err := runner.App.NewUploads(ctx, ref, channel.HighWater, limit)
+	if err != nil {
+		return []Result{{Channel: channel.ID, Err: err}}, nil
+	}
+	channel.CheckedAt = runner.Now()
+	if channel.HighWater == "" && channel.Backfill == 0 {
+		// Without a backfill the first run only marks where to start.
+		if len(uploads) > 0 {
+			channel.HighWater = uploads[len(uploads)-1].ID()
+		}
+		return nil, save()
+	}
+
+	var results []Result
+	for _, upload := range uploads {
+		result := Result{Channel: channel.ID, VideoID: upload.ID()}
+		result.Path, result.Err = runner.summarize(ctx, channel.ID, upload)
+		if result.Err != nil && !errors.Is(result.Err, context.Canceled) {
+			result.Skipped = runner.recordFailure(channel, upload.ID(), result.Err)
+		}
+		results = append(results, result)
+		if errors.Is(result.Err, context.Canceled) {
+			return results, result.Err
+		}
+		if result.Err != nil && !result.Skipped {
+			break
+		}
+		delete(channel.Failures, upload.ID())
+		channel.HighWater = upload.ID()
+		if err := save(); err != nil {
+			return results, err
+		}
+	}
+	return results, save()
+}
+
+// recordFailure counts a failed attempt and reports whether the channel should
+// move past the upload.
+func (runner Runner) recordFailure(channel *Channel, videoID string, err error) bool {
+	if permanentFailure(err) {
+		return true
+	}
+	if channel.Failures == nil {
+		channel.Failures = make(map[string]int)
+	}
+	channel.Failures[videoID]++
+	maxAttempts := runner.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	return channel.Failures[videoID] >= maxAttempts
+}
+
+// permanentFailure reports errors that retrying the same request cannot fix.
+func permanentFailure(err error) bool {
+	return errors.Is(err, tldw.ErrCaptionsUnavailable) || errors.Is(err, tldw.ErrTranscriptTimestampsUnavailable)
+}
+
+func (runner Runner) summarize(ctx context.Context, channelID string, upload tldw.YouTubeRef) (string, error) {
+	dir := filepath.Join(runner.OutputDir, strings.TrimPrefix(channelID, "@"))
+	path := filepath.Join(dir, upload.ID()+".md")
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	summary, err := runner.App.SummarizeVideo(ctx, upload, tldw.SummaryRequest{Transcript: runner.Request})
+	if err != nil {
+		return "", err
+	}
+	metadata, err := runner.App.MetadataFor(ctx, upload)
+	if err != nil {
+		metadata = nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating output directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(summaryDocument(upload, metadata, summary)), 0o644); err != nil {
+		return "", fmt.Errorf("writing summary: %w", err)
+	}
+	return path, nil
+}
+
+func summaryDocument(upload tldw.YouTubeRef, metadata *tldw.VideoMetadata, summary tldw.Summary) string {
+	var doc strings.Builder
+	title := upload.ID()
+	if metadata != nil && metadata.Title != "" {
+		title = metadata.Title
+	}
+	fmt.Fprintf(&doc, "# %s\n\n", title)
+	if metadata != nil && metadata.Channel != "" {
+		fmt.Fprintf(&doc, "- Channel: %s\n", metadata.Channel)
+	}
+	if metadata != nil && metadata.PublishedAt != "" {
+		fmt.Fprintf(&doc, "- Published: %s\n", metadata.PublishedAt)
+	}
+	fmt.Fprintf(&doc, "- Video: %s\n\n", upload.URL())
+	doc.WriteString(strings.TrimSpace(summary.Markdown))
+	doc.WriteString("\n")
+	return doc.String()
+}
@@ -0,0 +1,186 @@
+package watch_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rtzll/tldw/internal/tldw"
+	"github.com/rtzll/tldw/internal/watch"
+)
+
+type applicationStub struct {
+	uploads    []string
+	failing    string
+	failErr    error
+	sinces     []string
+	summarized []string
+}
+
+func (stub *applicationStub) NewUploads(_ context.Context, _ tldw.YouTubeRef, since string, limit int) ([]tldw.YouTubeRef, error) {
+	stub.sinces = append(stub.sinces, since)
+	start := 0
+	if index := slices.Index(stub.uploads, since); index >= 0 {
+		start = index + 1
+	} else if len(stub.uploads) > limit {
+		start = len(stub.uploads) - limit
+	}
+	var refs []tldw.YouTubeRef
+	for _, id := range stub.uploads[start:] {
+		ref, err := tldw.ParseVideoRef(id)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+func (stub *applicationStub) SummarizeVideo(_ context.Context, ref tldw.YouTubeRef, _ tldw.SummaryRequest) (tldw.Summary, error) {
+	if ref.ID() == stub.failing {
+		if stub.failErr != nil {
+			return tldw.Summary{}, stub.failErr
+		}
+		return tldw.Summary{}, errors.New("captions are unavailable")
+	}
+	stub.summarized = append(stub.summarized, ref.ID())
+	return tldw.Summary{Markdown: "## Summary of " + ref.ID()}, nil
+}
+
+func (stub *applicationStub) MetadataFor(_ context.Context, ref tldw.YouTubeRef) (*tldw.VideoMetadata, error) {
+	return &tldw.VideoMetadata{Title: "Title " + ref.ID(), Channel: "Example"}, nil
+}
+
+func TestRunnerSummarizesNewUploadsOnceAndAdvancesHighWater(t *testing.T) {
+	dir := t.TempDir()
+	list := watch.List{Path: filepath.Join(dir, "watchlist.json")}
+	channel, err := tldw.ParseChannelRef("@CMUDatabaseGroup")
+	if err != nil {
+		t.Fatalf("ParseChannelRef() error = %v", err)
+	}
+	if _, err := list.Add(channel, 2, time.Now()); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := list.Add(channel, 0, time.Now()); err == nil {
+		t.Fatal("Add() accepted a channel twice")
+	}
+	stub := &applicationStub{uploads: []string{"aaaaaaaaaaa", "bbbbbbbbbbb", "ccccccccccc"}}
+	runner := watch.Runner{App: stub, List: list, OutputDir: filepath.Join(dir, "out"), ScanLimit: 10, Now: time.Now}
+
+	results, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 2 || !slices.Equal(stub.summarized, []string{"bbbbbbbbbbb", "ccccccccccc"}) {
+		t.Fatalf("first Run() = %+v, summarized %v, want the two most recent uploads", results, stub.summarized)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "out", "CMUDatabaseGroup", "ccccccccccc.md"))
+	if err != nil {
+		t.Fatalf("reading summary: %v", err)
+	}
+	for _, fragment := range []string{"# Title ccccccccccc", "- Channel: Example", "## Summary of ccccccccccc"} {
+		if !strings.Contains(string(data), fragment) {
+			t.Fatalf("summary file %q does not contain %q", data, fragment)
+		}
+	}
+
+	stub.uploads = append(stub.uploads, "ddddddddddd", "eeeeeeeeeee")
+	stub.failing = "eeeeeeeeeee"
+	results, err = runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("second Run() error = %v", err)
+	}
+	if len(results) != 2 || results[1].Err == nil || stub.sinces[1] != "ccccccccccc" {
+		t.Fatalf("second Run() = %+v after checking since %v", results, stub.sinces)
+	}
+	channels, err := list.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(channels) != 1 || channels[0].HighWater != "ddddddddddd" || channels[0].CheckedAt.IsZero() {
+		t.Fatalf("Load() = %+v, want high water at the last summarized upload", channels)
+	}
+}
+
+func TestRunnerWithoutBackfillOnlyMarksTheNewestUpload(t *testing.T) {
+	dir := t.TempDir()
+	list := watch.List{Path: filepath.Join(dir, "watchlist.json")}
+	channel, err := tldw.ParseChannelRef("@CMUDatabaseGroup")
+	if err != nil {
+		t.Fatalf("ParseChannelRef() error = %v", err)
+	}
+	if _, err := list.Add(channel, 0, time.Now()); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	stub := &applicationStub{uploads: []string{"aaaaaaaaaaa", "bbbbbbbbbbb"}}
+	runner := watch.Runner{App: stub, List: list, OutputDir: filepath.Join(dir, "out"), ScanLimit: 10, Now: time.Now}
+
+	results, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	channels, err := list.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(results) != 0 || len(stub.summarized) != 0 || channels[0].HighWater != "bbbbbbbbbbb" {
+		t.Fatalf("Run() = %+v, summarized %v, channels %+v", results, stub.summarized, channels)
+	}
+}
+
+func TestRunnerMovesPastUploadsThatKeepFailing(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantRuns int
+	}{
+		{"transient failure", errors.New("HTTP Error 503"), 2},
+		{"permanent failure", tldw.ErrCaptionsUnavailable, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			list := watch.List{Path: filepath.Join(dir, "watchlist.json")}
+			channel, err := tldw.ParseChannelRef("@CMUDatabaseGroup")
+			if err != nil {
+				t.Fatalf("ParseChannelRef() error = %v", err)
+			}
+			if _, err := list.Add(channel, 3, time.Now()); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+			stub := &applicationStub{
+				uploads: []string{"aaaaaaaaaaa", "bbbbbbbbbbb", "ccccccccccc"},
+				failing: "aaaaaaaaaaa",
+				failErr: tt.err,
+			}
+			runner := watch.Runner{
+				App: stub, List: list, OutputDir: filepath.Join(dir, "out"), ScanLimit: 10, MaxAttempts: 2, Now: time.Now,
+			}
+
+			var results []watch.Result
+			for run := 1; run <= tt.wantRuns; run++ {
+				if results, err = runner.Run(context.Background()); err != nil {
+					t.Fatalf("Run() %d error = %v", run, err)
+				}
+				if run < tt.wantRuns && (len(results) != 1 || results[0].Skipped || len(stub.summarized) != 0) {
+					t.Fatalf("Run() %d = %+v, want the failing upload to block the channel", run, results)
+				}
+			}
+			if len(results) != 3 || !results[0].Skipped || !slices.Equal(stub.summarized, []string{"bbbbbbbbbbb", "ccccccccccc"}) {
+				t.Fatalf("Run() = %+v, summarized %v, want later uploads summarized", results, stub.summarized)
+			}
+			channels, err := list.Load()
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if channels[0].HighWater != "ccccccccccc" || len(channels[0].Failures) != 0 {
+				t.Fatalf("Load() = %+v, want high water past the skipped upload", channels)
+			}
+		})
+	}
+}
@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/rtzll/tldw/internal/cache"
+	"github.com/rtzll/tldw/internal/metrics"
+	"github.com/rtzll/tldw/internal/process"
 	"github.com/rtzll/tldw/internal/tldw"
 )
 
@@ -21,8 +25,28 @@ func (yt *YouTube) audio(ctx context.Context, ref tldw.YouTubeRef) (string, erro
 		return "", fmt.Errorf("creating cache directory: %w", err)
 	}
 
-	// Set output path in cache directory
-	outputPath := filepath.Join(cacheDir, "%(id)s.%(ext)s")
+	// Reuse audio from an earlier download, e.g. after a failed transcription
+	outputFile := filepath.Join(cacheDir, ref.ID()+".mp3")
+	cached := cachedAudioValid(outputFile)
+	metrics.CacheLookup("audio", cached)
+	if cached {
+		if yt.verbose && !yt.quiet {
+			yt.log.Printf("Reusing cached audio: %s\n", outputFile)
+		}
+		if err := cache.Touch(outputFile, time.Now()); err != nil {
+			yt.log.Printf("Warning: %v\n", err)
+		}
+		return outputFile, nil
+	}
+
+	// Download into a directory of this run, so a failure removes only what
+	// this run wrote and the finished mp3 appears in the cache in one rename.
+	workDir, err := os.MkdirTemp(cacheDir, ref.ID()+".*"+cache.PartialDirSuffix)
+	if err != nil {
+		return "", fmt.Errorf("creating download directory: %w", err)
+	}
+	defer yt.removeDownloadDir(workDir)
+	outputPath := filepath.Join(workDir, "%(id)s.%(ext)s")
 
 	// Build arguments for yt-dlp command
 	args := []string{
@@ -30,23 +54,75 @@ func (yt *YouTube) audio(ctx context.Context, ref tldw.YouTubeRef) (string, erro
 		"--extract-audio",       // Extract audio from video
 		"--audio-format", "mp3", // Convert to MP3 format
 		"--audio-quality", "10", // Set audio quality (0 is best, 10 is worst)
+	}
+	args = append(args, yt.sleepArgs(0, 0)...)
+	args = append(args, yt.accessArgs()...)
+	args = append(args,
 		"-o", outputPath, // Output to XDG cache directory
 		ref.URL(), // The YouTube URL
-	}
+	)
 
-	output, err := yt.executor.Run(ctx, "yt-dlp", args...)
+	output, err := yt.streamYtDLP(ctx, "audio", reportAudioDownload(ctx), args...)
 	if err != nil {
 		if yt.verbose {
 			yt.log.Printf("Audio download error: %v\n", err)
-			yt.log.Printf("Command output: %s\n", string(output))
+			yt.log.Printf("Command output: %s\n", yt.redactor.Output(output))
 		}
-		return "", fmt.Errorf("yt-dlp failed: %w\nOutput: %s", err, string(output))
+		return "", fmt.Errorf("yt-dlp failed: %w\nOutput: %s", err, yt.redactor.Output(output))
+	}
+	if err := os.Rename(filepath.Join(workDir, ref.ID()+".mp3"), outputFile); err != nil {
+		return "", fmt.Errorf("moving audio into cache: %w", err)
 	}
 	if yt.verbose && !yt.quiet {
 		yt.log.Printf("Audio download completed\n")
 	}
 
+	yt.enforceCachePolicy(outputFile)
+
 	// Return the full path to the downloaded file
-	outputFile := filepath.Join(cacheDir, ref.ID()+".mp3")
 	return outputFile, nil
 }
+
+// reportAudioDownload forwards yt-dlp's download progress to the engine.
+// Updates without a known size carry no percentage and are skipped.
+func reportAudioDownload(ctx context.Context) func(process.Progress) {
+	return func(progress process.Progress) {
+		if progress.Total <= 0 {
+			return
+		}
+		tldw.ReportProgress(ctx, tldw.Progress{
+			Stage:   tldw.ProgressStageAudio,
+			Message: fmt.Sprintf("Downloading audio (%d%%)", progress.Percent()),
+			Current: int(progress.Current),
+			Total:   int(progress.Total),
+		})
+	}
+}
+
+// cachedAudioValid reports whether a finished, non-empty download exists.
+// yt-dlp renames audio into place only after post-processing succeeds.
+func cachedAudioValid(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Size() > 0
+}
+
+// removeDownloadDir deletes the directory of one download with whatever
+// yt-dlp and ffmpeg left in it: .part files, the unconverted source audio,
+// and temporary output.
+func (yt *YouTube) removeDownloadDir(dir string) {
+	if err := os.RemoveAll(dir); err != nil {
+		yt.log.Printf("Warning: removing partial download %s: %v\n", dir, err)
+	}
+}
+
+// enforceCachePolicy evicts old and least recently used cache files while
+// keeping the audio that is about to be transcribed.
+func (yt *YouTube) enforceCachePolicy(keep string) {
+	evicted, err := yt.cache.Enforce(yt.cachePolicy, time.Now(), keep)
+	if err != nil {
+		yt.log.Printf("Warning: enforcing cache limits: %v\n", err)
+	}
+	if len(evicted) > 0 && yt.verbose && !yt.quiet {
+		yt.log.Printf("Evicted %d cached file(s)\n", len(evicted))
+	}
+}
@@ -2,16 +2,51 @@ package ytdlp
 
 import (
 	"context"
+	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/rtzll/tldw/internal/tldw"
 )
 
+// writeDownload returns a run hook that writes files where yt-dlp's -o
+// template puts them, as a download would.
+func writeDownload(t *testing.T, names ...string) func([]string) {
+	return func(args []string) {
+		index := slices.Index(args, "-o")
+		if index < 0 || index+1 >= len(args) {
+			t.Errorf("yt-dlp args = %v, want an -o template", args)
+			return
+		}
+		dir := filepath.Dir(args[index+1])
+		for _, name := range names {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte("audio"), 0o644); err != nil {
+				t.Errorf("WriteFile() error = %v", err)
+			}
+		}
+	}
+}
+
+// cacheNames lists the entries in the cache directory.
+func cacheNames(t *testing.T, cacheDir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
 func TestAudioUsesConfiguredCacheDir(t *testing.T) {
 	cacheDir := filepath.Join(t.TempDir(), "cache")
 	yt := NewYouTube(t.TempDir(), cacheDir, false, true)
-	yt.executor = &mockCommandRunner{}
+	yt.executor = &mockCommandRunner{run: writeDownload(t, "dQw4w9WgXcQ.webm", "dQw4w9WgXcQ.mp3")}
 	ref, err := tldw.ParseVideoRef("dQw4w9WgXcQ")
 	if err != nil {
 		t.Fatalf("ParseVideoRef() error = %v", err)
@@ -26,4 +61,99 @@ func TestAudioUsesConfiguredCacheDir(t *testing.T) {
 	if got != want {
 		t.Fatalf("DownloadAudio() = %q, want %q", got, want)
 	}
+	if names := cacheNames(t, cacheDir); !slices.Equal(names, []string{"dQw4w9WgXcQ.mp3"}) {
+		t.Fatalf("cache after download = %v, want only the mp3", names)
+	}
+}
+
+func TestAudioReusesCachedDownload(t *testing.T) {
+	cacheDir := t.TempDir()
+	cached := filepath.Join(cacheDir, "dQw4w9WgXcQ.mp3")
+	if err := os.WriteFile(cached, []byte("audio"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	lastUsed := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(cached, lastUsed, lastUsed); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+	runner := &mockCommandRunner{}
+	yt := NewYouTube(t.TempDir(), cacheDir, false, true)
+	yt.executor = runner
+	ref, err := tldw.ParseVideoRef("dQw4w9WgXcQ")
+	if err != nil {
+		t.Fatalf("ParseVideoRef() error = %v", err)
+	}
+
+	got, err := yt.DownloadAudio(context.Background(), ref)
+	if err != nil {
+		t.Fatalf("DownloadAudio() error = %v", err)
+	}
+	if got != cached || runner.calls != 0 {
+		t.Fatalf("DownloadAudio() = %q after %d yt-dlp calls, want cached %q without downloading", got, runner.calls, cached)
+	}
+	info, err := os.Stat(cached)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if !info.ModTime().After(lastUsed) {
+		t.Fatalf("cached audio modification time = %v, want it marked as recently used", info.ModTime())
+	}
+}
+
+func TestAudioRemovesOnlyItsOwnPartialDownloadOnFailure(t *testing.T) {
+	cacheDir := t.TempDir()
+	// Files of other runs and captions share the cache directory.
+	kept := []string{"dQw4w9WgXcQ.en.srt", "dQw4w9WgXcQ.m4a", "dQw4w9WgXcQ.webm.part", "otherVideo1.webm.part"}
+	for _, name := range kept {
+		if err := os.WriteFile(filepath.Join(cacheDir, name), []byte("partial"), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	yt := NewYouTube(t.TempDir(), cacheDir, false, true)
+	yt.executor = &mockCommandRunner{
+		err: context.Canceled,
+		run: writeDownload(t, "dQw4w9WgXcQ.webm.part", "dQw4w9WgXcQ.webm", "dQw4w9WgXcQ.temp.mp3"),
+	}
+	ref, err := tldw.ParseVideoRef("dQw4w9WgXcQ")
+	if err != nil {
+		t.Fatalf("ParseVideoRef() error = %v", err)
+	}
+
+	if _, err := yt.DownloadAudio(context.Background(), ref); err == nil {
+		t.Fatal("DownloadAudio() succeeded, want yt-dlp failure")
+	}
+	if left := cacheNames(t, cacheDir); !slices.Equal(left, kept) {
+		t.Fatalf("cache after failed download = %v, want %v", left, kept)
+	}
+}
+
+func TestAudioReportsDownloadProgress(t *testing.T) {
+	yt := NewYouTube(t.TempDir(), t.TempDir(), false, true)
+	runner := &mockCommandRunner{output: []byte("[tldw-progress] downloading 0 NA NA\n" +
+		"[tldw-progress] downloading 512 2048 NA\n" +
+		"[tldw-progress] finished 2048 2048 NA\n"), run: writeDownload(t, "dQw4w9WgXcQ.mp3")}
+	yt.executor = runner
+	ref, err := tldw.ParseVideoRef("dQw4w9WgXcQ")
+	if err != nil {
+		t.Fatalf("ParseVideoRef() error = %v", err)
+	}
+
+	var events []tldw.Progress
+	ctx := tldw.WithProgress(context.Background(), func(progress tldw.Progress) {
+		events = append(events, progress)
+	})
+	if _, err := yt.DownloadAudio(ctx, ref); err != nil {
+		t.Fatalf("DownloadAudio() error = %v", err)
+	}
+
+	want := []tldw.Progress{
+		{Stage: tldw.ProgressStageAudio, Message: "Downloading audio (25%)", Current: 512, Total: 2048},
+		{Stage: tldw.ProgressStageAudio, Message: "Downloading audio (100%)", Current: 2048, Total: 2048},
+	}
+	if !slices.Equal(events, want) {
+		t.Fatalf("progress = %+v, want %+v", events, want)
+	}
+	if !slices.Contains(runner.args, "--newline") {
+		t.Fatalf("yt-dlp args = %v, want --newline for progress lines", runner.args)
+	}
 }
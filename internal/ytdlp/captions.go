@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/rtzll/tldw/internal/process"
 	"github.com/rtzll/tldw/internal/tldw"
 )
 
@@ -127,13 +128,14 @@ func (yt *YouTube) downloadCaptions(ctx context.Context, ref tldw.YouTubeRef, pr
 		"--write-auto-subs", // Enable auto-generated subtitle writing
 		"--sub-langs", primarySubLangs,
 		"--convert-subs", "srt", // Convert subtitles to SRT format
-		"--skip-download",       // Skip downloading the video
-		"--sleep-interval", "2", // Sleep 2-5 seconds between requests to avoid rate limiting
-		"--max-sleep-interval", "5",
-		"--extractor-args", youtubeExtractorPolicy,
+		"--skip-download", // Skip downloading the video
+	}
+	args = append(args, yt.sleepArgs(2, 5)...) // Sleep 2-5 seconds between requests to avoid rate limiting
+	args = append(args, yt.accessArgs()...)
+	args = append(args,
 		"-o", outputPath, // Output to XDG cache directory
 		ref.URL(),
-	}
+	)
 
 	// Run the command (and optional fallback)
 	output, partialFiles, err := yt.runCaptionDownload(ctx, args, pattern)
@@ -157,7 +159,7 @@ func (yt *YouTube) downloadCaptions(ctx context.Context, ref tldw.YouTubeRef, pr
 		if fallbackErr != nil {
 			if yt.verbose {
 				yt.log.Printf("Fallback subtitle download error: %v\n", fallbackErr)
-				yt.log.Printf("Command output: %s\n", string(fallbackOutput))
+				yt.log.Printf("Command output: %s\n", yt.redactor.Output(fallbackOutput))
 			}
 			return fmt.Errorf("%w: %v", tldw.ErrDownloadFailed, fallbackErr)
 		}
@@ -167,12 +169,12 @@ func (yt *YouTube) downloadCaptions(ctx context.Context, ref tldw.YouTubeRef, pr
 	if err != nil {
 		if yt.verbose {
 			yt.log.Printf("Subtitle download error (%s): %v\n", primarySubLangs, err)
-			yt.log.Printf("Command output: %s\n", string(output))
+			yt.log.Printf("Command output: %s\n", yt.redactor.Output(output))
 		}
 
-		// Check if this was a rate limit error - if so, don't retry with more variants
-		if strings.Contains(string(output), "429") || strings.Contains(string(output), "Too Many Requests") {
-			return fmt.Errorf("%w: rate limited", tldw.ErrDownloadFailed)
+		// A rate-limited host would refuse the fallback languages too
+		if process.Classify(err) == process.FailureRateLimited {
+			return fmt.Errorf("%w: %v", tldw.ErrDownloadFailed, err)
 		}
 
 		// Retry with a broader English wildcard when available
@@ -218,7 +220,7 @@ func (yt *YouTube) downloadCaptions(ctx context.Context, ref tldw.YouTubeRef, pr
 }
 
 func (yt *YouTube) runCaptionDownload(ctx context.Context, args []string, pattern string) ([]byte, []string, error) {
-	output, err := yt.executor.Run(ctx, "yt-dlp", args...)
+	output, err := yt.runYtDLP(ctx, "captions", args...)
 	if err == nil {
 		return output, nil, nil
 	}
@@ -0,0 +1,46 @@
+package ytdlp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+// channelUploads lists a channel's most recent uploads, newest first.
+func (yt *YouTube) channelUploads(ctx context.Context, ref tldw.YouTubeRef, limit int) (*tldw.PlaylistInfo, error) {
+	if ref.Kind() != tldw.ContentTypeChannel {
+		return nil, fmt.Errorf("channel uploads require a channel reference")
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("channel upload limit must be positive")
+	}
+	if yt.verbose && !yt.quiet {
+		yt.log.Printf("Listing recent uploads of %s...\n", ref.ID())
+	}
+
+	args := []string{
+		"--flat-playlist",
+		"--dump-single-json",
+		"--playlist-end", strconv.Itoa(limit),
+	}
+	args = append(args, yt.lookupArgs()...)
+	args = append(args, ref.URL()+"/videos")
+
+	output, err := yt.runYtDLP(ctx, "channel_uploads", args...)
+	if err != nil {
+		if yt.verbose {
+			yt.log.Printf("Channel listing error: %v\n", err)
+			yt.log.Printf("Command output: %s\n", yt.redactor.Output(output))
+		}
+		return nil, fmt.Errorf("listing channel uploads: %w", err)
+	}
+
+	var uploads playlistMetadata
+	if err := json.Unmarshal(output, &uploads); err != nil {
+		return nil, fmt.Errorf("parsing channel uploads: %w", err)
+	}
+	return uploads.info(), nil
+}
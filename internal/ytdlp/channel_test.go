@@ -0,0 +1,36 @@
+package ytdlp
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/rtzll/tldw/internal/tldw"
+)
+
+func TestChannelUploadsListsTheVideosTab(t *testing.T) {
+	runner := &mockCommandRunner{output: []byte(`{
+		"title":"CMU Database Group - Videos",
+		"entries":[{"id":"tAP1eZYEuKA"},{"id":"dQw4w9WgXcQ"}]
+	}`)}
+	yt := NewYouTube(t.TempDir(), t.TempDir(), false, true)
+	yt.executor = runner
+	ref, err := tldw.ParseChannelRef("@CMUDatabaseGroup")
+	if err != nil {
+		t.Fatalf("ParseChannelRef() error = %v", err)
+	}
+
+	info, err := yt.FetchChannelUploads(context.Background(), ref, 5)
+	if err != nil {
+		t.Fatalf("FetchChannelUploads() error = %v", err)
+	}
+	if len(info.Videos) != 2 || info.Videos[0].ID() != "tAP1eZYEuKA" {
+		t.Fatalf("FetchChannelUploads() videos = %v", info.Videos)
+	}
+	if runner.args[len(runner.args)-1] != "https://www.youtube.com/@CMUDatabaseGroup/videos" {
+		t.Fatalf("yt-dlp URL = %q, want the channel's videos tab", runner.args[len(runner.args)-1])
+	}
+	if index := slices.Index(runner.args, "--playlist-end"); index < 0 || runner.args[index+1] != "5" {
+		t.Fatalf("yt-dlp args = %v, want --playlist-end 5", runner.args)
+	}
+}
@@ -2,7 +2,10 @@ package ytdlp
 
 import (
 	"context"
+	"time"
 
+	"github.com/rtzll/tldw/internal/cache"
+	"github.com/rtzll/tldw/internal/metrics"
 	"github.com/rtzll/tldw/internal/process"
 	"github.com/rtzll/tldw/internal/tldw"
 )
@@ -21,6 +24,10 @@ type YouTube struct {
 	quiet          bool
 	log            tldw.LogSink
 	executor       process.Runner
+	cache          cache.Dir
+	cachePolicy    cache.Policy
+	options        Options
+	redactor       process.Redactor
 }
 
 // NewYouTube creates a YouTube downloader with explicit storage paths.
@@ -39,6 +46,20 @@ func (yt *YouTube) SetLogSink(log tldw.LogSink) {
 	yt.log = log
 }
 
+// SetRunner replaces the runner for yt-dlp commands, e.g. with a
+// process.RetryRunner.
+func (yt *YouTube) SetRunner(runner process.Runner) {
+	yt.executor = runner
+}
+
+// SetCachePolicy bounds the size and age of downloaded files in the cache
+// directory. dir should name the transcription chunk directory, so chunks
+// inside the cache directory are not mistaken for downloaded audio.
+func (yt *YouTube) SetCachePolicy(dir cache.Dir, policy cache.Policy) {
+	yt.cache = dir
+	yt.cachePolicy = policy
+}
+
 func (yt *YouTube) FetchMetadata(ctx context.Context, ref tldw.YouTubeRef) (*tldw.VideoMetadata, error) {
 	return yt.metadata(ctx, ref)
 }
@@ -55,11 +76,39 @@ func (yt *YouTube) FetchPlaylist(ctx context.Context, ref tldw.YouTubeRef) (*tld
 	return yt.playlistVideoURLs(ctx, ref)
 }
 
-func youtubeLookupArgs() []string {
-	return []string{
-		"--sleep-interval", "1",
-		"--max-sleep-interval", "3",
-		"--extractor-args", youtubeExtractorPolicy,
-		"-q",
-	}
+func (yt *YouTube) FetchChannelUploads(ctx context.Context, ref tldw.YouTubeRef, limit int) (*tldw.PlaylistInfo, error) {
+	return yt.channelUploads(ctx, ref, limit)
+}
+
+// runYtDLP runs yt-dlp and counts the run under operation.
+func (yt *YouTube) runYtDLP(ctx context.Context, operation string, args ...string) ([]byte, error) {
+	return yt.observeYtDLP(operation, func() ([]byte, error) {
+		return yt.executor.Run(ctx, "yt-dlp", args...)
+	})
+}
+
+// streamYtDLP runs yt-dlp like runYtDLP while reporting its download
+// progress.
+func (yt *YouTube) streamYtDLP(ctx context.Context, operation string, report func(process.Progress), args ...string) ([]byte, error) {
+	args = append([]string{"--newline", "--progress-template", process.YtDLPProgressTemplate}, args...)
+	return yt.observeYtDLP(operation, func() ([]byte, error) {
+		return process.Stream(ctx, yt.executor, process.ParseYtDLPProgress, report, "yt-dlp", args...)
+	})
+}
+
+// observeYtDLP counts a yt-dlp run and redacts secrets from its error. The
+// output is returned unchanged because callers parse it; they redact it with
+// yt.redactor before logging it.
+func (yt *YouTube) observeYtDLP(operation string, run func() ([]byte, error)) ([]byte, error) {
+	start := time.Now()
+	output, err := run()
+	metrics.YtDLPInvocations.Inc(operation, metrics.Outcome(err))
+	metrics.YtDLPDuration.Observe(time.Since(start).Seconds(), operation)
+	return output, yt.redactor.Error(err)
+}
+
+func (yt *YouTube) lookupArgs() []string {
+	args := yt.sleepArgs(1, 3)
+	args = append(args, yt.accessArgs()...)
+	return append(args, "-q")
 }
@@ -5,8 +5,17 @@ import "context"
 type mockCommandRunner struct {
 	output []byte
 	err    error
+	calls  int
+	args   []string
+	// run, when set, stands in for the files the command writes.
+	run func(args []string)
 }
 
-func (m *mockCommandRunner) Run(context.Context, string, ...string) ([]byte, error) {
+func (m *mockCommandRunner) Run(_ context.Context, _ string, args ...string) ([]byte, error) {
+	m.calls++
+	m.args = args
+	if m.run != nil {
+		m.run(args)
+	}
 	return m.output, m.err
 }
@@ -20,15 +20,15 @@ func (yt *YouTube) metadata(ctx context.Context, ref tldw.YouTubeRef) (*tldw.Vid
 		"--dump-single-json",
 		"--no-playlist",
 	}
-	args = append(args, youtubeLookupArgs()...)
+	args = append(args, yt.lookupArgs()...)
 	args = append(args, ref.URL())
 
 	// Run the command
-	output, err := yt.executor.Run(ctx, "yt-dlp", args...)
+	output, err := yt.runYtDLP(ctx, "metadata", args...)
 	if err != nil {
 		if yt.verbose {
 			yt.log.Printf("Metadata extraction error: %v\n", err)
-			yt.log.Printf("Command output: %s\n", string(output))
+			yt.log.Printf("Command output: %s\n", yt.redactor.Output(output))
 		}
 		return nil, fmt.Errorf("extracting video metadata: %w", err)
 	}
@@ -9,7 +9,9 @@ import (
 )
 
 type playlistEntry struct {
-	ID string `json:"id"`
+	ID       string  `json:"id"`
+	Title    string  `json:"title"`
+	Duration float64 `json:"duration"`
 }
 
 type playlistMetadata struct {
@@ -17,6 +19,23 @@ type playlistMetadata struct {
 	Entries []playlistEntry `json:"entries"`
 }
 
+// info returns the playlist's valid video entries in order.
+func (playlist playlistMetadata) info() *tldw.PlaylistInfo {
+	info := &tldw.PlaylistInfo{Title: playlist.Title}
+	for _, entry := range playlist.Entries {
+		if !tldw.IsValidVideoID(entry.ID) {
+			continue
+		}
+		ref, err := tldw.ParseVideoRef(entry.ID)
+		if err != nil {
+			continue
+		}
+		info.Videos = append(info.Videos, ref)
+		info.Entries = append(info.Entries, tldw.PlaylistEntry{Ref: ref, Title: entry.Title, Duration: entry.Duration})
+	}
+	return info
+}
+
 func (yt *YouTube) playlistVideoURLs(ctx context.Context, ref tldw.YouTubeRef) (*tldw.PlaylistInfo, error) {
 	if yt.verbose && !yt.quiet {
 		yt.log.Printf("Extracting playlist video URLs...\n")
@@ -27,15 +46,15 @@ func (yt *YouTube) playlistVideoURLs(ctx context.Context, ref tldw.YouTubeRef) (
 		"--flat-playlist",
 		"--dump-single-json",
 	}
-	args = append(args, youtubeLookupArgs()...)
+	args = append(args, yt.lookupArgs()...)
 	args = append(args, ref.URL())
 
 	// Run the command
-	output, err := yt.executor.Run(ctx, "yt-dlp", args...)
+	output, err := yt.runYtDLP(ctx, "playlist", args...)
 	if err != nil {
 		if yt.verbose {
 			yt.log.Printf("Playlist extraction error: %v\n", err)
-			yt.log.Printf("Command output: %s\n", string(output))
+			yt.log.Printf("Command output: %s\n", yt.redactor.Output(output))
 		}
 		return nil, fmt.Errorf("extracting playlist URLs: %w", err)
 	}
@@ -49,23 +68,10 @@ func (yt *YouTube) playlistVideoURLs(ctx context.Context, ref tldw.YouTubeRef) (
 		return nil, fmt.Errorf("parsing playlist metadata: %w", err)
 	}
 
-	// Extract video URLs
-	var videos []tldw.YouTubeRef
-	for _, entry := range playlist.Entries {
-		if tldw.IsValidVideoID(entry.ID) {
-			ref, err := tldw.ParseVideoRef(entry.ID)
-			if err == nil {
-				videos = append(videos, ref)
-			}
-		}
-	}
-
+	info := playlist.info()
 	if yt.verbose && !yt.quiet {
-		yt.log.Printf("Found %d videos in playlist: %s\n", len(videos), playlist.Title)
+		yt.log.Printf("Found %d videos in playlist: %s\n", len(info.Videos), info.Title)
 	}
 
-	return &tldw.PlaylistInfo{
-		Title:  playlist.Title,
-		Videos: videos,
-	}, nil
+	return info, nil
 }
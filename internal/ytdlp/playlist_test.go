@@ -12,7 +12,7 @@ func TestPlaylistVideoURLsSkipsInvalidVideoIDs(t *testing.T) {
 	yt.executor = &mockCommandRunner{output: []byte(`{
 		"title":"Playlist",
 		"entries":[
-			{"id":"dQw4w9WgXcQ","title":"valid"},
+			{"id":"dQw4w9WgXcQ","title":"valid","duration":212.0},
 			{"id":"../../outside","title":"invalid"}
 		]
 	}`)}
@@ -31,4 +31,7 @@ func TestPlaylistVideoURLsSkipsInvalidVideoIDs(t *testing.T) {
 	if info.Videos[0].URL() != "https://www.youtube.com/watch?v=dQw4w9WgXcQ" {
 		t.Fatalf("Videos[0] = %+v", info.Videos[0])
 	}
+	if len(info.Entries) != 1 || info.Entries[0].Title != "valid" || info.Entries[0].Duration != 212 {
+		t.Fatalf("Entries = %+v, want the valid entry with its title and duration", info.Entries)
+	}
 }
@@ -26,12 +26,17 @@ func (yt *YouTube) processSrtTranscript(filePath string) (*tldw.Transcript, erro
 		return nil, fmt.Errorf("reading SRT file: %w", err)
 	}
 
-	// Extract video ID from filename
-	id, _, _ := strings.Cut(filepath.Base(filePath), ".")
+	// Extract video ID and caption language from <id>.<lang>.srt
+	id, rest, _ := strings.Cut(filepath.Base(filePath), ".")
+	language, _, _ := strings.Cut(rest, ".")
+	if language == "srt" {
+		language = ""
+	}
 	segments := parseSRT(string(content))
 	deduplicatedSegments := condenseSubtitleSegments(segments)
 	transcript := &tldw.Transcript{
 		VideoID:  id,
+		Language: language,
 		Source:   tldw.TranscriptSourceCaptions,
 		Segments: deduplicatedSegments,
 	}
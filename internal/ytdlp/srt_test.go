@@ -35,11 +35,15 @@ func TestProcessSRTTranscriptRemovesOnlyCacheFiles(t *testing.T) {
 			}
 
 			yt := NewYouTube(persistentDir, cacheDir, false, true)
-			if _, err := yt.processSrtTranscript(path); err != nil {
+			transcript, err := yt.processSrtTranscript(path)
+			if err != nil {
 				t.Fatalf("processSrtTranscript() error = %v", err)
 			}
+			if transcript.Language != "en" {
+				t.Fatalf("processSrtTranscript() language = %q, want %q", transcript.Language, "en")
+			}
 
-			_, err := os.Stat(path)
+			_, err = os.Stat(path)
 			if tt.wantExists && err != nil {
 				t.Fatalf("persistent transcript was removed: %v", err)
 			}